@@ -0,0 +1,60 @@
+package gsp
+
+import (
+	"time"
+	"unsafe"
+)
+
+// Clock delivers the ticks which drive a clocked [Pipeline].
+// It can be replaced to run a pipeline deterministically, for example in tests.
+type Clock interface {
+	// Ticks returns a channel which delivers a tick every interval, and a function which stops the ticks.
+	Ticks(interval time.Duration) (ticks <-chan time.Time, stop func())
+}
+
+// SystemClock is a [Clock] based on [time.Ticker].
+type SystemClock struct{}
+
+func (SystemClock) Ticks(interval time.Duration) (<-chan time.Time, func()) {
+	ticker := time.NewTicker(interval)
+	return ticker.C, ticker.Stop
+}
+
+// ClockConfig contains the configuration of a clocked [Pipeline].
+type ClockConfig struct {
+	Format    Format // Stream format, the sample rate sets the clock rate and the channel count the shape of silent multi-channel frames.
+	BlockSize int    // Number of frames processed per tick.
+	Clock     Clock  // Source of ticks, a [SystemClock] if nil.
+}
+
+// interval returns the duration of a single block.
+func (c ClockConfig) interval() time.Duration {
+	return c.Format.Duration(c.BlockSize)
+}
+
+// silence returns a buffer of frames holding the zero value of the sample type.
+func silence[F Frame[T], T Type](frames, channels int) []F {
+	buffer := make([]F, frames)
+
+	switch any(*new(F)).(type) {
+	case T:
+		zero := Zero[T]()
+		for i := range buffer {
+			buffer[i] = *(*F)(unsafe.Pointer(&zero))
+		}
+	case [2]T, Stereo[T]:
+		zero := ZeroStereo[T]()
+		for i := range buffer {
+			buffer[i] = *(*F)(unsafe.Pointer(&zero))
+		}
+	case []T, MultiChannel[T]:
+		for i := range buffer {
+			zero := ZeroMultiChannel[T](channels)
+			buffer[i] = *(*F)(unsafe.Pointer(&zero))
+		}
+	default:
+		panic("gsp: silence: unknown audio frame type")
+	}
+
+	return buffer
+}
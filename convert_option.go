@@ -0,0 +1,139 @@
+package gsp
+
+import "sync/atomic"
+
+// ConvertOption is a functional option which configures sample type conversion.
+type ConvertOption func(cfg *ConvertConfig)
+
+// ConvertConfig contains all configuration options for sample type conversion.
+// Dither and noise shaping keep their state across the samples of a [ConvertSlice] call, a [Converter] or a [Ditherer].
+type ConvertConfig struct {
+	Scaling      Scaling       // Scaling between integer and floating-point samples.
+	Overflow     Overflow      // Handling of floating-point samples outside of the integer range.
+	Clipped      *atomic.Int64 // Counter of samples outside of the integer range, if not nil.
+	Dither       Dither        // Dither added before quantizing floating-point samples to integers.
+	NoiseShaping NoiseShaping  // Noise-shaping filter applied to the quantization error.
+	Seed         uint64        // Seed of the dither noise generator.
+	Format       Format        // Format of the converted frames, reported by [Converter.Format].
+
+	quantizer *quantizer // Dither and noise shaping state, if dithered.
+}
+
+// Scaling is the convention which maps integer samples to the floating-point range [-1, 1].
+type Scaling int
+
+const (
+	// ScalingSymmetric scales by the largest positive integer, so full scale is symmetric around zero.
+	// The most negative integer is clipped to -1.
+	ScalingSymmetric Scaling = iota
+	// ScalingAsymmetric scales by 2^(n-1), so the most negative integer maps to exactly -1,
+	// and 1 is clipped to the largest positive integer.
+	ScalingAsymmetric
+)
+
+// Overflow selects how floating-point samples outside of the integer range are converted.
+type Overflow int
+
+const (
+	OverflowClip Overflow = iota // Clip to the integer range.
+	OverflowWrap                 // Wrap around, as with two's complement integer overflow.
+)
+
+// Dither is the probability density function of the dither noise.
+type Dither string
+
+const (
+	DitherNone Dither = ""     // Round to the nearest integer.
+	DitherRPDF Dither = "RPDF" // Rectangular dither of 1 LSB peak-to-peak.
+	DitherTPDF Dither = "TPDF" // Triangular dither of 2 LSB peak-to-peak, which makes the noise floor independent of the signal.
+)
+
+// NoiseShaping selects the error feedback filter, which moves quantization noise to frequencies where it is less audible.
+// The psychoacoustic filters are designed for a sample rate of 44.1 kHz.
+type NoiseShaping string
+
+const (
+	NoiseShapingNone       NoiseShaping = ""            // Flat quantization noise.
+	NoiseShapingFirstOrder NoiseShaping = "first-order" // First-order high-pass error feedback.
+	NoiseShapingLipshitz   NoiseShaping = "Lipshitz"    // Five-tap minimally audible filter by Lipshitz et al.
+	NoiseShapingFWeighted  NoiseShaping = "F-weighted"  // Nine-tap F-weighted filter by Wannamaker.
+)
+
+// ConvertScaling sets the scaling convention between integer and floating-point samples.
+func ConvertScaling(scaling Scaling) ConvertOption {
+	return func(cfg *ConvertConfig) {
+		cfg.Scaling = scaling
+	}
+}
+
+// ConvertOverflow sets the handling of floating-point samples outside of the integer range.
+func ConvertOverflow(overflow Overflow) ConvertOption {
+	return func(cfg *ConvertConfig) {
+		cfg.Overflow = overflow
+	}
+}
+
+// ConvertClipCounter sets a counter which is incremented for every sample outside of the integer range, whether clipped or wrapped.
+func ConvertClipCounter(counter *atomic.Int64) ConvertOption {
+	return func(cfg *ConvertConfig) {
+		cfg.Clipped = counter
+	}
+}
+
+// ConvertDither sets the dither added before quantization.
+func ConvertDither(dither Dither) ConvertOption {
+	return func(cfg *ConvertConfig) {
+		cfg.Dither = dither
+	}
+}
+
+// ConvertNoiseShaping sets the noise-shaping filter.
+func ConvertNoiseShaping(noiseShaping NoiseShaping) ConvertOption {
+	return func(cfg *ConvertConfig) {
+		cfg.NoiseShaping = noiseShaping
+	}
+}
+
+// ConvertSeed sets the seed of the dither noise generator, so dithered output is reproducible.
+func ConvertSeed(seed uint64) ConvertOption {
+	return func(cfg *ConvertConfig) {
+		cfg.Seed = seed
+	}
+}
+
+// ConvertFormat sets the format of the converted frames.
+func ConvertFormat(format Format) ConvertOption {
+	return func(cfg *ConvertConfig) {
+		cfg.Format = format
+	}
+}
+
+func newConvertConfig(opts ...ConvertOption) ConvertConfig {
+	var cfg ConvertConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// dithered reports whether floating-point samples are dithered or noise shaped before quantization.
+func (cfg *ConvertConfig) dithered() bool {
+	return cfg.Dither != DitherNone || cfg.NoiseShaping != NoiseShapingNone
+}
+
+// coefficients returns the error feedback filter, where the first coefficient applies to the previous error.
+func (n NoiseShaping) coefficients() []float64 {
+	switch n {
+	case NoiseShapingNone:
+		return nil
+	case NoiseShapingFirstOrder:
+		return []float64{1}
+	case NoiseShapingLipshitz:
+		return []float64{2.033, -2.165, 1.959, -1.590, 0.6149}
+	case NoiseShapingFWeighted:
+		return []float64{2.412, -3.370, 3.937, -4.174, 3.353, -2.205, 1.281, -0.569, 0.0847}
+	default:
+		panic("gsp: NoiseShaping: filter not implemented")
+	}
+}
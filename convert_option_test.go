@@ -0,0 +1,227 @@
+package gsp
+
+import (
+	"errors"
+	"io"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sync/atomic"
+	"testing"
+)
+
+func checkConvertOption[I, O Type](t *testing.T, input I, want O, name string, opts ...ConvertOption) {
+	t.Helper()
+
+	if res := ConvertType[O](input, opts...); res != want {
+		t.Errorf("wrong %s: got '%v', want '%v'", name, res, want)
+	}
+}
+
+func TestConvertOptionDefault(t *testing.T) {
+	t.Parallel()
+
+	// Default options must match the conversion without options.
+	for range 1000 {
+		input := 2.2*rand.Float64() - 1.1
+
+		checkConvertOption(t, input, ConvertType[int16](input), "float64->int16", ConvertScaling(ScalingSymmetric))
+		checkConvertOption(t, input, ConvertType[uint8](input), "float64->uint8", ConvertOverflow(OverflowClip))
+		checkConvertOption(t, input, ConvertType[int32](input), "float64->int32", ConvertScaling(ScalingSymmetric))
+
+		integer := int16(rand.IntN(math.MaxUint16) + math.MinInt16)
+		checkConvertOption(t, integer, ConvertType[float64](integer), "int16->float64", ConvertScaling(ScalingSymmetric))
+	}
+}
+
+func TestConvertOptionScaling(t *testing.T) {
+	t.Parallel()
+
+	t.Run("symmetric", func(t *testing.T) {
+		t.Parallel()
+
+		opt := ConvertScaling(ScalingSymmetric)
+
+		checkConvertOption(t, -1.0, int16(-math.MaxInt16), "minimum", opt)
+		checkConvertOption(t, 1.0, int16(math.MaxInt16), "maximum", opt)
+		checkConvertOption(t, int16(math.MinInt16), -1.0, "minimum integer", opt)
+		checkConvertOption(t, int16(math.MaxInt16), 1.0, "maximum integer", opt)
+		checkConvertOption(t, float32(-1), uint8(1), "unsigned minimum", opt)
+	})
+
+	t.Run("asymmetric", func(t *testing.T) {
+		t.Parallel()
+
+		opt := ConvertScaling(ScalingAsymmetric)
+
+		checkConvertOption(t, -1.0, int16(math.MinInt16), "minimum", opt)
+		checkConvertOption(t, 1.0, int16(math.MaxInt16), "maximum", opt)
+		checkConvertOption(t, 0.5, int16(16384), "half", opt)
+		checkConvertOption(t, int16(math.MinInt16), -1.0, "minimum integer", opt)
+		checkConvertOption(t, int16(16384), 0.5, "half integer", opt)
+		checkConvertOption(t, int8(math.MinInt8), float32(-1), "minimum int8", opt)
+		checkConvertOption(t, uint8(0), float32(-1), "minimum uint8", opt)
+		checkConvertOption(t, float32(-1), uint8(0), "unsigned minimum", opt)
+		checkConvertOption(t, int32(math.MinInt32), -1.0, "minimum int32", opt)
+	})
+}
+
+func TestConvertOptionOverflow(t *testing.T) {
+	t.Parallel()
+
+	t.Run("clip", func(t *testing.T) {
+		t.Parallel()
+
+		var clipped atomic.Int64
+
+		opts := []ConvertOption{ConvertOverflow(OverflowClip), ConvertClipCounter(&clipped)}
+
+		checkConvertOption(t, 1.5, int8(math.MaxInt8), "positive", opts...)
+		checkConvertOption(t, -1.5, int8(-math.MaxInt8), "negative", opts...)
+		checkConvertOption(t, 0.5, int8(64), "in range", opts...)
+		checkConvertOption(t, math.NaN(), int8(0), "NaN", opts...)
+
+		if clipped.Load() != 3 {
+			t.Errorf("wrong clip count: got '%d', want '%d'", clipped.Load(), 3)
+		}
+	})
+
+	t.Run("wrap", func(t *testing.T) {
+		t.Parallel()
+
+		var clipped atomic.Int64
+
+		opts := []ConvertOption{ConvertOverflow(OverflowWrap), ConvertClipCounter(&clipped)}
+
+		// 1.5 scales to 190.5, which rounds to 191 and wraps to 191-256.
+		checkConvertOption(t, 1.5, int8(-65), "positive", opts...)
+		checkConvertOption(t, -1.5, int8(65), "negative", opts...)
+		checkConvertOption(t, float32(1.5), uint8(63), "unsigned", opts...)
+		checkConvertOption(t, 2.0, int16(-2), "int16", opts...)
+
+		if clipped.Load() != 4 {
+			t.Errorf("wrong clip count: got '%d', want '%d'", clipped.Load(), 4)
+		}
+	})
+}
+
+func TestConvertSliceOptions(t *testing.T) {
+	t.Parallel()
+
+	var clipped atomic.Int64
+
+	input := []float32{-2, -1, -0.5, 0, 0.5, 1, 2}
+	want := []int16{math.MinInt16, math.MinInt16, -16384, 0, 16384, math.MaxInt16, math.MaxInt16}
+	output := make([]int16, len(input))
+
+	n := ConvertSlice(output, input, ConvertScaling(ScalingAsymmetric), ConvertClipCounter(&clipped))
+	if n != len(input) {
+		t.Fatalf("wrong length: got '%d', want '%d'", n, len(input))
+	}
+
+	for i := range want {
+		if output[i] != want[i] {
+			t.Errorf("wrong sample at index '%d': got '%d', want '%d'", i, output[i], want[i])
+		}
+	}
+
+	// Full scale is one above the largest positive integer with asymmetric scaling.
+	if clipped.Load() != 3 {
+		t.Errorf("wrong clip count: got '%d', want '%d'", clipped.Load(), 3)
+	}
+}
+
+func TestConverterClipped(t *testing.T) {
+	t.Parallel()
+
+	converter := NewConverter[Stereo[float64], Stereo[int16], float64, int16](4, ConvertScaling(ScalingAsymmetric))
+
+	_, _ = converter.Write([]Stereo[float64]{ToStereo(-1.0, 0.5), ToStereo(1.5, -1.5)})
+
+	output := make([]Stereo[int16], 2)
+	_, _ = converter.Read(output)
+
+	want := []Stereo[int16]{ToStereo[int16](math.MinInt16, 16384), ToStereo[int16](math.MaxInt16, math.MinInt16)}
+	for i := range want {
+		if output[i] != want[i] {
+			t.Errorf("wrong frame at index '%d': got '%v', want '%v'", i, output[i], want[i])
+		}
+	}
+
+	if converter.Clipped() != 2 {
+		t.Errorf("wrong clip count: got '%d', want '%d'", converter.Clipped(), 2)
+	}
+}
+
+func TestConverterMultiChannel(t *testing.T) {
+	t.Parallel()
+
+	// Multi-channel frames have the channel count of the format, and mono is converted to the first channel.
+	converter := NewConverter[float32, MultiChannel[int16], float32, int16](4, ConvertFormat(Format{Channels: 3}))
+
+	_, _ = converter.Write([]float32{0.5, -1})
+
+	output := make([]MultiChannel[int16], 2)
+	_, _ = converter.Read(output)
+
+	want := []MultiChannel[int16]{{16384, 0, 0}, {-math.MaxInt16, 0, 0}}
+	for i := range want {
+		if !slices.Equal(output[i], want[i]) {
+			t.Errorf("wrong frame at index '%d': got '%v', want '%v'", i, output[i], want[i])
+		}
+	}
+}
+
+func TestConverterClose(t *testing.T) {
+	t.Parallel()
+
+	converter := NewConverter[int16, float32, int16, float32](4)
+
+	_, _ = converter.Write([]int16{0, maxInt16, -maxInt16})
+
+	err := converter.Close()
+	if err != nil {
+		t.Fatalf("closing converter: error: %s", err.Error())
+	}
+
+	// The frames written before closing are returned by the last partial read.
+	output := make([]float32, 5)
+
+	n, err := converter.Read(output)
+	if n != 3 || err != io.EOF {
+		t.Fatalf("wrong last read: got '%d' frames and error '%v', want '%d' frames and error '%v'", n, err, 3, io.EOF)
+	}
+
+	if want := []float32{0, 1, -1}; !slices.Equal(output[:n], want) {
+		t.Errorf("wrong frames: got '%v', want '%v'", output[:n], want)
+	}
+
+	n, err = converter.Read(output)
+	if n != 0 || err != io.EOF {
+		t.Errorf("wrong read after end: got '%d' frames and error '%v', want '%d' frames and error '%v'", n, err, 0, io.EOF)
+	}
+
+	_, err = converter.Write([]int16{1})
+	if !errors.Is(err, ErrRingBufferClosed) {
+		t.Errorf("wrong write error after close: got '%v', want '%v'", err, ErrRingBufferClosed)
+	}
+}
+
+func TestDithererAsymmetric(t *testing.T) {
+	t.Parallel()
+
+	var clipped atomic.Int64
+
+	input := []float64{-1, 1}
+	output := make([]int8, len(input))
+
+	NewDitherer[int8, float64](1, ConvertScaling(ScalingAsymmetric), ConvertClipCounter(&clipped)).ConvertSlice(output, input)
+
+	if output[0] != math.MinInt8 || output[1] != math.MaxInt8 {
+		t.Errorf("wrong output: got '%v', want '%v'", output, []int8{math.MinInt8, math.MaxInt8})
+	}
+
+	if clipped.Load() != 1 {
+		t.Errorf("wrong clip count: got '%d', want '%d'", clipped.Load(), 1)
+	}
+}
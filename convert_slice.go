@@ -5,12 +5,29 @@ import (
 )
 
 // ConvertSlice converts a slice of audio samples from one type to another.
-func ConvertSlice[O Type, I Type](out []O, in []I) (length int) {
+// Without options, integers are scaled symmetrically and out-of-range floating-point samples are clipped.
+// Dither and noise shaping treat the samples as interleaved with the number of channels of [ConvertFormat], defaulting to one,
+// and start from the seed on every call. Use a [Ditherer] to continue a stream across calls.
+func ConvertSlice[O Type, I Type](out []O, in []I, opts ...ConvertOption) (length int) {
 	length = min(len(in), len(out))
 	if length == 0 {
 		return
 	}
 
+	if len(opts) > 0 {
+		cfg := newConvertConfig(opts...)
+
+		if cfg.dithered() && isFloat[I]() && !isFloat[O]() {
+			cfg.quantizer = newQuantizer(&cfg, max(1, cfg.Format.Channels))
+		}
+
+		for i := range length {
+			out[i] = convertTypeConfig[O](in[i], &cfg)
+		}
+
+		return length
+	}
+
 	switch unsafe.Sizeof(in[0]) {
 	case 1: // 8-bit input
 		switch unsafe.Sizeof(out[0]) {
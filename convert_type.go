@@ -3,13 +3,12 @@ package gsp
 import (
 	"errors"
 	"math"
+	"math/rand/v2"
 	"unsafe"
 
 	"github.com/samborkent/math32"
 )
 
-// TODO: fix scaling of
-
 var (
 	errUnknownInputType     = errors.New("gsp: convertType: unknown input type encountered")
 	errUnknownInputBitSize  = errors.New("gsp: convertType: unknown input bit size encountered")
@@ -17,7 +16,20 @@ var (
 )
 
 // ConvertType converts any audio sample type to any other sample type.
-func ConvertType[O Type, I Type](in I) (out O) {
+// Without options, integers are scaled symmetrically and out-of-range floating-point samples are clipped.
+// A single sample has no history, so its dither is drawn from the global random generator, and noise shaping has no effect.
+func ConvertType[O Type, I Type](in I, opts ...ConvertOption) (out O) {
+	if len(opts) > 0 {
+		cfg := newConvertConfig(opts...)
+
+		if cfg.dithered() && isFloat[I]() && !isFloat[O]() {
+			cfg.quantizer = newQuantizer(&cfg, 1)
+			cfg.quantizer.uniform = rand.Float64
+		}
+
+		return convertTypeConfig[O](in, &cfg)
+	}
+
 	switch unsafe.Sizeof(in) {
 	case 1: // 8-bit input
 		switch unsafe.Sizeof(out) {
@@ -471,3 +483,100 @@ func quantize32[T Signed](num float32) T {
 func quantize64[T Signed](num float64) T {
 	return T(math.Round(num))
 }
+
+// convertSample converts a sample with the configuration, or with the default configuration if cfg is nil.
+func convertSample[O Type, I Type](cfg *ConvertConfig, in I) O {
+	if cfg == nil {
+		return ConvertType[O](in)
+	}
+
+	return convertTypeConfig[O](in, cfg)
+}
+
+// convertTypeConfig converts a sample with the scaling, overflow handling and dither of cfg.
+// Only conversions between integer and floating-point samples depend on the configuration.
+func convertTypeConfig[O Type, I Type](in I, cfg *ConvertConfig) O {
+	switch {
+	case isFloat[I]() && !isFloat[O]():
+		r := newIntegerRange[O](cfg.Scaling)
+
+		if cfg.quantizer != nil {
+			return O(cfg.quantizer.process(float64(in), &r, cfg) + r.zero)
+		}
+
+		return O(r.limit(math.Round(float64(in)*r.scale), cfg) + r.zero)
+	case !isFloat[I]() && isFloat[O]():
+		r := newIntegerRange[I](cfg.Scaling)
+		value := (float64(in) - r.zero) * (1 / r.scale)
+
+		if cfg.Scaling == ScalingSymmetric {
+			return O(max(value, -1))
+		}
+
+		return O(value)
+	default:
+		return ConvertType[O](in)
+	}
+}
+
+// integerRange describes the mapping of an integer type to the floating-point range [-1, 1].
+type integerRange struct {
+	scale   float64 // Integer value of floating-point full scale.
+	minimum float64 // Lowest integer value of floating-point samples, before offset.
+	maximum float64 // Highest integer value, before offset.
+	wrap    float64 // Number of integer values.
+	zero    float64 // Offset of unsigned integers.
+}
+
+func newIntegerRange[T Type](scaling Scaling) integerRange {
+	var r integerRange
+
+	switch unsafe.Sizeof(T(0)) {
+	case 1:
+		r.maximum, r.zero = maxInt8_64, float64(zeroUint8)
+	case 2:
+		r.maximum, r.zero = maxInt16_64, float64(zeroUint16)
+	case 4:
+		r.maximum, r.zero = maxInt32_64, float64(zeroUint32)
+	default:
+		panic(errUnknownOutputBitSize.Error())
+	}
+
+	r.wrap = 2 * (r.maximum + 1)
+
+	switch scaling {
+	case ScalingSymmetric:
+		r.scale, r.minimum = r.maximum, -r.maximum
+	case ScalingAsymmetric:
+		r.scale, r.minimum = r.maximum+1, -r.maximum-1
+	default:
+		panic("gsp: Scaling: scaling not implemented")
+	}
+
+	if !isUnsigned[T]() {
+		r.zero = 0
+	}
+
+	return r
+}
+
+// limit clips or wraps an integer value outside of the range, and counts it.
+func (r *integerRange) limit(value float64, cfg *ConvertConfig) float64 {
+	if value >= r.minimum && value <= r.maximum {
+		return value
+	}
+
+	if cfg.Clipped != nil {
+		cfg.Clipped.Add(1)
+	}
+
+	if cfg.Overflow == OverflowWrap && !math.IsNaN(value) && !math.IsInf(value, 0) {
+		return value - r.wrap*math.Floor((value+r.wrap/2)/r.wrap)
+	}
+
+	if math.IsNaN(value) {
+		return 0
+	}
+
+	return min(max(value, r.minimum), r.maximum)
+}
@@ -3,6 +3,7 @@ package gsp
 import (
 	"context"
 	"runtime"
+	"sync/atomic"
 	"unsafe"
 )
 
@@ -12,38 +13,82 @@ var (
 )
 
 // Converter can converts from one data type to another.
+// Frames are transported over lock-free rings, and converted on a separate goroutine.
 type Converter[In Frame[I], Out Frame[O], I Type, O Type] struct {
-	input  chan In
-	output chan Out
+	input  *ring[In]
+	output *ring[Out]
+	cfg    *ConvertConfig
+
+	inputFrame  [1]In  // Scratch of the writer.
+	outputFrame [1]Out // Scratch of the reader.
 }
 
-func NewConverter[In Frame[I], Out Frame[O], I Type, O Type](bufferSize int) *Converter[In, Out, I, O] {
-	input := make(chan In, bufferSize)
-	output := make(chan Out, bufferSize)
+// NewConverter returns a [Converter] which converts frames with the scaling, overflow handling and dither of the options.
+// The input and output each buffer up to bufferSize frames.
+// Out-of-range samples are always counted, see [Converter.Clipped].
+func NewConverter[In Frame[I], Out Frame[O], I Type, O Type](bufferSize int, opts ...ConvertOption) *Converter[In, Out, I, O] {
+	cfg := newConvertConfig(opts...)
+	if cfg.Clipped == nil {
+		cfg.Clipped = new(atomic.Int64)
+	}
+
+	// Each channel of the converted frames keeps its own noise-shaping history.
+	if cfg.dithered() && isFloat[I]() && !isFloat[O]() {
+		cfg.quantizer = newQuantizer(&cfg, 0)
+	}
+
+	bufferSize = max(1, bufferSize)
+
+	input := newRing(bufferSize, copyFrames[In, I])
+	output := newRing(bufferSize, copyFrames[Out, O])
 
 	converter := &Converter[In, Out, I, O]{
 		input:  input,
 		output: output,
+		cfg:    &cfg,
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 
 	runtime.AddCleanup(converter, func(_ int) {
 		cancel()
-		close(input)
-		close(output)
+		input.close()
+		output.close()
 	}, 0)
 
-	go converter.run(ctx)
+	go runConverter[In, Out, I, O](ctx, input, output, &cfg, bufferSize)
 
 	return converter
 }
 
+// Format returns the format of the converted frames, as set with [ConvertFormat].
+// The sample type is always the output sample type.
+func (c *Converter[In, Out, I, O]) Format() Format {
+	format := c.cfg.Format
+	format.SampleType = SampleTypeOf[O]()
+
+	if channels := frameChannels[Out, O](); channels > 0 {
+		format.Channels = channels
+	}
+
+	return format
+}
+
+// Clipped returns the number of samples which were out of range for the output type.
+func (c *Converter[In, Out, I, O]) Clipped() int64 {
+	return c.cfg.Clipped.Load()
+}
+
 // Read converted output from converter, blocking until output is filled.
-// Error is always nil, so can be safely ignored (present to match to [Reader]).
+// Once the converter is closed and all converted frames are read, it returns the frames of the last partial read with [io.EOF].
 func (c *Converter[In, Out, I, O]) Read(output []Out) (int, error) {
-	for i := range output {
-		output[i] = <-c.output
+	for n := 0; n < len(output); {
+		read, err := c.output.read(output[n:], true, nil)
+		if err != nil {
+			return n, err
+		}
+
+		n += read
 	}
 
 	return len(output), nil
@@ -51,98 +96,109 @@ func (c *Converter[In, Out, I, O]) Read(output []Out) (int, error) {
 
 // Get tries to get n samples from the output. If no more frames remain, return intermediate result.
 func (c *Converter[In, Out, I, O]) Get(n int) []Out {
-	output := make([]Out, 0, n)
-
-	select {
-	case frame := <-c.output:
-		output = append(output, frame)
-	default:
-		return output
-	}
+	output := make([]Out, n)
+	read, _ := c.output.read(output, false, nil)
 
-	return output
+	return output[:read]
 }
 
 // ReadFrame reads a single converted frame from the converter, blocking if no new frame is available.
 func (c *Converter[In, Out, I, O]) ReadFrame() Out {
-	return <-c.output
+	// Multi-channel samples are copied into a new frame, so returned frames are never reused.
+	c.outputFrame[0] = *new(Out)
+	_, _ = c.output.read(c.outputFrame[:], true, nil)
+
+	return c.outputFrame[0]
 }
 
 // GetFrame is a non-blocking version of [Converter.ReadFrame].
 func (c *Converter[In, Out, I, O]) GetFrame() (Out, bool) {
-	select {
-	case frame := <-c.output:
-		return frame, true
-	default:
+	c.outputFrame[0] = *new(Out)
+
+	n, _ := c.output.read(c.outputFrame[:], false, nil)
+	if n == 0 {
 		return *new(Out), false
 	}
+
+	return c.outputFrame[0], true
 }
 
-// Write input to converter, blocking if the input channel is full.
-// Error is always nil, so can be safely ignored (present to match to [Writer]).
+// Write input to converter, blocking if the input is full.
+// It returns [ErrRingBufferClosed] after [Converter.Close].
 func (c *Converter[In, Out, I, O]) Write(input []In) (int, error) {
-	for _, sample := range input {
-		c.input <- sample
-	}
+	return c.input.write(input, true, nil)
+}
 
-	return len(input), nil
+// Close stops accepting writes. The frames written before are still converted, after which [Converter.Read] returns [io.EOF].
+// It is safe to call multiple times.
+func (c *Converter[In, Out, I, O]) Close() error {
+	c.input.close()
+	return nil
 }
 
 // Put will try to write the input to the converter. If the converter input is full, return early and return number of entries written.
 func (c *Converter[In, Out, I, O]) Put(input []In) int {
-	n := 0
-
-frameLoop:
-	for _, frame := range input {
-		select {
-		case c.input <- frame:
-			n++
-		default:
-			break frameLoop
-		}
-	}
-
+	n, _ := c.input.write(input, false, nil)
 	return n
 }
 
-// WriteFrame writes a single frame to the converter, blocking if the input channel is full.
+// WriteFrame writes a single frame to the converter, blocking if the input is full.
 func (c *Converter[In, Out, I, O]) WriteFrame(frame In) {
-	c.input <- frame
+	c.inputFrame[0] = frame
+	_, _ = c.input.write(c.inputFrame[:], true, nil)
 }
 
 // PutFrame puts a single frame to the converter. It returns true is the frame was written to the converter, false if the input is full.
 func (c *Converter[In, Out, I, O]) PutFrame(frame In) bool {
-	select {
-	case c.input <- frame:
-		return true
-	default:
-		return false
-	}
+	c.inputFrame[0] = frame
+	n, _ := c.input.write(c.inputFrame[:], false, nil)
+
+	return n == 1
 }
 
-func (c *Converter[In, Out, I, O]) run(ctx context.Context) {
+// runConverter converts all available input frames at once, until the input is closed and drained or the context is cancelled.
+// It does not reference the converter, so the converter can be garbage collected.
+func runConverter[In Frame[I], Out Frame[O], I Type, O Type](ctx context.Context, input *ring[In], output *ring[Out], cfg *ConvertConfig, bufferSize int) {
+	defer output.close()
+
+	inputBuffer := make([]In, bufferSize)
+	outputBuffer := make([]Out, bufferSize)
+
 	for {
-		select {
-		case <-ctx.Done():
+		n, err := input.read(inputBuffer, true, ctx.Done())
+		if err != nil {
+			return
+		}
+
+		for i := range n {
+			if cfg.quantizer != nil {
+				cfg.quantizer.channel = 0
+			}
+
+			outputBuffer[i] = convert[In, Out, I, O](cfg, inputBuffer[i], cfg.Format.Channels)
+		}
+
+		_, err = output.write(outputBuffer[:n], true, ctx.Done())
+		if err != nil {
 			return
-		case input := <-c.input:
-			c.output <- convert[In, Out, I, O](input)
 		}
 	}
 }
 
-func convert[In Frame[I], Out Frame[O], I Type, O Type](input In) (output Out) {
+// convert converts a frame to another frame and sample type.
+// Multi-channel output frames have the given number of channels, or the channel count of the input if it is zero.
+func convert[In Frame[I], Out Frame[O], I Type, O Type](cfg *ConvertConfig, input In, channels int) (output Out) {
 	switch any(*new(In)).(type) {
 	case I: // mono input
 		switch any(*new(Out)).(type) {
 		case O: // mono -> mono
-			out := convertMono[O](*(*I)(unsafe.Pointer(&input)))
+			out := convertMono[O](cfg, *(*I)(unsafe.Pointer(&input)))
 			return *(*Out)(unsafe.Pointer(&out))
 		case [2]O, Stereo[O]: // mono -> stereo
-			out := convertMonoToStereo[O](*(*I)(unsafe.Pointer(&input)))
+			out := convertMonoToStereo[O](cfg, *(*I)(unsafe.Pointer(&input)))
 			return *(*Out)(unsafe.Pointer(&out))
 		case []O, MultiChannel[O]: // mono -> multi-channel
-			out := convertMonoToMultiChannel[O](*(*I)(unsafe.Pointer(&input)))
+			out := convertMonoToMultiChannel[O](cfg, *(*I)(unsafe.Pointer(&input)), channels)
 			return *(*Out)(unsafe.Pointer(&out))
 		default:
 			panic("gsp: convert: unknown output audio type")
@@ -150,13 +206,13 @@ func convert[In Frame[I], Out Frame[O], I Type, O Type](input In) (output Out) {
 	case [2]I, Stereo[I]: // stereo input
 		switch any(*new(Out)).(type) {
 		case O: // stereo -> mono
-			out := convertStereoToMono[O](*(*Stereo[I])(unsafe.Pointer(&input)))
+			out := convertStereoToMono[O](cfg, *(*Stereo[I])(unsafe.Pointer(&input)))
 			return *(*Out)(unsafe.Pointer(&out))
 		case [2]O, Stereo[O]: // stereo -> stereo
-			out := convertStereo[O](*(*Stereo[I])(unsafe.Pointer(&input)))
+			out := convertStereo[O](cfg, *(*Stereo[I])(unsafe.Pointer(&input)))
 			return *(*Out)(unsafe.Pointer(&out))
 		case []O, MultiChannel[O]: // stereo -> multi-channel
-			out := convertStereoToMultiChannel[O](*(*Stereo[I])(unsafe.Pointer(&input)))
+			out := convertStereoToMultiChannel[O](cfg, *(*Stereo[I])(unsafe.Pointer(&input)), channels)
 			return *(*Out)(unsafe.Pointer(&out))
 		default:
 			panic("gsp: convert: unknown output audio type")
@@ -164,13 +220,13 @@ func convert[In Frame[I], Out Frame[O], I Type, O Type](input In) (output Out) {
 	case []I, MultiChannel[I]: // multi-channel input
 		switch any(*new(Out)).(type) {
 		case O: // multi-channel -> mono
-			out := convertMultiChannelToMono[O](*(*MultiChannel[I])(unsafe.Pointer(&input)))
+			out := convertMultiChannelToMono[O](cfg, *(*MultiChannel[I])(unsafe.Pointer(&input)))
 			return *(*Out)(unsafe.Pointer(&out))
 		case [2]O, Stereo[O]: // multi-channel -> stereo
-			out := convertMultiChannelToStereo[O](*(*MultiChannel[I])(unsafe.Pointer(&input)))
+			out := convertMultiChannelToStereo[O](cfg, *(*MultiChannel[I])(unsafe.Pointer(&input)))
 			return *(*Out)(unsafe.Pointer(&out))
 		case []O, MultiChannel[O]: // multi-channel -> multi-channel
-			out := convertMultiChannel[O](*(*MultiChannel[I])(unsafe.Pointer(&input)))
+			out := convertMultiChannel[O](cfg, *(*MultiChannel[I])(unsafe.Pointer(&input)), channels)
 			return *(*Out)(unsafe.Pointer(&out))
 		default:
 			panic("gsp: convert: unknown output audio type")
@@ -180,75 +236,71 @@ func convert[In Frame[I], Out Frame[O], I Type, O Type](input In) (output Out) {
 	}
 }
 
-func convertMono[O Type, I Type](input I) (output O) {
-	return ConvertType[O](input)
+func convertMono[O Type, I Type](cfg *ConvertConfig, input I) (output O) {
+	return convertSample[O](cfg, input)
 }
 
-func convertMonoToStereo[O Type, I Type](input I) (output Stereo[O]) {
-	return MonoToStereo(ConvertType[O](input))
+func convertMonoToStereo[O Type, I Type](cfg *ConvertConfig, input I) (output Stereo[O]) {
+	return MonoToStereo(convertSample[O](cfg, input))
 }
 
-func convertMonoToMultiChannel[O Type, I Type](input I) (output MultiChannel[O]) {
-	if len(output) == 0 {
-		return MultiChannel[O]{}
-	}
-
-	out := ZeroMultiChannel[O](len(output))
-	out[0] = ConvertType[O](input)
+func convertMonoToMultiChannel[O Type, I Type](cfg *ConvertConfig, input I, channels int) (output MultiChannel[O]) {
+	output = ZeroMultiChannel[O](max(1, channels))
+	output[0] = convertSample[O](cfg, input)
 
-	return out
+	return output
 }
 
-func convertStereoToMono[O Type, I Type](input Stereo[I]) (output O) {
-	return ConvertType[O](input.M())
+func convertStereoToMono[O Type, I Type](cfg *ConvertConfig, input Stereo[I]) (output O) {
+	return convertSample[O](cfg, input.M())
 }
 
-func convertStereo[O Type, I Type](input Stereo[I]) (output Stereo[O]) {
-	return ToStereo(ConvertType[O](input.L()), ConvertType[O](input.R()))
+func convertStereo[O Type, I Type](cfg *ConvertConfig, input Stereo[I]) (output Stereo[O]) {
+	return ToStereo(convertSample[O](cfg, input.L()), convertSample[O](cfg, input.R()))
 }
 
-func convertStereoToMultiChannel[O Type, I Type](input Stereo[I]) (output MultiChannel[O]) {
-	if len(output) == 0 {
-		return MultiChannel[O]{}
+func convertStereoToMultiChannel[O Type, I Type](cfg *ConvertConfig, input Stereo[I], channels int) (output MultiChannel[O]) {
+	if channels == 0 {
+		channels = 2
 	}
 
-	out := ZeroMultiChannel[O](len(output))
+	output = ZeroMultiChannel[O](channels)
 
-	switch len(output) {
+	switch channels {
 	case 1:
-		out[0] = ConvertType[O](input.M())
+		output[0] = convertSample[O](cfg, input.M())
 	default:
-		out[L] = ConvertType[O](input.L())
-		out[R] = ConvertType[O](input.R())
+		output[L] = convertSample[O](cfg, input.L())
+		output[R] = convertSample[O](cfg, input.R())
 	}
 
-	return out
+	return output
 }
 
-func convertMultiChannelToMono[O Type, I Type](input MultiChannel[I]) (output O) {
+func convertMultiChannelToMono[O Type, I Type](cfg *ConvertConfig, input MultiChannel[I]) (output O) {
 	if len(input) == 0 {
 		return Zero[O]()
 	}
 
-	return ConvertType[O](input.M())
+	return convertSample[O](cfg, input.M())
 }
 
-func convertMultiChannelToStereo[O Type, I Type](input MultiChannel[I]) (output Stereo[O]) {
+func convertMultiChannelToStereo[O Type, I Type](cfg *ConvertConfig, input MultiChannel[I]) (output Stereo[O]) {
 	if len(input) == 0 {
 		return ZeroStereo[O]()
 	}
 
 	switch len(input) {
 	case 1:
-		return MonoToStereo(ConvertType[O](input[0]))
+		return MonoToStereo(convertSample[O](cfg, input[0]))
 	case 2:
-		return ToStereo(ConvertType[O](input[L]), ConvertType[O](input[R]))
+		return ToStereo(convertSample[O](cfg, input[L]), convertSample[O](cfg, input[R]))
 	default:
-		out := ToStereo(ConvertType[O](input[L]), ConvertType[O](input[R]))
+		out := ToStereo(convertSample[O](cfg, input[L]), convertSample[O](cfg, input[R]))
 
 		sides := O(0)
 		for i := 2; i < len(input); i++ {
-			sides += ConvertType[O](input[i])
+			sides += convertSample[O](cfg, input[i])
 		}
 		sides /= O(len(input) - 2)
 		sides /= 2
@@ -260,30 +312,30 @@ func convertMultiChannelToStereo[O Type, I Type](input MultiChannel[I]) (output
 	}
 }
 
-func convertMultiChannel[O Type, I Type](input MultiChannel[I]) (output MultiChannel[O]) {
-	if len(output) == 0 {
-		return MultiChannel[O]{}
+func convertMultiChannel[O Type, I Type](cfg *ConvertConfig, input MultiChannel[I], channels int) (output MultiChannel[O]) {
+	if channels == 0 {
+		channels = len(input)
 	}
 
 	switch len(input) {
 	case 0:
-		return ZeroMultiChannel[O](len(output))
+		return ZeroMultiChannel[O](channels)
 	case 1:
-		return convertMonoToMultiChannel[O](input[0])
+		return convertMonoToMultiChannel[O](cfg, input[0], channels)
 	case 2:
-		return convertStereoToMultiChannel[O](ToStereo(input[L], input[R]))
+		return convertStereoToMultiChannel[O](cfg, ToStereo(input[L], input[R]), channels)
 	default:
-		switch len(output) {
+		switch channels {
 		case 1:
-			return MultiChannel[O]{convertMultiChannelToMono[O](input)}
+			return MultiChannel[O]{convertMultiChannelToMono[O](cfg, input)}
 		case 2:
-			out := convertMultiChannelToStereo[O](input)
+			out := convertMultiChannelToStereo[O](cfg, input)
 			return MultiChannel[O]{out[L], out[R]}
 		default:
-			out := ZeroMultiChannel[O](len(output))
+			out := ZeroMultiChannel[O](channels)
 
-			for i := range min(len(input), len(output)) {
-				out[i] = ConvertType[O](input[i])
+			for i := range min(len(input), channels) {
+				out[i] = convertSample[O](cfg, input[i])
 			}
 
 			return out
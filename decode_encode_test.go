@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"math"
 	"math/rand/v2"
+	"slices"
 	"testing"
 	"unsafe"
 
@@ -202,3 +203,161 @@ func testDecodeEncodeMono[T gsp.Type](t *testing.T, data []T, input []byte) {
 		}
 	}
 }
+
+func TestDecodeToEncodeFromStereo(t *testing.T) {
+	t.Parallel()
+
+	N := 100
+
+	input := make([]byte, 2*2*N)
+	for i := range 2 * N {
+		binary.LittleEndian.PutUint16(input[2*i:2*(i+1)], uint16(int16((2*rand.Float32()-1)*math.MaxInt16)))
+	}
+
+	decoder := gsp.NewDecoder[gsp.Stereo[int16], int16](bytes.NewReader(input))
+	frames := new(gsp.Buffer[gsp.Stereo[int16], int16])
+
+	err := decoder.DecodeTo(frames)
+	if err != nil {
+		t.Fatalf("decoding frames: error: %s", err.Error())
+	}
+
+	if frames.Len() != N {
+		t.Fatalf("wrong number of frames: got '%d', want '%d'", frames.Len(), N)
+	}
+
+	buf := new(bytes.Buffer)
+	encoder := gsp.NewEncoder[gsp.Stereo[int16], int16](buf)
+
+	err = encoder.EncodeFrom(frames)
+	if err != nil {
+		t.Fatalf("encoding frames: error: %s", err.Error())
+	}
+
+	if !bytes.Equal(buf.Bytes(), input) {
+		t.Errorf("binary mismatch: got '%v', want '%v'", buf.Bytes(), input)
+	}
+}
+
+func TestDecodeEncodePacking(t *testing.T) {
+	t.Parallel()
+
+	// 24-bit values covering both signs, full scale and values with the most significant bit set in each byte.
+	values := []int32{0, 1, -1, 0x123456, -0x123456, 0x7FFFFF, -0x800000, 0x008080, -0x008080}
+
+	tests := []struct {
+		name      string
+		packing   gsp.Packing
+		bigEndian bool
+		encode    func(value int32) []byte
+	}{
+		{"packed little-endian", gsp.Packing24, false, func(v int32) []byte {
+			return []byte{byte(v), byte(v >> 8), byte(v >> 16)}
+		}},
+		{"packed big-endian", gsp.Packing24, true, func(v int32) []byte {
+			return []byte{byte(v >> 16), byte(v >> 8), byte(v)}
+		}},
+		{"left-justified little-endian", gsp.Packing24In32Left, false, func(v int32) []byte {
+			return binary.LittleEndian.AppendUint32(nil, uint32(v)<<8)
+		}},
+		{"left-justified big-endian", gsp.Packing24In32Left, true, func(v int32) []byte {
+			return binary.BigEndian.AppendUint32(nil, uint32(v)<<8)
+		}},
+		{"right-justified little-endian", gsp.Packing24In32Right, false, func(v int32) []byte {
+			return binary.LittleEndian.AppendUint32(nil, uint32(v))
+		}},
+		{"right-justified big-endian", gsp.Packing24In32Right, true, func(v int32) []byte {
+			return binary.BigEndian.AppendUint32(nil, uint32(v))
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var input []byte
+			for _, value := range values {
+				input = append(input, test.encode(value)...)
+			}
+
+			opts := []gsp.EncodingOption{gsp.EncodingPacking(test.packing)}
+			if test.bigEndian {
+				opts = append(opts, gsp.EncodingBigEndian)
+			}
+
+			t.Run("int32", func(t *testing.T) {
+				t.Parallel()
+
+				want := make([]int32, len(values))
+				for i, value := range values {
+					want[i] = value << 8
+				}
+
+				testDecodeEncodePacking(t, input, want, opts...)
+			})
+
+			t.Run("float32", func(t *testing.T) {
+				t.Parallel()
+
+				want := make([]float32, len(values))
+				for i, value := range values {
+					want[i] = max(float32(value)/(1<<23-1), -1)
+				}
+
+				// The most negative value is clipped to -1, which encodes as its symmetric counterpart.
+				expected := bytes.Clone(input)
+				index := slices.Index(values, -0x800000)
+				copy(expected[index*len(input)/len(values):], test.encode(-0x7FFFFF))
+
+				decoded := testDecodePacking(t, input, want, opts...)
+				testEncodePacking(t, decoded, expected, opts...)
+			})
+		})
+	}
+}
+
+func testDecodeEncodePacking[T gsp.Type](t *testing.T, input []byte, want []T, opts ...gsp.EncodingOption) {
+	t.Helper()
+
+	testEncodePacking(t, testDecodePacking(t, input, want, opts...), input, opts...)
+}
+
+func testDecodePacking[T gsp.Type](t *testing.T, input []byte, want []T, opts ...gsp.EncodingOption) []T {
+	t.Helper()
+
+	decoder := gsp.NewDecoder[T, T](bytes.NewReader(input), opts...)
+
+	if decoder.ByteSize() != len(input)/len(want) {
+		t.Errorf("wrong byte size: got '%d', want '%d'", decoder.ByteSize(), len(input)/len(want))
+	}
+
+	samples := make([]T, len(want))
+
+	err := decoder.Decode(samples)
+	if err != nil {
+		t.Fatalf("decoding samples: error: %s", err.Error())
+	}
+
+	for i := range samples {
+		if samples[i] != want[i] {
+			t.Errorf("sample mismatch at index '%d': got '%v', want '%v'", i, samples[i], want[i])
+		}
+	}
+
+	return samples
+}
+
+func testEncodePacking[T gsp.Type](t *testing.T, samples []T, want []byte, opts ...gsp.EncodingOption) {
+	t.Helper()
+
+	buf := new(bytes.Buffer)
+
+	err := gsp.NewEncoder[T, T](buf, opts...).Encode(samples)
+	if err != nil {
+		t.Fatalf("encoding samples: error: %s", err.Error())
+	}
+
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("binary mismatch: got '%v', want '%v'", buf.Bytes(), want)
+	}
+}
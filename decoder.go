@@ -23,7 +23,10 @@ type Decoder[F Frame[T], T Type] struct {
 	samplePool                *Pool[F, T]
 	bytesRead, samplesDecoded atomic.Int64
 	channels, byteSize        int
+	sampleRate                int
+	packing                   Packing
 	bigEndian                 bool
+	multiChannel              bool
 	initialized               bool
 }
 
@@ -35,28 +38,39 @@ func NewDecoder[F Frame[T], T Type](r io.Reader, opts ...EncodingOption) *Decode
 	}
 
 	var channels int
+	var multiChannel bool
 
-	switch frameType := any(*new(F)).(type) {
+	switch any(*new(F)).(type) {
 	case T:
 		channels = 1
 	case [2]T, Stereo[T]:
 		channels = 2
-	case []T:
-		channels = len(frameType)
-	case MultiChannel[T]:
-		channels = len(frameType)
+	case []T, MultiChannel[T]:
+		// Channel count is only known at runtime, if not configured it is taken from the first frame.
+		channels = cfg.Channels
+		multiChannel = true
 	default:
 		panic("gsp: NewDecoder: unknown audio frame type")
 	}
 
+	checkPacking[T](cfg.Packing, "NewDecoder")
+
+	byteSize := int(unsafe.Sizeof(T(0)))
+	if cfg.Packing != PackingNone {
+		byteSize = cfg.Packing.byteSize()
+	}
+
 	return &Decoder[F, T]{
-		r:           r,
-		bytePool:    NewByteBufferPool(),
-		samplePool:  NewPool[F, T](),
-		channels:    channels,
-		byteSize:    int(unsafe.Sizeof(T(0))),
-		bigEndian:   cfg.BigEndian,
-		initialized: true,
+		r:            r,
+		bytePool:     NewByteBufferPool(),
+		samplePool:   NewPool[F, T](),
+		channels:     channels,
+		byteSize:     byteSize,
+		sampleRate:   cfg.SampleRate,
+		packing:      cfg.Packing,
+		bigEndian:    cfg.BigEndian,
+		multiChannel: multiChannel,
+		initialized:  true,
 	}
 }
 
@@ -64,17 +78,37 @@ func (d *Decoder[F, T]) Channels() int {
 	return d.channels
 }
 
+// ByteSize returns the size of a single encoded sample in bytes.
 func (d *Decoder[F, T]) ByteSize() int {
 	return d.byteSize
 }
 
+// Format returns the format of the decoded stream.
+// The channel count of multi-channel streams is zero until it is known.
+func (d *Decoder[F, T]) Format() Format {
+	return Format{
+		SampleRate: d.sampleRate,
+		Channels:   d.channels,
+		SampleType: SampleTypeOf[T](),
+		BigEndian:  d.bigEndian,
+		Packing:    d.packing,
+		Layout:     LayoutInterleaved,
+	}
+}
+
 // Decode reads from the internal [io.Reader] and decodes into the sample slice.
 // It will read until [io.EOF] is reached, so users should ensure to pass an adequately sized sample frame slice to prevent data loss.
+// Stereo and multi-channel frames are decoded from interleaved samples.
+// Multi-channel frames which do not match the channel count are reallocated.
 func (d *Decoder[F, T]) Decode(s []F) error {
 	if !d.initialized {
 		return ErrDecoderNotinitialized
 	}
 
+	if len(s) == 0 {
+		return nil
+	}
+
 	// Retrieve byte buffer from pool.
 	buf := d.bytePool.Get()
 	if buf == nil {
@@ -93,23 +127,17 @@ func (d *Decoder[F, T]) Decode(s []F) error {
 
 	d.bytesRead.Add(int64(bytesRead))
 
-	switch d.channels {
-	case 1:
-		// There is only a single channel, so we can safely perform this unsafe type-casting.
-		samplesDecoded := d.decodeMono(unsafe.Slice((*T)(unsafe.Pointer(&s[0])), len(s)), buf.Bytes())
-		d.samplesDecoded.Add(int64(samplesDecoded))
-	case 2:
-		panic("gsp: Decoder.Decode: implement stereo decoding")
-	default:
-		panic("gsp: Decoder.Decode: implement multi-channel decoding")
+	framesDecoded, err := d.decodeFrames(s, buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("gsp: Decoder.Decode: %w", err)
 	}
 
+	d.samplesDecoded.Add(int64(framesDecoded * d.channels))
+
 	return nil
 }
 
 // Decode reads from the internal [io.Reader] and decodes into the [Writer].
-//
-// TODO: can probably be improved, ideally should rely on same logic as Decode (see EncodeFrom).
 func (d *Decoder[F, T]) DecodeTo(w Writer[F, T]) error {
 	if !d.initialized {
 		return ErrDecoderNotinitialized
@@ -132,35 +160,80 @@ func (d *Decoder[F, T]) DecodeTo(w Writer[F, T]) error {
 
 	d.bytesRead.Add(int64(bytesRead))
 
+	if d.channels == 0 {
+		return fmt.Errorf("gsp: Decoder.DecodeTo: %w", ErrUnknownChannelCount)
+	}
+
+	frames := buf.Len() / (d.channels * d.byteSize)
+	if frames == 0 {
+		return nil
+	}
+
 	sampleBuf := d.samplePool.Get()
 	if sampleBuf == nil {
 		// Allocate in-case the pool does not have a pre-allocated entry ready.
-		sampleBuf = NewBuffer[F, T](make([]F, decoderMinRead))
+		sampleBuf = NewBuffer[F, T](make([]F, 0, max(frames, decoderMinRead)))
 	}
 
 	defer d.samplePool.Put(sampleBuf)
 
-	switch d.channels {
-	case 1:
-		// There is only a single channel, so we can safely perform this unsafe type-casting.
-		_ = d.decodeMono(unsafe.Slice((*T)(unsafe.Pointer(&sampleBuf.Frames()[0])), sampleBuf.Len()), buf.Bytes())
-	case 2:
-		panic("gsp: Decoder.DecodeTo: implement stereo decoding")
-	default:
-		panic("gsp: Decoder.DecodeTo: implement multi-channel decoding")
+	sampleBuf.Grow(frames)
+	frameBuf := sampleBuf.AvailableBuffer()[:frames]
+
+	framesDecoded, err := d.decodeFrames(frameBuf, buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("gsp: Decoder.DecodeTo: %w", err)
 	}
 
-	samplesDecoded, err := w.Write(sampleBuf.Frames())
+	framesWritten, err := w.Write(frameBuf[:framesDecoded])
 	if err != nil {
 		return fmt.Errorf("gsp: Decoder.DecodeTo: writing frames: %w", err)
 	}
 
-	d.samplesDecoded.Add(int64(samplesDecoded))
+	d.samplesDecoded.Add(int64(framesWritten * d.channels))
 
 	return nil
 }
 
+// decodeFrames decodes the interleaved samples in src into dst and returns the number of complete frames decoded.
+func (d *Decoder[F, T]) decodeFrames(dst []F, src []byte) (int, error) {
+	if len(dst) == 0 {
+		return 0, nil
+	}
+
+	if !d.multiChannel {
+		// Mono and stereo frames are contiguous in memory, so they can be decoded as a single sample slice.
+		samplesDecoded := d.decodeMono(unsafe.Slice((*T)(unsafe.Pointer(&dst[0])), d.channels*len(dst)), src)
+		return samplesDecoded / d.channels, nil
+	}
+
+	if d.channels == 0 {
+		d.channels = len(*(*[]T)(unsafe.Pointer(&dst[0])))
+		if d.channels == 0 {
+			return 0, ErrUnknownChannelCount
+		}
+	}
+
+	frameSize := d.channels * d.byteSize
+	frames := min(len(dst), len(src)/frameSize)
+
+	for i := range frames {
+		frame := (*[]T)(unsafe.Pointer(&dst[i]))
+		if len(*frame) != d.channels {
+			*frame = make([]T, d.channels)
+		}
+
+		_ = d.decodeMono(*frame, src[i*frameSize:(i+1)*frameSize])
+	}
+
+	return frames, nil
+}
+
 func (d *Decoder[F, T]) decodeMono(dst []T, src []byte) int {
+	if d.packing != PackingNone {
+		return decode24(dst, src, d.packing, d.bigEndian)
+	}
+
 	switch d.byteSize {
 	case 1: // 8 bit
 		minLen := min(len(dst), len(src))
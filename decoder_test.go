@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"math"
 	"math/rand/v2"
+	"slices"
 	"testing"
 	"unsafe"
 
@@ -191,3 +192,96 @@ func testDecodeMono[T gsp.Type](t *testing.T, input []byte, want []T) {
 		}
 	}
 }
+
+func TestDecoderDecodeInterleaved(t *testing.T) {
+	t.Parallel()
+
+	N := 10
+
+	t.Run("int16 stereo", func(t *testing.T) {
+		t.Parallel()
+
+		input := make([]byte, 2*2*N)
+		expected := make([]gsp.Stereo[int16], N)
+		for i := range N {
+			expected[i] = gsp.ToStereo(int16(rand.IntN(math.MaxInt16)), int16(-rand.IntN(math.MaxInt16)))
+			binary.LittleEndian.PutUint16(input[4*i:4*i+2], uint16(expected[i].L()))
+			binary.LittleEndian.PutUint16(input[4*i+2:4*(i+1)], uint16(expected[i].R()))
+		}
+
+		testDecodeFrames[gsp.Stereo[int16], int16](t, input, expected, 2)
+	})
+
+	t.Run("int32 stereo big-endian", func(t *testing.T) {
+		t.Parallel()
+
+		input := make([]byte, 2*4*N)
+		expected := make([]gsp.Stereo[int32], N)
+		for i := range N {
+			expected[i] = gsp.ToStereo(rand.Int32(), -rand.Int32())
+			binary.BigEndian.PutUint32(input[8*i:8*i+4], uint32(expected[i].L()))
+			binary.BigEndian.PutUint32(input[8*i+4:8*(i+1)], uint32(expected[i].R()))
+		}
+
+		testDecodeFrames[gsp.Stereo[int32], int32](t, input, expected, 2, gsp.EncodingBigEndian)
+	})
+
+	t.Run("float32 multi-channel", func(t *testing.T) {
+		t.Parallel()
+
+		channels := 6
+
+		input := make([]byte, 0, 4*channels*N)
+		expected := make([]gsp.MultiChannel[float32], N)
+		for i := range N {
+			expected[i] = make(gsp.MultiChannel[float32], channels)
+			for j := range channels {
+				expected[i][j] = 2*rand.Float32() - 1
+				input = binary.LittleEndian.AppendUint32(input, math.Float32bits(expected[i][j]))
+			}
+		}
+
+		testDecodeFrames[gsp.MultiChannel[float32], float32](t, input, expected, channels, gsp.EncodingChannels(channels))
+	})
+}
+
+func testDecodeFrames[F gsp.Frame[T], T gsp.Type](t *testing.T, input []byte, want []F, channels int, opts ...gsp.EncodingOption) {
+	t.Helper()
+
+	decoder := gsp.NewDecoder[F, T](bytes.NewReader(input), opts...)
+
+	if decoder.Channels() != channels {
+		t.Errorf("wrong number of channels: got '%d', want '%d'", decoder.Channels(), channels)
+	}
+
+	frames := make([]F, len(want))
+
+	err := decoder.Decode(frames)
+	if err != nil {
+		t.Fatalf("decoding frames: error: %s", err.Error())
+	}
+
+	for i := range frames {
+		if !slices.Equal(frameSamples[F, T](frames[i]), frameSamples[F, T](want[i])) {
+			t.Errorf("frame mismatch at index '%d': got '%v', want '%v'", i, frames[i], want[i])
+		}
+	}
+}
+
+// frameSamples returns the samples of any frame type as a slice.
+func frameSamples[F gsp.Frame[T], T gsp.Type](frame F) []T {
+	switch f := any(frame).(type) {
+	case T:
+		return []T{f}
+	case gsp.Stereo[T]:
+		return f[:]
+	case [2]T:
+		return f[:]
+	case gsp.MultiChannel[T]:
+		return f
+	case []T:
+		return f
+	default:
+		panic("unknown frame type")
+	}
+}
@@ -0,0 +1,147 @@
+package gsp
+
+import (
+	"math"
+	"math/rand/v2"
+)
+
+// Ditherer converts floating-point samples to integer samples with dither and noise shaping.
+// Noise shaping feeds the quantization error of each channel back into the following samples,
+// so a Ditherer must be used for a single stream of interleaved samples.
+type Ditherer[O Type, I Float] struct {
+	quantizer *quantizer
+	cfg       ConvertConfig
+	limits    integerRange
+	quantize  bool
+}
+
+// NewDitherer returns a [Ditherer] for a stream of interleaved samples with the given number of channels.
+func NewDitherer[O Type, I Float](channels int, opts ...ConvertOption) *Ditherer[O, I] {
+	cfg := newConvertConfig(opts...)
+
+	switch cfg.Dither {
+	case DitherNone, DitherRPDF, DitherTPDF:
+	default:
+		panic("gsp: NewDitherer: dither not implemented")
+	}
+
+	d := &Ditherer[O, I]{
+		quantizer: newQuantizer(&cfg, max(1, channels)),
+		cfg:       cfg,
+	}
+
+	// Floating-point outputs are not quantized.
+	d.quantize = !isFloat[O]()
+	if d.quantize {
+		d.limits = newIntegerRange[O](cfg.Scaling)
+	}
+
+	return d
+}
+
+// Reset clears the error history and restarts the dither noise generator from its seed.
+func (d *Ditherer[O, I]) Reset() {
+	d.quantizer.reset()
+}
+
+// ConvertSlice converts the interleaved samples in to out, and returns the number of samples converted.
+// Conversions to floating-point samples are passed to [ConvertSlice].
+func (d *Ditherer[O, I]) ConvertSlice(out []O, in []I) (length int) {
+	if !d.quantize {
+		return ConvertSlice(out, in)
+	}
+
+	length = min(len(out), len(in))
+
+	for i := range length {
+		out[i] = O(d.quantizer.process(float64(in[i]), &d.limits, &d.cfg) + d.limits.zero)
+	}
+
+	return length
+}
+
+// quantizer adds dither and noise shaping to floating-point samples before they are quantized to integers.
+type quantizer struct {
+	filter   []float64
+	uniform  func() float64 // Uniform noise in [0, 1).
+	source   *rand.PCG
+	errors   [][]float64 // Per channel history of quantization errors, newest first.
+	channel  int         // Channel of the next sample.
+	channels int         // Number of interleaved channels, zero if the channel is reset at the start of every frame.
+	dither   Dither
+	seed     uint64
+}
+
+// newQuantizer returns a quantizer with the dither, noise shaping and seed of cfg.
+func newQuantizer(cfg *ConvertConfig, channels int) *quantizer {
+	switch cfg.Dither {
+	case DitherNone, DitherRPDF, DitherTPDF:
+	default:
+		panic("gsp: Dither: dither not implemented")
+	}
+
+	q := &quantizer{
+		filter:   cfg.NoiseShaping.coefficients(),
+		source:   rand.NewPCG(cfg.Seed, cfg.Seed),
+		errors:   make([][]float64, channels),
+		channels: channels,
+		dither:   cfg.Dither,
+		seed:     cfg.Seed,
+	}
+
+	q.uniform = rand.New(q.source).Float64
+
+	for c := range q.errors {
+		q.errors[c] = make([]float64, len(q.filter))
+	}
+
+	return q
+}
+
+// reset clears the error history and restarts the dither noise generator from its seed.
+func (q *quantizer) reset() {
+	for c := range q.errors {
+		clear(q.errors[c])
+	}
+
+	q.source.Seed(q.seed, q.seed)
+	q.channel = 0
+}
+
+// process quantizes a single sample of the next channel, and returns the signed integer value.
+func (q *quantizer) process(sample float64, limits *integerRange, cfg *ConvertConfig) float64 {
+	if q.channel == len(q.errors) {
+		q.errors = append(q.errors, make([]float64, len(q.filter)))
+	}
+
+	errors := q.errors[q.channel]
+
+	q.channel++
+	if q.channel == q.channels {
+		q.channel = 0
+	}
+
+	shaped := sample * limits.scale
+	for k, h := range q.filter {
+		shaped -= h * errors[k]
+	}
+
+	var noise float64
+
+	switch q.dither {
+	case DitherRPDF:
+		noise = q.uniform() - 0.5
+	case DitherTPDF:
+		noise = q.uniform() - q.uniform()
+	}
+
+	quantized := math.Round(shaped + noise)
+
+	if len(errors) > 0 {
+		copy(errors[1:], errors)
+		// The error is taken before clipping, which would otherwise make the feedback loop unstable.
+		errors[0] = quantized - shaped
+	}
+
+	return limits.limit(quantized, cfg)
+}
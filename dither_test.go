@@ -0,0 +1,232 @@
+package gsp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDithererNone(t *testing.T) {
+	t.Parallel()
+
+	input := []float32{-1.5, -1, -0.5, -0.001, 0, 0.25, 0.5, 1, 1.5}
+
+	want := make([]int16, len(input))
+	ConvertSlice(want, input)
+
+	got := make([]int16, len(input))
+	NewDitherer[int16, float32](1).ConvertSlice(got, input)
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("wrong sample at index '%d': got '%d', want '%d'", i, got[i], want[i])
+		}
+	}
+
+	gotUnsigned := make([]uint8, len(input))
+	wantUnsigned := make([]uint8, len(input))
+	ConvertSlice(wantUnsigned, input)
+	NewDitherer[uint8, float32](1).ConvertSlice(gotUnsigned, input)
+
+	for i := range wantUnsigned {
+		if gotUnsigned[i] != wantUnsigned[i] {
+			t.Errorf("wrong unsigned sample at index '%d': got '%d', want '%d'", i, gotUnsigned[i], wantUnsigned[i])
+		}
+	}
+}
+
+func TestDithererLinearization(t *testing.T) {
+	t.Parallel()
+
+	// A constant level of a quarter LSB is lost without dither, but preserved on average with dither.
+	N := 1 << 16
+	level := 0.25 / maxInt16_64
+
+	input := make([]float64, N)
+	for i := range input {
+		input[i] = level
+	}
+
+	tests := []struct {
+		name   string
+		dither Dither
+		want   float64
+	}{
+		{"none", DitherNone, 0},
+		{"RPDF", DitherRPDF, 0.25},
+		{"TPDF", DitherTPDF, 0.25},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			output := make([]int16, N)
+			NewDitherer[int16, float64](1, ConvertDither(test.dither), ConvertSeed(1)).ConvertSlice(output, input)
+
+			var sum float64
+			for _, sample := range output {
+				sum += float64(sample)
+			}
+
+			if mean := sum / float64(N); math.Abs(mean-test.want) > 0.02 {
+				t.Errorf("wrong mean: got '%g', want '%g'", mean, test.want)
+			}
+		})
+	}
+}
+
+func TestDithererNoiseShaping(t *testing.T) {
+	t.Parallel()
+
+	N := 1 << 13
+
+	input := make([]float64, N)
+	for i := range input {
+		input[i] = 0.1 * math.Sin(2*math.Pi*0.01*float64(i))
+	}
+
+	// bandError returns the power of the quantization error between 2.6 and 4.4 kHz at 44.1 kHz, where hearing is most sensitive.
+	bandError := func(shaping NoiseShaping) float64 {
+		output := make([]int16, N)
+		NewDitherer[int16, float64](1, ConvertDither(DitherTPDF), ConvertNoiseShaping(shaping)).ConvertSlice(output, input)
+
+		var power float64
+
+		for k := N * 6 / 100; k < N*10/100; k += 16 {
+			var re, im float64
+
+			for n := range N {
+				e := float64(output[n]) - input[n]*maxInt16_64
+				sin, cos := math.Sincos(2 * math.Pi * float64(k) * float64(n) / float64(N))
+				re += e * cos
+				im -= e * sin
+			}
+
+			power += re*re + im*im
+		}
+
+		return power
+	}
+
+	flat := bandError(NoiseShapingNone)
+
+	for _, shaping := range []NoiseShaping{NoiseShapingFirstOrder, NoiseShapingLipshitz, NoiseShapingFWeighted} {
+		if shaped := bandError(shaping); shaped > flat/2 {
+			t.Errorf("wrong band error with %s noise shaping: got '%g', want below '%g'", shaping, shaped, flat/2)
+		}
+	}
+}
+
+func TestDithererReset(t *testing.T) {
+	t.Parallel()
+
+	input := make([]float32, 1024)
+	for i := range input {
+		input[i] = float32(0.3 * math.Sin(float64(i)))
+	}
+
+	d := NewDitherer[int8, float32](2, ConvertDither(DitherTPDF), ConvertNoiseShaping(NoiseShapingLipshitz), ConvertSeed(42))
+
+	first := make([]int8, len(input))
+	d.ConvertSlice(first, input)
+
+	d.Reset()
+
+	second := make([]int8, len(input))
+	d.ConvertSlice(second[:100], input[:100])
+	d.ConvertSlice(second[100:], input[100:])
+
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("wrong sample at index '%d' after reset: got '%d', want '%d'", i, second[i], first[i])
+		}
+	}
+}
+
+func TestConvertSliceDither(t *testing.T) {
+	t.Parallel()
+
+	input := make([]float64, 1024)
+	for i := range input {
+		input[i] = 0.3 * math.Sin(float64(i))
+	}
+
+	opts := []ConvertOption{ConvertDither(DitherTPDF), ConvertNoiseShaping(NoiseShapingLipshitz), ConvertSeed(7), ConvertFormat(Format{Channels: 2})}
+
+	want := make([]int16, len(input))
+	NewDitherer[int16, float64](2, opts...).ConvertSlice(want, input)
+
+	got := make([]int16, len(input))
+	ConvertSlice(got, input, opts...)
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("wrong sample at index '%d': got '%d', want '%d'", i, got[i], want[i])
+		}
+	}
+}
+
+func TestConverterDither(t *testing.T) {
+	t.Parallel()
+
+	input := make([]Stereo[float64], 512)
+	interleaved := make([]float64, 2*len(input))
+
+	for i := range input {
+		input[i] = ToStereo(0.3*math.Sin(float64(i)), 0.2*math.Cos(0.1*float64(i)))
+		interleaved[2*i], interleaved[2*i+1] = input[i].L(), input[i].R()
+	}
+
+	opts := []ConvertOption{ConvertDither(DitherRPDF), ConvertNoiseShaping(NoiseShapingFirstOrder), ConvertSeed(3)}
+
+	// The converter shapes the noise of each channel separately, as a ditherer of interleaved samples does.
+	want := make([]int8, len(interleaved))
+	NewDitherer[int8, float64](2, opts...).ConvertSlice(want, interleaved)
+
+	converter := NewConverter[Stereo[float64], Stereo[int8], float64, int8](64, opts...)
+
+	go func() {
+		_, _ = converter.Write(input)
+	}()
+
+	output := make([]Stereo[int8], len(input))
+	_, _ = converter.Read(output)
+
+	for i := range output {
+		if output[i].L() != want[2*i] || output[i].R() != want[2*i+1] {
+			t.Fatalf("wrong frame at index '%d': got '%v', want '%v'", i, output[i], ToStereo(want[2*i], want[2*i+1]))
+		}
+	}
+}
+
+func TestConvertTypeDither(t *testing.T) {
+	t.Parallel()
+
+	// A quarter LSB is lost without dither, but preserved on average with dither.
+	N := 1 << 16
+	level := 0.25 / maxInt16_64
+
+	tests := []struct {
+		name   string
+		dither Dither
+		want   float64
+	}{
+		{"none", DitherNone, 0},
+		{"TPDF", DitherTPDF, 0.25},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var sum float64
+			for range N {
+				sum += float64(ConvertType[int16](level, ConvertDither(test.dither)))
+			}
+
+			if mean := sum / float64(N); math.Abs(mean-test.want) > 0.02 {
+				t.Errorf("wrong mean: got '%g', want '%g'", mean, test.want)
+			}
+		})
+	}
+}
@@ -23,7 +23,10 @@ type Encoder[F Frame[T], T Type] struct {
 	samplePool                   *Pool[F, T]
 	samplesEncoded, bytesWritten atomic.Int64
 	channels, byteSize           int
+	sampleRate                   int
+	packing                      Packing
 	bigEndian                    bool
+	multiChannel                 bool
 	initialized                  bool
 }
 
@@ -35,28 +38,39 @@ func NewEncoder[F Frame[T], T Type](w io.Writer, opts ...EncodingOption) *Encode
 	}
 
 	var channels int
+	var multiChannel bool
 
-	switch frameType := any(*new(F)).(type) {
+	switch any(*new(F)).(type) {
 	case T:
 		channels = 1
 	case [2]T, Stereo[T]:
 		channels = 2
-	case []T:
-		channels = len(frameType)
-	case MultiChannel[T]:
-		channels = len(frameType)
+	case []T, MultiChannel[T]:
+		// Channel count is only known at runtime, if not configured it is taken from the first frame.
+		channels = cfg.Channels
+		multiChannel = true
 	default:
 		panic("gsp: NewEncoder: unknown audio frame type")
 	}
 
+	checkPacking[T](cfg.Packing, "NewEncoder")
+
+	byteSize := int(unsafe.Sizeof(T(0)))
+	if cfg.Packing != PackingNone {
+		byteSize = cfg.Packing.byteSize()
+	}
+
 	return &Encoder[F, T]{
-		w:           w,
-		bytePool:    NewByteBufferPool(),
-		samplePool:  NewPool[F, T](),
-		channels:    channels,
-		byteSize:    int(unsafe.Sizeof(T(0))),
-		bigEndian:   cfg.BigEndian,
-		initialized: true,
+		w:            w,
+		bytePool:     NewByteBufferPool(),
+		samplePool:   NewPool[F, T](),
+		channels:     channels,
+		byteSize:     byteSize,
+		sampleRate:   cfg.SampleRate,
+		packing:      cfg.Packing,
+		bigEndian:    cfg.BigEndian,
+		multiChannel: multiChannel,
+		initialized:  true,
 	}
 }
 
@@ -64,14 +78,33 @@ func (e *Encoder[F, T]) Channels() int {
 	return e.channels
 }
 
+// ByteSize returns the size of a single encoded sample in bytes.
 func (e *Encoder[F, T]) ByteSize() int {
 	return e.byteSize
 }
 
+// Format returns the format of the encoded stream.
+// The channel count of multi-channel streams is zero until it is known.
+func (e *Encoder[F, T]) Format() Format {
+	return Format{
+		SampleRate: e.sampleRate,
+		Channels:   e.channels,
+		SampleType: SampleTypeOf[T](),
+		BigEndian:  e.bigEndian,
+		Packing:    e.packing,
+		Layout:     LayoutInterleaved,
+	}
+}
+
 // Encode reads from the sample slice and decodes into the internal [io.Writer].
+// Stereo and multi-channel frames are encoded interleaved.
 func (e *Encoder[F, T]) Encode(s []F) error {
 	if !e.initialized {
-		return ErrDecoderNotinitialized
+		return ErrEncoderNotinitialized
+	}
+
+	if len(s) == 0 {
+		return nil
 	}
 
 	buf := e.bytePool.Get()
@@ -82,17 +115,13 @@ func (e *Encoder[F, T]) Encode(s []F) error {
 
 	defer e.bytePool.Put(buf)
 
-	switch e.channels {
-	case 1:
-		// There is only a single channel, so we can safely perform this unsafe type-casting.
-		samplesEncoded := e.encodeMono(buf, unsafe.Slice((*T)(unsafe.Pointer(&s[0])), len(s)))
-		e.samplesEncoded.Add(int64(samplesEncoded))
-	case 2:
-		panic("gsp: Encoder.Encode: stereo encoding not implemented")
-	default:
-		panic("gsp: Encoder.Encode: multi-channel encoding not implemented")
+	samplesEncoded, err := e.encodeFrames(buf, s)
+	if err != nil {
+		return fmt.Errorf("gsp: Encoder.Encode: %w", err)
 	}
 
+	e.samplesEncoded.Add(int64(samplesEncoded))
+
 	bytesWritten, err := buf.WriteTo(e.w)
 	if err != nil {
 		return fmt.Errorf("gsp: Encoder.Encode: writing bytes: %w", err)
@@ -106,7 +135,7 @@ func (e *Encoder[F, T]) Encode(s []F) error {
 // Encode EncodeFrom from the [Reader] and decodes into the internal [io.Writer].
 func (e *Encoder[F, T]) EncodeFrom(r Reader[F, T]) error {
 	if !e.initialized {
-		return ErrDecoderNotinitialized
+		return ErrEncoderNotinitialized
 	}
 
 	sampleBuf := e.samplePool.Get()
@@ -125,8 +154,41 @@ func (e *Encoder[F, T]) EncodeFrom(r Reader[F, T]) error {
 	return e.Encode(sampleBuf.Frames())
 }
 
+// encodeFrames writes the interleaved samples of all frames to buf and returns the number of samples encoded.
+func (e *Encoder[F, T]) encodeFrames(buf *bytes.Buffer, src []F) (int, error) {
+	if !e.multiChannel {
+		// Mono and stereo frames are contiguous in memory, so they can be encoded as a single sample slice.
+		return e.encodeMono(buf, unsafe.Slice((*T)(unsafe.Pointer(&src[0])), e.channels*len(src))), nil
+	}
+
+	if e.channels == 0 {
+		e.channels = len(*(*[]T)(unsafe.Pointer(&src[0])))
+		if e.channels == 0 {
+			return 0, ErrUnknownChannelCount
+		}
+	}
+
+	samplesEncoded := 0
+
+	for i := range src {
+		frame := *(*[]T)(unsafe.Pointer(&src[i]))
+		if len(frame) != e.channels {
+			return samplesEncoded, ErrChannelMismatch
+		}
+
+		samplesEncoded += e.encodeMono(buf, frame)
+	}
+
+	return samplesEncoded, nil
+}
+
 // encodeMono writes the encoded sampled to the internal [io.Writer].
 func (e *Encoder[F, T]) encodeMono(buf *bytes.Buffer, src []T) int {
+	if e.packing != PackingNone {
+		_, _ = buf.Write(append24(buf.AvailableBuffer(), src, e.packing, e.bigEndian))
+		return len(src)
+	}
+
 	switch e.byteSize {
 	case 1: // 8 bit
 		// Abuse overflow rules to deduce specific type.
@@ -3,6 +3,7 @@ package gsp_test
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"math"
 	"math/rand/v2"
 	"testing"
@@ -183,3 +184,118 @@ func testEncodeMono[T gsp.Type](t *testing.T, input []T, want []byte) {
 		}
 	}
 }
+
+func TestEncoderEncodeInterleaved(t *testing.T) {
+	t.Parallel()
+
+	N := 10
+
+	t.Run("int16 stereo", func(t *testing.T) {
+		t.Parallel()
+
+		input := make([]gsp.Stereo[int16], N)
+		for i := range N {
+			input[i] = gsp.ToStereo(int16(rand.IntN(math.MaxInt16)), int16(-rand.IntN(math.MaxInt16)))
+		}
+
+		want := make([]byte, 2*2*N)
+		for i := range N {
+			binary.LittleEndian.PutUint16(want[4*i:4*i+2], uint16(input[i].L()))
+			binary.LittleEndian.PutUint16(want[4*i+2:4*(i+1)], uint16(input[i].R()))
+		}
+
+		testEncodeFrames[gsp.Stereo[int16], int16](t, input, want, 2)
+	})
+
+	t.Run("float32 stereo big-endian", func(t *testing.T) {
+		t.Parallel()
+
+		input := make([]gsp.Stereo[float32], N)
+		for i := range N {
+			input[i] = gsp.ToStereo(2*rand.Float32()-1, 2*rand.Float32()-1)
+		}
+
+		want := make([]byte, 2*4*N)
+		for i := range N {
+			binary.BigEndian.PutUint32(want[8*i:8*i+4], math.Float32bits(input[i].L()))
+			binary.BigEndian.PutUint32(want[8*i+4:8*(i+1)], math.Float32bits(input[i].R()))
+		}
+
+		testEncodeFrames[gsp.Stereo[float32], float32](t, input, want, 2, gsp.EncodingBigEndian)
+	})
+
+	t.Run("uint8 multi-channel", func(t *testing.T) {
+		t.Parallel()
+
+		channels := 6
+
+		input := make([]gsp.MultiChannel[uint8], N)
+		want := make([]byte, 0, channels*N)
+		for i := range N {
+			input[i] = make(gsp.MultiChannel[uint8], channels)
+			for j := range channels {
+				input[i][j] = uint8(rand.UintN(math.MaxUint8))
+				want = append(want, input[i][j])
+			}
+		}
+
+		testEncodeFrames[gsp.MultiChannel[uint8], uint8](t, input, want, channels)
+	})
+
+	t.Run("float64 multi-channel", func(t *testing.T) {
+		t.Parallel()
+
+		channels := 3
+
+		input := make([]gsp.MultiChannel[float64], N)
+		want := make([]byte, 0, 8*channels*N)
+		for i := range N {
+			input[i] = make(gsp.MultiChannel[float64], channels)
+			for j := range channels {
+				input[i][j] = 2*rand.Float64() - 1
+				want = binary.LittleEndian.AppendUint64(want, math.Float64bits(input[i][j]))
+			}
+		}
+
+		testEncodeFrames[gsp.MultiChannel[float64], float64](t, input, want, channels)
+	})
+
+	t.Run("multi-channel mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		encoder := gsp.NewEncoder[gsp.MultiChannel[int16], int16](new(bytes.Buffer), gsp.EncodingChannels(2))
+
+		err := encoder.Encode([]gsp.MultiChannel[int16]{{1, 2, 3}})
+		if !errors.Is(err, gsp.ErrChannelMismatch) {
+			t.Errorf("wrong error: got '%v', want '%v'", err, gsp.ErrChannelMismatch)
+		}
+	})
+}
+
+func testEncodeFrames[F gsp.Frame[T], T gsp.Type](t *testing.T, input []F, want []byte, channels int, opts ...gsp.EncodingOption) {
+	t.Helper()
+
+	buf := new(bytes.Buffer)
+	encoder := gsp.NewEncoder[F, T](buf, opts...)
+
+	err := encoder.Encode(input)
+	if err != nil {
+		t.Fatalf("encoding frames: error: %s", err.Error())
+	}
+
+	if encoder.Channels() != channels {
+		t.Errorf("wrong number of channels: got '%d', want '%d'", encoder.Channels(), channels)
+	}
+
+	output := buf.Bytes()
+
+	if len(output) != len(want) {
+		t.Fatalf("missing bytes: got '%d', want '%d'", len(output), len(want))
+	}
+
+	for i := range output {
+		if output[i] != want[i] {
+			t.Errorf("binary mismatch at index '%d': got '%v', want '%v'", i, output[i], want[i])
+		}
+	}
+}
@@ -5,10 +5,61 @@ type EncodingOption func(cfg *EncodingConfig)
 
 // EncodingConfig contains all configuration options for [LPCMEncoder] and [LPCMDecoder].
 type EncodingConfig struct {
-	BigEndian bool // Enable big-endian encoding for bit sizes above 8-bit.
+	BigEndian  bool    // Enable big-endian encoding for bit sizes above 8-bit.
+	Channels   int     // Number of interleaved channels of a multi-channel stream, only used for [MultiChannel] frames.
+	SampleRate int     // Sample rate of the stream, only used to report the [Format].
+	Packing    Packing // Container of 24-bit samples, only supported for int32 and float32 samples.
+}
+
+// Packing is the container of encoded 24-bit samples.
+// Decoded int32 samples hold the 24-bit value in the most significant bytes,
+// decoded float32 samples are scaled symmetrically to [-1, 1].
+type Packing int
+
+const (
+	PackingNone        Packing = iota // Samples are encoded with the size of the sample type.
+	Packing24                         // 24-bit samples packed in 3 bytes.
+	Packing24In32Left                 // 24-bit samples in the most significant bytes of a 4 byte container.
+	Packing24In32Right                // 24-bit samples in the least significant bytes of a 4 byte container, sign-extended.
+)
+
+// byteSize returns the size of a single encoded sample, or zero if the size of the sample type is used.
+func (p Packing) byteSize() int {
+	switch p {
+	case PackingNone:
+		return 0
+	case Packing24:
+		return 3
+	case Packing24In32Left, Packing24In32Right:
+		return 4
+	default:
+		panic("gsp: Packing: packing not implemented")
+	}
 }
 
 // EncodingBigEndian enables big-endian encoding instead of little-endian encoding.
 func EncodingBigEndian(cfg *EncodingConfig) {
 	cfg.BigEndian = true
 }
+
+// EncodingChannels sets the number of interleaved channels for [MultiChannel] frames.
+// If not set, the channel count is taken from the length of the first frame.
+func EncodingChannels(channels int) EncodingOption {
+	return func(cfg *EncodingConfig) {
+		cfg.Channels = channels
+	}
+}
+
+// EncodingSampleRate sets the sample rate of the stream.
+func EncodingSampleRate(sampleRate int) EncodingOption {
+	return func(cfg *EncodingConfig) {
+		cfg.SampleRate = sampleRate
+	}
+}
+
+// EncodingPacking sets the container of 24-bit samples.
+func EncodingPacking(packing Packing) EncodingOption {
+	return func(cfg *EncodingConfig) {
+		cfg.Packing = packing
+	}
+}
@@ -1 +1,8 @@
 package gsp
+
+import "errors"
+
+var (
+	ErrChannelMismatch     = errors.New("gsp: frame channel count does not match stream channel count")
+	ErrUnknownChannelCount = errors.New("gsp: unknown channel count")
+)
@@ -0,0 +1,48 @@
+package fft
+
+import (
+	"github.com/samborkent/gsp"
+	"github.com/samborkent/gsp/internal/gmath"
+)
+
+// Complex is a complex number of any floating-point type.
+// Its memory layout matches complex64 and complex128 for float32 and float64 respectively.
+type Complex[T gsp.Float] struct {
+	Re, Im T
+}
+
+// Abs returns the magnitude of c.
+func (c Complex[T]) Abs() T {
+	return gmath.Hypot(c.Re, c.Im)
+}
+
+func (c Complex[T]) Add(x Complex[T]) Complex[T] {
+	return Complex[T]{c.Re + x.Re, c.Im + x.Im}
+}
+
+// Arg returns the phase of c in radians.
+func (c Complex[T]) Arg() T {
+	return gmath.Atan2(c.Im, c.Re)
+}
+
+// Conj returns the complex conjugate of c.
+func (c Complex[T]) Conj() Complex[T] {
+	return Complex[T]{c.Re, -c.Im}
+}
+
+func (c Complex[T]) Multiply(x Complex[T]) Complex[T] {
+	return Complex[T]{c.Re*x.Re - c.Im*x.Im, c.Re*x.Im + c.Im*x.Re}
+}
+
+// Power returns the squared magnitude of c.
+func (c Complex[T]) Power() T {
+	return c.Re*c.Re + c.Im*c.Im
+}
+
+func (c Complex[T]) Scale(x T) Complex[T] {
+	return Complex[T]{c.Re * x, c.Im * x}
+}
+
+func (c Complex[T]) Subtract(x Complex[T]) Complex[T] {
+	return Complex[T]{c.Re - x.Re, c.Im - x.Im}
+}
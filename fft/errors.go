@@ -0,0 +1,8 @@
+package fft
+
+import "errors"
+
+var (
+	ErrChannelMismatch = errors.New("gsp: ISTFT: channel count does not match previous frames")
+	ErrSpectrumSize    = errors.New("gsp: ISTFT: spectrum size does not match frame size")
+)
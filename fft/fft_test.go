@@ -0,0 +1,142 @@
+package fft_test
+
+import (
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"testing"
+
+	"github.com/samborkent/gsp/fft"
+)
+
+var sizes = []int{1, 2, 3, 4, 5, 6, 7, 8, 12, 15, 16, 30, 49, 64, 100, 127, 256, 360, 1024}
+
+func TestPlanForward(t *testing.T) {
+	t.Parallel()
+
+	for _, n := range sizes {
+		t.Run(strconv.Itoa(n), func(t *testing.T) {
+			t.Parallel()
+
+			src := randomComplex(n)
+			dst := make([]fft.Complex[float64], n)
+
+			fft.NewPlan[float64](n).Forward(dst, src)
+
+			want := dft(src)
+			for k := range dst {
+				if dst[k].Subtract(want[k]).Abs() > 1e-9*float64(n) {
+					t.Fatalf("wrong bin %d: got '%v', want '%v'", k, dst[k], want[k])
+				}
+			}
+		})
+	}
+}
+
+func TestPlanRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, n := range sizes {
+		t.Run(strconv.Itoa(n), func(t *testing.T) {
+			t.Parallel()
+
+			src := randomComplex(n)
+			data := make([]fft.Complex[float64], n)
+			copy(data, src)
+
+			plan := fft.NewPlan[float64](n)
+			plan.Forward(data, data)
+			plan.Inverse(data, data)
+
+			for i := range data {
+				if data[i].Subtract(src[i]).Abs() > 1e-12*float64(n) {
+					t.Fatalf("wrong sample %d: got '%v', want '%v'", i, data[i], src[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRealPlan(t *testing.T) {
+	t.Parallel()
+
+	for _, n := range sizes {
+		t.Run(strconv.Itoa(n), func(t *testing.T) {
+			t.Parallel()
+
+			src := make([]float32, n)
+			complexSrc := make([]fft.Complex[float64], n)
+
+			for i := range src {
+				src[i] = 2*rand.Float32() - 1
+				complexSrc[i] = fft.Complex[float64]{Re: float64(src[i])}
+			}
+
+			plan := fft.NewRealPlan[float32](n)
+			spectrum := make([]fft.Complex[float32], plan.Bins())
+			plan.Forward(spectrum, src)
+
+			want := dft(complexSrc)
+			for k := range spectrum {
+				got := fft.Complex[float64]{Re: float64(spectrum[k].Re), Im: float64(spectrum[k].Im)}
+				if got.Subtract(want[k]).Abs() > 1e-5*float64(n) {
+					t.Fatalf("wrong bin %d: got '%v', want '%v'", k, got, want[k])
+				}
+			}
+
+			dst := make([]float32, n)
+			plan.Inverse(dst, spectrum)
+
+			for i := range dst {
+				if math.Abs(float64(dst[i]-src[i])) > 1e-5 {
+					t.Fatalf("wrong sample %d: got '%v', want '%v'", i, dst[i], src[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPlanAllocations(t *testing.T) {
+	const n = 480
+
+	plan := fft.NewPlan[float32](n)
+	data := make([]fft.Complex[float32], n)
+
+	realPlan := fft.NewRealPlan[float32](n)
+	signal := make([]float32, n)
+	spectrum := make([]fft.Complex[float32], realPlan.Bins())
+
+	allocs := testing.AllocsPerRun(10, func() {
+		plan.Forward(data, data)
+		plan.Inverse(data, data)
+		realPlan.Forward(spectrum, signal)
+		realPlan.Inverse(signal, spectrum)
+	})
+
+	if allocs != 0 {
+		t.Errorf("wrong allocations: got '%v', want '%v'", allocs, 0)
+	}
+}
+
+func dft(src []fft.Complex[float64]) []fft.Complex[float64] {
+	n := len(src)
+	dst := make([]fft.Complex[float64], n)
+
+	for k := range n {
+		for i := range n {
+			sin, cos := math.Sincos(-2 * math.Pi * float64((i*k)%n) / float64(n))
+			dst[k] = dst[k].Add(src[i].Multiply(fft.Complex[float64]{Re: cos, Im: sin}))
+		}
+	}
+
+	return dst
+}
+
+func randomComplex(n int) []fft.Complex[float64] {
+	data := make([]fft.Complex[float64], n)
+	for i := range data {
+		data[i] = fft.Complex[float64]{Re: 2*rand.Float64() - 1, Im: 2*rand.Float64() - 1}
+	}
+
+	return data
+}
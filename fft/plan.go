@@ -0,0 +1,196 @@
+// Package fft implements fast Fourier transforms for any floating-point type.
+package fft
+
+import (
+	"math"
+
+	"github.com/samborkent/gsp"
+)
+
+// Plan computes complex discrete Fourier transforms of a fixed size.
+// It uses a mixed-radix Stockham autosort algorithm, with dedicated radix-2 and radix-4 butterflies.
+// Sizes with large prime factors are supported, but are slower.
+// A Plan does not allocate after creation, but is not safe for concurrent use.
+type Plan[T gsp.Float] struct {
+	n      int
+	stages []stage[T]
+	work   []Complex[T]
+	v, w   []Complex[T] // Butterfly input and output of the largest radix.
+}
+
+type stage[T gsp.Float] struct {
+	radix, stride int
+	twiddles      []Complex[T] // stride rows of radix twiddle factors.
+	roots         []Complex[T] // Roots of unity of the radix, only used by the generic butterfly.
+}
+
+// NewPlan returns a [Plan] for transforms of size n.
+func NewPlan[T gsp.Float](n int) *Plan[T] {
+	if n <= 0 {
+		panic("gsp: fft.NewPlan: size must be positive")
+	}
+
+	p := &Plan[T]{
+		n:    n,
+		work: make([]Complex[T], n),
+	}
+
+	maxRadix := 1
+	stride := 1
+
+	for _, radix := range factorize(n) {
+		s := stage[T]{
+			radix:    radix,
+			stride:   stride,
+			twiddles: make([]Complex[T], stride*radix),
+		}
+
+		for k := range stride {
+			for r := range radix {
+				s.twiddles[k*radix+r] = root[T](r*k, stride*radix)
+			}
+		}
+
+		if radix != 2 && radix != 4 {
+			s.roots = make([]Complex[T], radix)
+			for r := range radix {
+				s.roots[r] = root[T](r, radix)
+			}
+		}
+
+		p.stages = append(p.stages, s)
+		maxRadix = max(maxRadix, radix)
+		stride *= radix
+	}
+
+	p.v = make([]Complex[T], maxRadix)
+	p.w = make([]Complex[T], maxRadix)
+
+	return p
+}
+
+// Len returns the transform size.
+func (p *Plan[T]) Len() int {
+	return p.n
+}
+
+// Forward computes the unnormalized forward transform of src into dst.
+// Both slices must have the plan size, and may be the same slice.
+func (p *Plan[T]) Forward(dst, src []Complex[T]) {
+	if len(dst) != p.n || len(src) != p.n {
+		panic("gsp: fft.Plan.Forward: slice length does not match plan size")
+	}
+
+	copy(dst, src)
+	p.transform(dst)
+}
+
+// Inverse computes the inverse transform of src into dst, normalized by the plan size.
+// Both slices must have the plan size, and may be the same slice.
+func (p *Plan[T]) Inverse(dst, src []Complex[T]) {
+	if len(dst) != p.n || len(src) != p.n {
+		panic("gsp: fft.Plan.Inverse: slice length does not match plan size")
+	}
+
+	// The inverse transform is the conjugate of the forward transform of the conjugate.
+	for i := range src {
+		dst[i] = src[i].Conj()
+	}
+
+	p.transform(dst)
+
+	scale := 1 / T(p.n)
+	for i := range dst {
+		dst[i] = Complex[T]{dst[i].Re * scale, -dst[i].Im * scale}
+	}
+}
+
+// transform performs the forward transform in place, using the work buffer for the intermediate stages.
+func (p *Plan[T]) transform(data []Complex[T]) {
+	in, out := data, p.work
+
+	for i := range p.stages {
+		p.pass(&p.stages[i], out, in)
+		in, out = out, in
+	}
+
+	if len(p.stages)%2 != 0 {
+		copy(data, p.work)
+	}
+}
+
+// pass performs a single Stockham stage.
+func (p *Plan[T]) pass(s *stage[T], out, in []Complex[T]) {
+	radix, stride := s.radix, s.stride
+	span := p.n / radix
+	v, w := p.v[:radix], p.w[:radix]
+
+	for j := range span {
+		k := j % stride
+		twiddles := s.twiddles[k*radix : (k+1)*radix]
+
+		for r := range radix {
+			v[r] = in[j+r*span].Multiply(twiddles[r])
+		}
+
+		switch radix {
+		case 2:
+			w[0] = v[0].Add(v[1])
+			w[1] = v[0].Subtract(v[1])
+		case 4:
+			a, b := v[0].Add(v[2]), v[0].Subtract(v[2])
+			c, d := v[1].Add(v[3]), v[1].Subtract(v[3])
+			// Multiply by -i.
+			d = Complex[T]{d.Im, -d.Re}
+
+			w[0] = a.Add(c)
+			w[1] = b.Add(d)
+			w[2] = a.Subtract(c)
+			w[3] = b.Subtract(d)
+		default:
+			for q := range radix {
+				sum := v[0]
+				for r := 1; r < radix; r++ {
+					sum = sum.Add(v[r].Multiply(s.roots[(r*q)%radix]))
+				}
+
+				w[q] = sum
+			}
+		}
+
+		offset := (j/stride)*stride*radix + k
+		for r := range radix {
+			out[offset+r*stride] = w[r]
+		}
+	}
+}
+
+// factorize splits n into radices, preferring radix 4.
+func factorize(n int) []int {
+	var factors []int
+
+	for n%4 == 0 {
+		factors = append(factors, 4)
+		n /= 4
+	}
+
+	for factor := 2; n > 1; factor++ {
+		for n%factor == 0 {
+			factors = append(factors, factor)
+			n /= factor
+		}
+
+		if factor*factor > n && n > 1 {
+			factors = append(factors, n)
+			break
+		}
+	}
+
+	return factors
+}
+
+// root returns exp(-2πi k/n), computed in float64 precision.
+func root[T gsp.Float](k, n int) Complex[T] {
+	sin, cos := math.Sincos(-2 * math.Pi * float64(k%n) / float64(n))
+	return Complex[T]{T(cos), T(sin)}
+}
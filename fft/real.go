@@ -0,0 +1,135 @@
+package fft
+
+import (
+	"github.com/samborkent/gsp"
+)
+
+// RealPlan computes discrete Fourier transforms of real signals of a fixed size.
+// Only the non-negative frequency bins are computed, so spectra have n/2+1 bins.
+// Even sizes are computed with a complex transform of half the size.
+// A RealPlan does not allocate after creation, but is not safe for concurrent use.
+type RealPlan[T gsp.Float] struct {
+	n        int
+	plan     *Plan[T]
+	twiddles []Complex[T]
+	work     []Complex[T]
+}
+
+// NewRealPlan returns a [RealPlan] for transforms of size n.
+func NewRealPlan[T gsp.Float](n int) *RealPlan[T] {
+	if n <= 0 {
+		panic("gsp: fft.NewRealPlan: size must be positive")
+	}
+
+	if n%2 != 0 {
+		return &RealPlan[T]{
+			n:    n,
+			plan: NewPlan[T](n),
+			work: make([]Complex[T], n),
+		}
+	}
+
+	p := &RealPlan[T]{
+		n:        n,
+		plan:     NewPlan[T](n / 2),
+		twiddles: make([]Complex[T], n/2+1),
+		work:     make([]Complex[T], n/2),
+	}
+
+	for k := range p.twiddles {
+		p.twiddles[k] = root[T](k, n)
+	}
+
+	return p
+}
+
+// Len returns the transform size.
+func (p *RealPlan[T]) Len() int {
+	return p.n
+}
+
+// Bins returns the number of frequency bins of a spectrum.
+func (p *RealPlan[T]) Bins() int {
+	return p.n/2 + 1
+}
+
+// Forward computes the unnormalized forward transform of the real signal src into the spectrum dst.
+// src must have the plan size and dst must have [RealPlan.Bins] bins.
+func (p *RealPlan[T]) Forward(dst []Complex[T], src []T) {
+	if len(src) != p.n || len(dst) != p.Bins() {
+		panic("gsp: fft.RealPlan.Forward: slice length does not match plan size")
+	}
+
+	if p.n%2 != 0 {
+		for i := range src {
+			p.work[i] = Complex[T]{src[i], 0}
+		}
+
+		p.plan.transform(p.work)
+		copy(dst, p.work)
+
+		return
+	}
+
+	// Transform even samples as real part and odd samples as imaginary part.
+	half := p.n / 2
+	for i := range half {
+		p.work[i] = Complex[T]{src[2*i], src[2*i+1]}
+	}
+
+	p.plan.transform(p.work)
+
+	for k := range half + 1 {
+		z := p.work[k%half]
+		zc := p.work[(half-k)%half].Conj()
+
+		even := z.Add(zc).Scale(0.5)
+		odd := z.Subtract(zc).Scale(0.5)
+		// Divide by i.
+		odd = Complex[T]{odd.Im, -odd.Re}
+
+		dst[k] = even.Add(p.twiddles[k].Multiply(odd))
+	}
+}
+
+// Inverse computes the inverse transform of the spectrum src into the real signal dst, normalized by the plan size.
+// src must have [RealPlan.Bins] bins and dst must have the plan size.
+func (p *RealPlan[T]) Inverse(dst []T, src []Complex[T]) {
+	if len(dst) != p.n || len(src) != p.Bins() {
+		panic("gsp: fft.RealPlan.Inverse: slice length does not match plan size")
+	}
+
+	if p.n%2 != 0 {
+		// Reconstruct the negative frequencies from the Hermitian symmetry.
+		copy(p.work, src)
+		for k := 1; k < len(src); k++ {
+			p.work[p.n-k] = src[k].Conj()
+		}
+
+		p.plan.Inverse(p.work, p.work)
+
+		for i := range dst {
+			dst[i] = p.work[i].Re
+		}
+
+		return
+	}
+
+	half := p.n / 2
+	for k := range half {
+		x := src[k]
+		xc := src[half-k].Conj()
+
+		even := x.Add(xc).Scale(0.5)
+		odd := x.Subtract(xc).Scale(0.5).Multiply(p.twiddles[k].Conj())
+		// Multiply by i.
+		p.work[k] = even.Add(Complex[T]{-odd.Im, odd.Re})
+	}
+
+	p.plan.Inverse(p.work, p.work)
+
+	for i := range half {
+		dst[2*i] = p.work[i].Re
+		dst[2*i+1] = p.work[i].Im
+	}
+}
@@ -0,0 +1,317 @@
+package fft
+
+import (
+	"io"
+	"unsafe"
+
+	"github.com/samborkent/gsp"
+	"github.com/samborkent/gsp/window"
+)
+
+// STFT computes the short-time Fourier transform of the frames read from a [gsp.Reader].
+// Each analysis frame holds size samples per channel, and consecutive frames are hop samples apart.
+// The first frame is centered such that its last hop samples are the first input samples,
+// which allows [ISTFT] to reconstruct the input without a leading delay.
+type STFT[F gsp.Frame[T], T gsp.Float] struct {
+	r            gsp.Reader[F, T]
+	plan         *RealPlan[T]
+	window       []T
+	size, hop    int
+	channels     int
+	multiChannel bool
+
+	input    []F
+	history  [][]T
+	windowed []T
+	spectra  [][]Complex[T]
+
+	framesRead int64
+	padding    int // Number of zero samples appended after the end of the input.
+	eof        bool
+}
+
+// NewSTFT returns an [STFT] reading from r.
+// The window must have length size, a nil window selects a periodic Hann window.
+func NewSTFT[F gsp.Frame[T], T gsp.Float](r gsp.Reader[F, T], size, hop int, window []T) *STFT[F, T] {
+	window = checkSTFTParameters(size, hop, window, "NewSTFT")
+
+	s := &STFT[F, T]{
+		r:        r,
+		plan:     NewRealPlan[T](size),
+		window:   window,
+		size:     size,
+		hop:      hop,
+		input:    make([]F, hop),
+		windowed: make([]T, size),
+	}
+
+	switch any(*new(F)).(type) {
+	case T:
+		s.allocate(1)
+	case [2]T, gsp.Stereo[T]:
+		s.allocate(2)
+	case []T, gsp.MultiChannel[T]:
+		// Channel count is taken from the first input frame.
+		s.multiChannel = true
+	default:
+		panic("gsp: fft.NewSTFT: unknown audio frame type")
+	}
+
+	return s
+}
+
+// Bins returns the number of frequency bins per channel spectrum.
+func (s *STFT[F, T]) Bins() int {
+	return s.plan.Bins()
+}
+
+// FramesRead returns the number of input frames read so far.
+// It can be used to trim the padding from the output of an [ISTFT].
+func (s *STFT[F, T]) FramesRead() int64 {
+	return s.framesRead
+}
+
+// Next reads the next hop frames from the source and returns the spectrum of each channel.
+// The returned spectra are reused by the next call.
+// After the end of the input, frames are padded with silence until every input sample has been analyzed by all frames covering it,
+// after which Next returns [io.EOF].
+func (s *STFT[F, T]) Next() ([][]Complex[T], error) {
+	read := 0
+
+	for !s.eof && read < s.hop {
+		n, err := s.r.Read(s.input[read:])
+		read += n
+
+		if err == io.EOF {
+			s.eof = true
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	if s.multiChannel && s.history == nil && read > 0 {
+		s.allocate(len(*(*[]T)(unsafe.Pointer(&s.input[0]))))
+	}
+
+	s.framesRead += int64(read)
+
+	if s.eof {
+		s.padding += s.hop - read
+	}
+
+	// Stop once a frame no longer contains any input sample.
+	if s.framesRead == 0 || s.padding >= s.size {
+		return nil, io.EOF
+	}
+
+	for c := range s.channels {
+		history := s.history[c]
+		copy(history, history[s.hop:])
+
+		tail := history[s.size-s.hop:]
+		for i := range read {
+			tail[i] = s.samples(&s.input[i])[c]
+		}
+
+		clear(tail[read:])
+
+		for i := range history {
+			s.windowed[i] = history[i] * s.window[i]
+		}
+
+		s.plan.Forward(s.spectra[c], s.windowed)
+	}
+
+	return s.spectra, nil
+}
+
+func (s *STFT[F, T]) allocate(channels int) {
+	s.channels = channels
+	s.history = make([][]T, channels)
+	s.spectra = make([][]Complex[T], channels)
+
+	for c := range channels {
+		s.history[c] = make([]T, s.size)
+		s.spectra[c] = make([]Complex[T], s.plan.Bins())
+	}
+}
+
+// samples returns the samples of a frame as a slice aliasing the frame.
+func (s *STFT[F, T]) samples(frame *F) []T {
+	if s.multiChannel {
+		return *(*[]T)(unsafe.Pointer(frame))
+	}
+
+	return unsafe.Slice((*T)(unsafe.Pointer(frame)), s.channels)
+}
+
+// ISTFT reconstructs a signal from short-time spectra through weighted overlap-add.
+// Every output sample is normalized by the sum of the squared window values of the frames covering it,
+// which gives perfect reconstruction for unmodified spectra of any window that does not vanish, including all COLA windows.
+// The reconstructed frames can be consumed with [ISTFT.Read] or [ISTFT.WriteTo].
+type ISTFT[F gsp.Frame[T], T gsp.Float] struct {
+	plan         *RealPlan[T]
+	window       []T
+	size, hop    int
+	channels     int
+	multiChannel bool
+
+	acc    [][]T
+	norm   []T
+	signal []T
+	output gsp.Buffer[F, T]
+	skip   int
+}
+
+// NewISTFT returns an [ISTFT] with the given parameters, which must match those of the analyzing [STFT].
+// The window must have length size, a nil window selects a periodic Hann window.
+func NewISTFT[F gsp.Frame[T], T gsp.Float](size, hop int, window []T) *ISTFT[F, T] {
+	window = checkSTFTParameters(size, hop, window, "NewISTFT")
+
+	s := &ISTFT[F, T]{
+		plan:   NewRealPlan[T](size),
+		window: window,
+		size:   size,
+		hop:    hop,
+		norm:   make([]T, size),
+		signal: make([]T, size),
+		skip:   size - hop,
+	}
+
+	switch any(*new(F)).(type) {
+	case T:
+		s.channels = 1
+	case [2]T, gsp.Stereo[T]:
+		s.channels = 2
+	case []T, gsp.MultiChannel[T]:
+		// Channel count is taken from the first spectra.
+		s.multiChannel = true
+	default:
+		panic("gsp: fft.NewISTFT: unknown audio frame type")
+	}
+
+	return s
+}
+
+// Write adds the spectra of one frame, as returned by [STFT.Next], and makes the next hop frames available for reading.
+func (s *ISTFT[F, T]) Write(spectra [][]Complex[T]) error {
+	if s.multiChannel && s.acc == nil {
+		s.channels = len(spectra)
+	}
+
+	if len(spectra) != s.channels || len(spectra) == 0 {
+		return ErrChannelMismatch
+	}
+
+	for c := range spectra {
+		if len(spectra[c]) != s.plan.Bins() {
+			return ErrSpectrumSize
+		}
+	}
+
+	if s.acc == nil {
+		s.acc = make([][]T, s.channels)
+		for c := range s.acc {
+			s.acc[c] = make([]T, s.size)
+		}
+	}
+
+	for c, spectrum := range spectra {
+		s.plan.Inverse(s.signal, spectrum)
+
+		for i := range s.signal {
+			s.acc[c][i] += s.signal[i] * s.window[i]
+		}
+	}
+
+	for i := range s.norm {
+		s.norm[i] += s.window[i] * s.window[i]
+	}
+
+	s.emit(s.hop)
+
+	return nil
+}
+
+// Flush makes the remaining overlapping samples available for reading.
+// The flushed frames include the padding appended by [STFT], use [STFT.FramesRead] to trim the output to the input length.
+func (s *ISTFT[F, T]) Flush() {
+	if s.acc == nil {
+		return
+	}
+
+	s.emit(s.size - s.hop)
+}
+
+// Read reads reconstructed frames into buffer.
+// It returns [io.EOF] when no reconstructed frames are available.
+func (s *ISTFT[F, T]) Read(buffer []F) (int, error) {
+	return s.output.Read(buffer)
+}
+
+// WriteTo writes all reconstructed frames to w.
+func (s *ISTFT[F, T]) WriteTo(w gsp.Writer[F, T]) (int64, error) {
+	return s.output.WriteTo(w)
+}
+
+// Buffered returns the number of reconstructed frames that can be read.
+func (s *ISTFT[F, T]) Buffered() int {
+	return s.output.Len()
+}
+
+// emit moves n completed samples per channel from the accumulator to the output buffer.
+func (s *ISTFT[F, T]) emit(n int) {
+	skipped := min(n, s.skip)
+	s.skip -= skipped
+
+	s.output.Grow(n - skipped)
+	frames := s.output.AvailableBuffer()[:n-skipped]
+
+	for i := range frames {
+		var samples []T
+
+		if s.multiChannel {
+			samples = make([]T, s.channels)
+			*(*[]T)(unsafe.Pointer(&frames[i])) = samples
+		} else {
+			samples = unsafe.Slice((*T)(unsafe.Pointer(&frames[i])), s.channels)
+		}
+
+		for c := range samples {
+			if norm := s.norm[skipped+i]; norm > normEpsilon {
+				samples[c] = s.acc[c][skipped+i] / norm
+			} else {
+				samples[c] = 0
+			}
+		}
+	}
+
+	_, _ = s.output.Write(frames)
+
+	for c := range s.acc {
+		copy(s.acc[c], s.acc[c][n:])
+		clear(s.acc[c][s.size-n:])
+	}
+
+	copy(s.norm, s.norm[n:])
+	clear(s.norm[s.size-n:])
+}
+
+// normEpsilon is the minimum window power for which overlap-add output is normalized.
+const normEpsilon = 1e-10
+
+func checkSTFTParameters[T gsp.Float](size, hop int, w []T, function string) []T {
+	if size <= 0 || hop <= 0 || hop > size {
+		panic("gsp: fft." + function + ": hop size must be positive and not exceed the frame size")
+	}
+
+	if w == nil {
+		w = window.Periodic(window.Hann[T], size)
+	}
+
+	if len(w) != size {
+		panic("gsp: fft." + function + ": window length does not match frame size")
+	}
+
+	return w
+}
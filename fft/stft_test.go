@@ -0,0 +1,144 @@
+package fft_test
+
+import (
+	"io"
+	"math"
+	"math/rand/v2"
+	"testing"
+	"unsafe"
+
+	"github.com/samborkent/gsp"
+	"github.com/samborkent/gsp/fft"
+	"github.com/samborkent/gsp/window"
+)
+
+func TestSTFTReconstruction(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		size, hop   int
+		frames      int
+		rectangular bool
+	}{
+		{"50% overlap", 512, 256, 4000, false},
+		{"75% overlap", 512, 128, 4000, false},
+		{"no overlap", 256, 256, 1000, true},
+		{"odd size", 375, 125, 999, false},
+		{"short input", 1024, 256, 100, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name+"/mono", func(t *testing.T) {
+			t.Parallel()
+			testSTFTReconstruction[float64, float64](t, test.size, test.hop, test.rectangular, randomFrames[float64, float64](test.frames, 1))
+		})
+
+		t.Run(test.name+"/stereo", func(t *testing.T) {
+			t.Parallel()
+			testSTFTReconstruction[gsp.Stereo[float32], float32](t, test.size, test.hop, test.rectangular, randomFrames[gsp.Stereo[float32], float32](test.frames, 2))
+		})
+
+		t.Run(test.name+"/multi-channel", func(t *testing.T) {
+			t.Parallel()
+			testSTFTReconstruction[gsp.MultiChannel[float32], float32](t, test.size, test.hop, test.rectangular, randomFrames[gsp.MultiChannel[float32], float32](test.frames, 3))
+		})
+	}
+}
+
+func TestSTFTEmpty(t *testing.T) {
+	t.Parallel()
+
+	stft := fft.NewSTFT[float32, float32](new(gsp.Buffer[float32, float32]), 64, 16, nil)
+
+	_, err := stft.Next()
+	if err != io.EOF {
+		t.Errorf("wrong error: got '%v', want '%v'", err, io.EOF)
+	}
+}
+
+func testSTFTReconstruction[F gsp.Frame[T], T gsp.Float](t *testing.T, size, hop int, rectangular bool, input []F) {
+	t.Helper()
+
+	var w []T
+	if rectangular {
+		w = window.Rectangular[T](size)
+	}
+
+	stft := fft.NewSTFT[F, T](gsp.NewBuffer[F, T](append([]F(nil), input...)), size, hop, w)
+	istft := fft.NewISTFT[F, T](size, hop, w)
+
+	for {
+		spectra, err := stft.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("analysis: error: %s", err.Error())
+		}
+
+		if len(spectra[0]) != stft.Bins() {
+			t.Fatalf("wrong number of bins: got '%d', want '%d'", len(spectra[0]), stft.Bins())
+		}
+
+		err = istft.Write(spectra)
+		if err != nil {
+			t.Fatalf("synthesis: error: %s", err.Error())
+		}
+	}
+
+	istft.Flush()
+
+	if stft.FramesRead() != int64(len(input)) {
+		t.Fatalf("wrong number of frames read: got '%d', want '%d'", stft.FramesRead(), len(input))
+	}
+
+	output := new(gsp.Buffer[F, T])
+
+	_, err := istft.WriteTo(output)
+	if err != nil {
+		t.Fatalf("reading output: error: %s", err.Error())
+	}
+
+	if output.Len() < len(input) {
+		t.Fatalf("wrong number of frames: got '%d', want at least '%d'", output.Len(), len(input))
+	}
+
+	frames := output.Frames()
+	for i := range input {
+		got, want := frameSamples[F, T](&frames[i]), frameSamples[F, T](&input[i])
+
+		for c := range want {
+			if math.Abs(float64(got[c]-want[c])) > 1e-4 {
+				t.Fatalf("sample mismatch at index '%d' channel '%d': got '%g', want '%g'", i, c, got[c], want[c])
+			}
+		}
+	}
+}
+
+func randomFrames[F gsp.Frame[T], T gsp.Float](n, channels int) []F {
+	frames := make([]F, n)
+
+	for i := range frames {
+		if channels > 2 {
+			*(*[]T)(unsafe.Pointer(&frames[i])) = make([]T, channels)
+		}
+
+		samples := frameSamples[F, T](&frames[i])
+		for c := range samples {
+			samples[c] = T(2*rand.Float64() - 1)
+		}
+	}
+
+	return frames
+}
+
+func frameSamples[F gsp.Frame[T], T gsp.Float](frame *F) []T {
+	switch any(*frame).(type) {
+	case T:
+		return unsafe.Slice((*T)(unsafe.Pointer(frame)), 1)
+	case gsp.Stereo[T]:
+		return unsafe.Slice((*T)(unsafe.Pointer(frame)), 2)
+	default:
+		return *(*[]T)(unsafe.Pointer(frame))
+	}
+}
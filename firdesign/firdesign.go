@@ -0,0 +1,182 @@
+// Package firdesign designs linear-phase FIR filter kernels.
+//
+// All frequencies are normalized to the sample rate, such that 0.5 is the Nyquist frequency.
+package firdesign
+
+import (
+	"errors"
+	"math"
+
+	"github.com/samborkent/gsp"
+	"github.com/samborkent/gsp/window"
+)
+
+var (
+	ErrInvalidBands       = errors.New("gsp: firdesign: bands must be ordered, non-overlapping and within [0, 0.5]")
+	ErrInvalidTaps        = errors.New("gsp: firdesign: invalid number of taps")
+	ErrNoConvergence      = errors.New("gsp: firdesign: Remez exchange did not converge")
+	ErrAlternationFailure = errors.New("gsp: firdesign: Remez exchange lost alternation")
+)
+
+// LowPass returns a windowed-sinc low-pass kernel with the given number of taps and cutoff frequency.
+// The window must have length taps, a nil window selects a Blackman window.
+// The kernel has unity gain at DC.
+func LowPass[T gsp.Float](taps int, cutoff float64, w []T) []T {
+	kernel := idealLowPass(taps, cutoff)
+	applyWindow(kernel, w, "LowPass")
+	normalize(kernel, 0)
+
+	return convert[T](kernel)
+}
+
+// HighPass returns a windowed-sinc high-pass kernel with the given number of taps and cutoff frequency.
+// The number of taps must be odd, since even length linear-phase filters have a zero at the Nyquist frequency.
+// The window must have length taps, a nil window selects a Blackman window.
+// The kernel has unity gain at the Nyquist frequency.
+func HighPass[T gsp.Float](taps int, cutoff float64, w []T) []T {
+	if taps%2 == 0 {
+		panic("gsp: firdesign.HighPass: number of taps must be odd")
+	}
+
+	kernel := idealLowPass(taps, cutoff)
+	applyWindow(kernel, w, "HighPass")
+	normalize(kernel, 0)
+	invert(kernel)
+
+	return convert[T](kernel)
+}
+
+// BandPass returns a windowed-sinc band-pass kernel with the given number of taps, passing frequencies between low and high.
+// The window must have length taps, a nil window selects a Blackman window.
+// The kernel has unity gain at the center of the pass band.
+func BandPass[T gsp.Float](taps int, low, high float64, w []T) []T {
+	kernel := idealLowPass(taps, high)
+	lowPass := idealLowPass(taps, low)
+
+	for i := range kernel {
+		kernel[i] -= lowPass[i]
+	}
+
+	applyWindow(kernel, w, "BandPass")
+	normalize(kernel, (low+high)/2)
+
+	return convert[T](kernel)
+}
+
+// BandStop returns a windowed-sinc band-stop kernel with the given number of taps, rejecting frequencies between low and high.
+// The number of taps must be odd.
+// The window must have length taps, a nil window selects a Blackman window.
+// The kernel has unity gain at DC.
+func BandStop[T gsp.Float](taps int, low, high float64, w []T) []T {
+	if taps%2 == 0 {
+		panic("gsp: firdesign.BandStop: number of taps must be odd")
+	}
+
+	kernel := idealLowPass(taps, low)
+	highPass := idealLowPass(taps, high)
+	invert(highPass)
+
+	for i := range kernel {
+		kernel[i] += highPass[i]
+	}
+
+	applyWindow(kernel, w, "BandStop")
+	normalize(kernel, 0)
+
+	return convert[T](kernel)
+}
+
+// KaiserBeta returns the Kaiser window beta which achieves the given stop band attenuation in dB.
+func KaiserBeta(attenuation float64) float64 {
+	switch {
+	case attenuation > 50:
+		return 0.1102 * (attenuation - 8.7)
+	case attenuation >= 21:
+		return 0.5842*math.Pow(attenuation-21, 0.4) + 0.07886*(attenuation-21)
+	default:
+		return 0
+	}
+}
+
+// KaiserTaps returns the number of taps of a Kaiser windowed-sinc filter which achieves the given stop band attenuation in dB,
+// with the given normalized transition bandwidth.
+// The result is odd, so it can be used for all filter types.
+func KaiserTaps(attenuation, transition float64) int {
+	taps := int(math.Ceil((attenuation-7.95)/(14.36*transition))) + 1
+	if taps%2 == 0 {
+		taps++
+	}
+
+	return max(taps, 1)
+}
+
+// idealLowPass returns the truncated impulse response of an ideal low-pass filter, centered on the kernel.
+func idealLowPass(taps int, cutoff float64) []float64 {
+	if taps <= 0 {
+		panic("gsp: firdesign: number of taps must be positive")
+	}
+
+	kernel := make([]float64, taps)
+	center := float64(taps-1) / 2
+
+	for i := range kernel {
+		x := float64(i) - center
+		if x == 0 {
+			kernel[i] = 2 * cutoff
+		} else {
+			kernel[i] = math.Sin(2*math.Pi*cutoff*x) / (math.Pi * x)
+		}
+	}
+
+	return kernel
+}
+
+func applyWindow[T gsp.Float](kernel []float64, w []T, function string) {
+	if w == nil {
+		w = window.Blackman[T](len(kernel))
+	}
+
+	if len(w) != len(kernel) {
+		panic("gsp: firdesign." + function + ": window length does not match number of taps")
+	}
+
+	for i := range kernel {
+		kernel[i] *= float64(w[i])
+	}
+}
+
+// normalize scales the kernel to unity gain at the given frequency.
+func normalize(kernel []float64, frequency float64) {
+	center := float64(len(kernel)-1) / 2
+	gain := 0.0
+
+	for i, h := range kernel {
+		gain += h * math.Cos(2*math.Pi*frequency*(float64(i)-center))
+	}
+
+	if gain == 0 {
+		return
+	}
+
+	for i := range kernel {
+		kernel[i] /= gain
+	}
+}
+
+// invert turns an odd length low-pass kernel into the complementary high-pass kernel through spectral inversion.
+func invert(kernel []float64) {
+	for i := range kernel {
+		kernel[i] = -kernel[i]
+	}
+
+	kernel[len(kernel)/2] += 1
+}
+
+func convert[T gsp.Float](kernel []float64) []T {
+	converted := make([]T, len(kernel))
+	for i, h := range kernel {
+		converted[i] = T(h)
+	}
+
+	return converted
+}
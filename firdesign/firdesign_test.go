@@ -0,0 +1,140 @@
+package firdesign_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/samborkent/gsp/firdesign"
+	"github.com/samborkent/gsp/window"
+)
+
+func TestWindowedSinc(t *testing.T) {
+	t.Parallel()
+
+	const taps = 201
+
+	kaiser := window.Kaiser[float64](taps, firdesign.KaiserBeta(80))
+
+	tests := []struct {
+		name       string
+		kernel     []float64
+		pass, stop []float64
+	}{
+		{"low-pass", firdesign.LowPass(taps, 0.2, kaiser), []float64{0, 0.1, 0.18}, []float64{0.23, 0.3, 0.5}},
+		{"high-pass", firdesign.HighPass(taps, 0.2, kaiser), []float64{0.23, 0.3, 0.5}, []float64{0, 0.1, 0.17}},
+		{"band-pass", firdesign.BandPass(taps, 0.1, 0.3, kaiser), []float64{0.13, 0.2, 0.27}, []float64{0, 0.07, 0.33, 0.5}},
+		{"band-stop", firdesign.BandStop(taps, 0.1, 0.3, kaiser), []float64{0, 0.07, 0.33, 0.5}, []float64{0.13, 0.2, 0.27}},
+		{"default window", firdesign.LowPass[float64](taps, 0.2, nil), []float64{0, 0.1, 0.18}, []float64{0.23, 0.3, 0.5}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			testResponse(t, test.kernel, test.pass, test.stop, 1e-3, -70)
+		})
+	}
+}
+
+func TestRemez(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		taps       int
+		bands      []firdesign.Band
+		pass, stop []float64
+		ripple     float64
+		rejection  float64
+	}{
+		{
+			name: "low-pass odd",
+			taps: 101,
+			bands: []firdesign.Band{
+				{Low: 0, High: 0.2, Gain: 1, Weight: 1},
+				{Low: 0.25, High: 0.5, Gain: 0, Weight: 10},
+			},
+			pass: []float64{0, 0.1, 0.2}, stop: []float64{0.25, 0.3, 0.5},
+			ripple: 0.01, rejection: -60,
+		},
+		{
+			name: "low-pass even",
+			taps: 100,
+			bands: []firdesign.Band{
+				{Low: 0, High: 0.2, Gain: 1, Weight: 1},
+				{Low: 0.25, High: 0.5, Gain: 0, Weight: 10},
+			},
+			pass: []float64{0, 0.1, 0.2}, stop: []float64{0.25, 0.3, 0.5},
+			ripple: 0.01, rejection: -60,
+		},
+		{
+			name: "band-pass",
+			taps: 151,
+			bands: []firdesign.Band{
+				{Low: 0, High: 0.1, Gain: 0, Weight: 1},
+				{Low: 0.15, High: 0.3, Gain: 1, Weight: 1},
+				{Low: 0.35, High: 0.5, Gain: 0, Weight: 1},
+			},
+			pass: []float64{0.15, 0.2, 0.3}, stop: []float64{0, 0.1, 0.35, 0.5},
+			ripple: 0.01, rejection: -40,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			kernel, err := firdesign.Remez[float64](test.taps, test.bands)
+			if err != nil {
+				t.Fatalf("design: error: %s", err.Error())
+			}
+
+			for i := range kernel {
+				if math.Abs(kernel[i]-kernel[len(kernel)-1-i]) > 1e-9 {
+					t.Fatalf("kernel is not symmetric at index '%d': got '%g', want '%g'", i, kernel[i], kernel[len(kernel)-1-i])
+				}
+			}
+
+			testResponse(t, kernel, test.pass, test.stop, test.ripple, test.rejection)
+		})
+	}
+}
+
+func TestRemezInvalidBands(t *testing.T) {
+	t.Parallel()
+
+	_, err := firdesign.Remez[float32](51, []firdesign.Band{
+		{Low: 0, High: 0.3, Gain: 1},
+		{Low: 0.2, High: 0.5, Gain: 0},
+	})
+	if err != firdesign.ErrInvalidBands {
+		t.Errorf("wrong error: got '%v', want '%v'", err, firdesign.ErrInvalidBands)
+	}
+}
+
+func testResponse(t *testing.T, kernel []float64, pass, stop []float64, ripple, rejection float64) {
+	t.Helper()
+
+	for _, f := range pass {
+		if gain := magnitude(kernel, f); math.Abs(gain-1) > ripple {
+			t.Errorf("wrong pass band gain at '%g': got '%g', want '%g'", f, gain, 1.0)
+		}
+	}
+
+	for _, f := range stop {
+		if gain := 20 * math.Log10(magnitude(kernel, f)); gain > rejection {
+			t.Errorf("wrong stop band gain at '%g': got '%g dB', want below '%g dB'", f, gain, rejection)
+		}
+	}
+}
+
+func magnitude(kernel []float64, frequency float64) float64 {
+	re, im := 0.0, 0.0
+	for n, h := range kernel {
+		sin, cos := math.Sincos(-2 * math.Pi * frequency * float64(n))
+		re += h * cos
+		im += h * sin
+	}
+
+	return math.Hypot(re, im)
+}
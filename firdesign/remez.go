@@ -0,0 +1,305 @@
+package firdesign
+
+import (
+	"math"
+
+	"github.com/samborkent/gsp"
+)
+
+const (
+	remezGridDensity  = 16
+	remezMaxIteration = 100
+	remezTolerance    = 1e-6
+)
+
+// Band is a frequency band of an equiripple filter specification.
+type Band struct {
+	Low, High float64 // Normalized band edges.
+	Gain      float64 // Desired amplitude within the band.
+	Weight    float64 // Relative weight of the approximation error, zero is treated as one.
+}
+
+// Remez returns an equiripple linear-phase kernel with the given number of taps, designed with the Parks-McClellan algorithm.
+// The bands must be ordered and non-overlapping, the gaps between them are transition bands.
+// Even length kernels have a zero at the Nyquist frequency, so they cannot have a pass band there.
+//
+//	// Low-pass with pass band up to 0.2, and stop band from 0.25.
+//	kernel, err := firdesign.Remez[float32](101, []firdesign.Band{
+//		{Low: 0, High: 0.2, Gain: 1, Weight: 1},
+//		{Low: 0.25, High: 0.5, Gain: 0, Weight: 10},
+//	})
+func Remez[T gsp.Float](taps int, bands []Band) ([]T, error) {
+	if taps < 3 {
+		return nil, ErrInvalidTaps
+	}
+
+	if len(bands) == 0 {
+		return nil, ErrInvalidBands
+	}
+
+	for i, band := range bands {
+		if band.Low < 0 || band.High > 0.5 || band.Low >= band.High || (i > 0 && band.Low < bands[i-1].High) {
+			return nil, ErrInvalidBands
+		}
+	}
+
+	even := taps%2 == 0
+	// Number of cosine basis functions.
+	r := (taps + 1) / 2
+
+	grid := newRemezGrid(bands, r, even)
+	if len(grid.frequency) < r+1 {
+		return nil, ErrInvalidBands
+	}
+
+	// Start with extremal frequencies spread uniformly over the grid.
+	extremals := make([]int, r+1)
+	for k := range extremals {
+		extremals[k] = k * (len(grid.frequency) - 1) / r
+	}
+
+	var interpolation remezInterpolation
+
+	converged := false
+	deviations := make([]float64, len(grid.frequency))
+
+	for range remezMaxIteration {
+		interpolation = newRemezInterpolation(grid, extremals)
+
+		for j := range grid.frequency {
+			deviations[j] = grid.weight[j] * (grid.desired[j] - interpolation.evaluate(grid.x[j]))
+		}
+
+		next := findExtremals(grid, deviations, math.Abs(interpolation.delta), r+1)
+		if next == nil {
+			return nil, ErrAlternationFailure
+		}
+
+		maxError := 0.0
+		for _, j := range next {
+			maxError = max(maxError, math.Abs(deviations[j]))
+		}
+
+		extremals = next
+
+		if maxError-math.Abs(interpolation.delta) <= remezTolerance*maxError {
+			converged = true
+			break
+		}
+	}
+
+	if !converged {
+		return nil, ErrNoConvergence
+	}
+
+	interpolation = newRemezInterpolation(grid, extremals)
+
+	// Sample the zero-phase response on the DFT grid, and compute the kernel with an inverse cosine transform.
+	response := make([]float64, (taps-1)/2+1)
+	for m := range response {
+		omega := 2 * math.Pi * float64(m) / float64(taps)
+		response[m] = interpolation.evaluate(math.Cos(omega))
+
+		if even {
+			response[m] *= math.Cos(omega / 2)
+		}
+	}
+
+	kernel := make([]T, taps)
+	center := float64(taps-1) / 2
+
+	for n := range kernel {
+		sum := response[0]
+		for m := 1; m < len(response); m++ {
+			sum += 2 * response[m] * math.Cos(2*math.Pi*float64(m)*(float64(n)-center)/float64(taps))
+		}
+
+		kernel[n] = T(sum / float64(taps))
+	}
+
+	return kernel, nil
+}
+
+// remezGrid is the dense frequency grid on which the approximation error is evaluated.
+type remezGrid struct {
+	frequency, x    []float64
+	desired, weight []float64
+	band            []int
+}
+
+func newRemezGrid(bands []Band, r int, even bool) remezGrid {
+	var grid remezGrid
+
+	spacing := 0.5 / float64(remezGridDensity*r)
+
+	for b, band := range bands {
+		high := band.High
+		if even && high > 0.5-spacing {
+			// The response of even length kernels is zero at the Nyquist frequency.
+			high = 0.5 - spacing
+		}
+
+		points := max(2, int(math.Ceil((high-band.Low)/spacing))+1)
+
+		weight := band.Weight
+		if weight == 0 {
+			weight = 1
+		}
+
+		for i := range points {
+			f := band.Low + (high-band.Low)*float64(i)/float64(points-1)
+			desired, w := band.Gain, weight
+
+			if even {
+				// Factor out the cos(ω/2) term of even length kernels.
+				c := math.Cos(math.Pi * f)
+				desired /= c
+				w *= c
+			}
+
+			grid.frequency = append(grid.frequency, f)
+			grid.x = append(grid.x, math.Cos(2*math.Pi*f))
+			grid.desired = append(grid.desired, desired)
+			grid.weight = append(grid.weight, w)
+			grid.band = append(grid.band, b)
+		}
+	}
+
+	return grid
+}
+
+// remezInterpolation is the barycentric Lagrange interpolation of the approximating polynomial through the extremal frequencies.
+type remezInterpolation struct {
+	x, y, weights []float64
+	delta         float64
+}
+
+func newRemezInterpolation(grid remezGrid, extremals []int) remezInterpolation {
+	n := len(extremals)
+	x := make([]float64, n)
+
+	for k, j := range extremals {
+		x[k] = grid.x[j]
+	}
+
+	// Barycentric weights over all extremal frequencies.
+	weights := make([]float64, n)
+	for k := range weights {
+		product := 1.0
+		for i := range x {
+			if i != k {
+				product *= 2 * (x[k] - x[i])
+			}
+		}
+
+		weights[k] = 1 / product
+	}
+
+	numerator, denominator := 0.0, 0.0
+	sign := 1.0
+
+	for k, j := range extremals {
+		numerator += weights[k] * grid.desired[j]
+		denominator += sign * weights[k] / grid.weight[j]
+		sign = -sign
+	}
+
+	delta := numerator / denominator
+
+	// Interpolate through all but the last extremal frequency, at the desired response offset by the alternating error.
+	interpolation := remezInterpolation{
+		x:       x[:n-1],
+		y:       make([]float64, n-1),
+		weights: make([]float64, n-1),
+		delta:   delta,
+	}
+
+	sign = 1.0
+
+	for k := range n - 1 {
+		j := extremals[k]
+		interpolation.y[k] = grid.desired[j] - sign*delta/grid.weight[j]
+		interpolation.weights[k] = weights[k] * 2 * (x[k] - x[n-1])
+		sign = -sign
+	}
+
+	return interpolation
+}
+
+func (p *remezInterpolation) evaluate(x float64) float64 {
+	numerator, denominator := 0.0, 0.0
+
+	for k := range p.x {
+		d := x - p.x[k]
+		if math.Abs(d) < 1e-14 {
+			return p.y[k]
+		}
+
+		c := p.weights[k] / d
+		numerator += c * p.y[k]
+		denominator += c
+	}
+
+	return numerator / denominator
+}
+
+// findExtremals returns the grid indices of count alternating error extrema with a magnitude of at least delta,
+// or nil if there are too few.
+func findExtremals(grid remezGrid, deviations []float64, delta float64, count int) []int {
+	var candidates []int
+
+	// Allow for rounding errors, which are significant relative to the small deviation of the first iterations.
+	threshold := delta * (1 - 1e-3)
+
+	for j := range deviations {
+		if math.Abs(deviations[j]) < threshold {
+			continue
+		}
+
+		// Band edges only have a neighbor on one side.
+		previous := j > 0 && grid.band[j-1] == grid.band[j]
+		next := j < len(deviations)-1 && grid.band[j+1] == grid.band[j]
+
+		if deviations[j] > 0 {
+			if (!previous || deviations[j] >= deviations[j-1]) && (!next || deviations[j] > deviations[j+1]) {
+				candidates = append(candidates, j)
+			}
+		} else {
+			if (!previous || deviations[j] <= deviations[j-1]) && (!next || deviations[j] < deviations[j+1]) {
+				candidates = append(candidates, j)
+			}
+		}
+	}
+
+	// Enforce alternation by keeping the largest of consecutive extrema of the same sign.
+	alternating := candidates[:0]
+	for _, j := range candidates {
+		if len(alternating) > 0 {
+			last := alternating[len(alternating)-1]
+			if math.Signbit(deviations[j]) == math.Signbit(deviations[last]) {
+				if math.Abs(deviations[j]) > math.Abs(deviations[last]) {
+					alternating[len(alternating)-1] = j
+				}
+
+				continue
+			}
+		}
+
+		alternating = append(alternating, j)
+	}
+
+	// Drop the smallest extrema at the ends, which preserves alternation.
+	for len(alternating) > count {
+		if math.Abs(deviations[alternating[0]]) < math.Abs(deviations[alternating[len(alternating)-1]]) {
+			alternating = alternating[1:]
+		} else {
+			alternating = alternating[:len(alternating)-1]
+		}
+	}
+
+	if len(alternating) < count {
+		return nil
+	}
+
+	return alternating
+}
@@ -0,0 +1,153 @@
+package gsp
+
+import (
+	"time"
+)
+
+// SampleType identifies the data type of a single sample.
+type SampleType int
+
+const (
+	SampleTypeUnknown SampleType = iota
+	SampleTypeUint8
+	SampleTypeInt8
+	SampleTypeUint16
+	SampleTypeInt16
+	SampleTypeUint32
+	SampleTypeInt32
+	SampleTypeFloat32
+	SampleTypeFloat64
+)
+
+// SampleTypeOf returns the [SampleType] of T.
+func SampleTypeOf[T Type]() SampleType {
+	switch any(T(0)).(type) {
+	case uint8:
+		return SampleTypeUint8
+	case int8:
+		return SampleTypeInt8
+	case uint16:
+		return SampleTypeUint16
+	case int16:
+		return SampleTypeInt16
+	case uint32:
+		return SampleTypeUint32
+	case int32:
+		return SampleTypeInt32
+	case float32:
+		return SampleTypeFloat32
+	case float64:
+		return SampleTypeFloat64
+	default:
+		return SampleTypeUnknown
+	}
+}
+
+// ByteSize returns the size of a single sample in bytes.
+func (t SampleType) ByteSize() int {
+	switch t {
+	case SampleTypeUint8, SampleTypeInt8:
+		return 1
+	case SampleTypeUint16, SampleTypeInt16:
+		return 2
+	case SampleTypeUint32, SampleTypeInt32, SampleTypeFloat32:
+		return 4
+	case SampleTypeFloat64:
+		return 8
+	default:
+		return 0
+	}
+}
+
+func (t SampleType) String() string {
+	switch t {
+	case SampleTypeUint8:
+		return "uint8"
+	case SampleTypeInt8:
+		return "int8"
+	case SampleTypeUint16:
+		return "uint16"
+	case SampleTypeInt16:
+		return "int16"
+	case SampleTypeUint32:
+		return "uint32"
+	case SampleTypeInt32:
+		return "int32"
+	case SampleTypeFloat32:
+		return "float32"
+	case SampleTypeFloat64:
+		return "float64"
+	default:
+		return "unknown"
+	}
+}
+
+// Layout is the memory layout of the channels of a signal.
+type Layout int
+
+const (
+	LayoutInterleaved Layout = iota // Samples of all channels are stored per frame.
+	LayoutPlanar                    // Samples are stored per channel.
+)
+
+// Format describes a stream of audio frames.
+type Format struct {
+	SampleRate int // Number of frames per second.
+	Channels   int
+	SampleType SampleType
+	BigEndian  bool    // Byte order of the encoded samples.
+	Packing    Packing // Container of the encoded samples, if it differs from the sample type.
+	Layout     Layout
+}
+
+// NewFormat returns the [Format] of frame type F at the given sample rate.
+// The channel count of multi-channel frames is only known at runtime, so it is left zero.
+func NewFormat[F Frame[T], T Type](sampleRate int) Format {
+	return Format{
+		SampleRate: sampleRate,
+		Channels:   frameChannels[F, T](),
+		SampleType: SampleTypeOf[T](),
+	}
+}
+
+// FrameSize returns the size of a single encoded frame in bytes.
+func (f Format) FrameSize() int {
+	if f.Packing != PackingNone {
+		return f.Channels * f.Packing.byteSize()
+	}
+
+	return f.Channels * f.SampleType.ByteSize()
+}
+
+// Duration returns the duration of the given number of frames.
+func (f Format) Duration(frames int) time.Duration {
+	if f.SampleRate == 0 {
+		return 0
+	}
+
+	// Whole seconds and the remaining frames are converted separately, so long durations do not overflow.
+	seconds, remainder := frames/f.SampleRate, frames%f.SampleRate
+
+	return time.Duration(seconds)*time.Second + time.Duration(remainder)*time.Second/time.Duration(f.SampleRate)
+}
+
+// Frames returns the number of frames within the given duration, rounded down.
+func (f Format) Frames(d time.Duration) int {
+	seconds, remainder := d/time.Second, d%time.Second
+
+	return int(seconds)*f.SampleRate + int(remainder*time.Duration(f.SampleRate)/time.Second)
+}
+
+// frameChannels returns the number of channels of frame type F, or zero for multi-channel frames.
+func frameChannels[F Frame[T], T Type]() int {
+	switch any(*new(F)).(type) {
+	case T:
+		return 1
+	case [2]T, Stereo[T]:
+		return 2
+	case []T, MultiChannel[T]:
+		return 0
+	default:
+		panic("gsp: frameChannels: unknown audio frame type")
+	}
+}
@@ -0,0 +1,141 @@
+package gsp_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/samborkent/gsp"
+)
+
+func TestNewFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		format gsp.Format
+		want   gsp.Format
+	}{
+		{"mono", gsp.NewFormat[int16, int16](44100), gsp.Format{SampleRate: 44100, Channels: 1, SampleType: gsp.SampleTypeInt16}},
+		{"stereo", gsp.NewFormat[gsp.Stereo[float32], float32](48000), gsp.Format{SampleRate: 48000, Channels: 2, SampleType: gsp.SampleTypeFloat32}},
+		{"array", gsp.NewFormat[[2]uint8, uint8](8000), gsp.Format{SampleRate: 8000, Channels: 2, SampleType: gsp.SampleTypeUint8}},
+		{"multi-channel", gsp.NewFormat[gsp.MultiChannel[float64], float64](96000), gsp.Format{SampleRate: 96000, SampleType: gsp.SampleTypeFloat64}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if test.format != test.want {
+				t.Errorf("wrong format: got '%+v', want '%+v'", test.format, test.want)
+			}
+		})
+	}
+
+	if size := gsp.NewFormat[gsp.Stereo[int32], int32](48000).FrameSize(); size != 8 {
+		t.Errorf("wrong frame size: got '%d', want '%d'", size, 8)
+	}
+}
+
+func TestFormatFrameSizePacked(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		packing gsp.Packing
+		want    int
+	}{
+		{"unpacked", gsp.PackingNone, 8},
+		{"packed", gsp.Packing24, 6},
+		{"left-justified", gsp.Packing24In32Left, 8},
+		{"right-justified", gsp.Packing24In32Right, 8},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			encoder := gsp.NewEncoder[gsp.Stereo[int32], int32](io.Discard, gsp.EncodingPacking(test.packing))
+			if size := encoder.Format().FrameSize(); size != test.want {
+				t.Errorf("wrong encoder frame size: got '%d', want '%d'", size, test.want)
+			}
+
+			decoder := gsp.NewDecoder[gsp.Stereo[int32], int32](bytes.NewReader(nil), gsp.EncodingPacking(test.packing))
+			if size := decoder.Format().FrameSize(); size != test.want {
+				t.Errorf("wrong decoder frame size: got '%d', want '%d'", size, test.want)
+			}
+		})
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		sampleRate int
+		frames     int
+		want       time.Duration
+	}{
+		{"zero sample rate", 0, 48000, 0},
+		{"second", 48000, 48000, time.Second},
+		{"fraction", 44100, 441, 10 * time.Millisecond},
+		{"rounded down", 3, 1, 333333333 * time.Nanosecond},
+		{"hundred hours", 48000, 100 * 3600 * 48000, 100 * time.Hour},
+		{"year", 192000, 365 * 24 * 3600 * 192000, 365 * 24 * time.Hour},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			format := gsp.Format{SampleRate: test.sampleRate}
+
+			if got := format.Duration(test.frames); got != test.want {
+				t.Errorf("wrong duration: got '%v', want '%v'", got, test.want)
+			}
+		})
+	}
+}
+
+func TestFormatFrames(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		sampleRate int
+		duration   time.Duration
+		want       int
+	}{
+		{"zero sample rate", 0, time.Second, 0},
+		{"second", 48000, time.Second, 48000},
+		{"fraction", 44100, 10 * time.Millisecond, 441},
+		{"rounded down", 44100, time.Millisecond, 44},
+		{"hundred hours", 48000, 100 * time.Hour, 100 * 3600 * 48000},
+		{"year", 192000, 365 * 24 * time.Hour, 365 * 24 * 3600 * 192000},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			format := gsp.Format{SampleRate: test.sampleRate}
+
+			if got := format.Frames(test.duration); got != test.want {
+				t.Errorf("wrong number of frames: got '%d', want '%d'", got, test.want)
+			}
+		})
+	}
+}
+
+func TestConverterFormat(t *testing.T) {
+	t.Parallel()
+
+	converter := gsp.NewConverter[float32, gsp.Stereo[int16], float32, int16](16, gsp.ConvertFormat(gsp.Format{SampleRate: 44100}))
+
+	want := gsp.Format{SampleRate: 44100, Channels: 2, SampleType: gsp.SampleTypeInt16}
+	if converter.Format() != want {
+		t.Errorf("wrong format: got '%+v', want '%+v'", converter.Format(), want)
+	}
+}
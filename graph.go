@@ -0,0 +1,497 @@
+package gsp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"unsafe"
+)
+
+var ErrGraphCycle = errors.New("gsp: Graph: connection creates a cycle")
+
+// Graph is a directed acyclic graph of processors, which processes a block of frames at a time on the calling goroutine.
+//
+// Nodes are created with [AddProcessor] and [AddBus], and connected with [Connect], [Send] and [ConnectConverted].
+// A node sums all its inputs, so a node with multiple inputs acts as a mixer, and a node with multiple outputs as a split.
+// Every node has its own frame type, branches of different frame types are bridged by converting connections.
+//
+// Before processing, the nodes are scheduled in topological order, and branches of differing latency are delayed to arrive aligned at the nodes where they merge.
+// The latency of a node is the latency of its processor if it implements [LatencyReporter].
+// A graph is not safe for concurrent use.
+type Graph struct {
+	blockSize int
+	format    Format
+	nodes     []graphNode
+	order     []graphNode // Nodes in processing order, nil if the graph changed since it was compiled.
+}
+
+// NewGraph returns an empty [Graph] which processes blocks of blockSize frames.
+func NewGraph(blockSize int) *Graph {
+	if blockSize <= 0 {
+		panic("gsp: NewGraph: block size must be positive")
+	}
+
+	return &Graph{
+		blockSize: blockSize,
+	}
+}
+
+// BlockSize returns the number of frames processed per block.
+func (g *Graph) BlockSize() int {
+	return g.blockSize
+}
+
+// Init initializes every processor implementing [Initializer] with the stream format.
+// The channel count and sample type are adapted to the frame type of each node, the channel count of the format is used for multi-channel nodes.
+func (g *Graph) Init(format Format) {
+	g.format = format
+
+	for _, node := range g.nodes {
+		node.init(format)
+	}
+
+	// Latencies may depend on the format.
+	g.order = nil
+}
+
+// Format returns the format the graph was initialized with.
+func (g *Graph) Format() Format {
+	return g.format
+}
+
+// Compile schedules the nodes in topological order and computes the delays which align merging branches.
+// It is called by [Graph.Process] after the graph changed, but can be called earlier to query [Node.Latency].
+func (g *Graph) Compile() error {
+	indegree := make([]int, len(g.nodes))
+
+	for _, node := range g.nodes {
+		for _, edge := range node.base().outputs {
+			indegree[edge.to.base().index]++
+		}
+	}
+
+	queue := make([]graphNode, 0, len(g.nodes))
+
+	for i, node := range g.nodes {
+		if indegree[i] == 0 {
+			queue = append(queue, node)
+		}
+	}
+
+	order := make([]graphNode, 0, len(g.nodes))
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		order = append(order, node)
+
+		for _, edge := range node.base().outputs {
+			index := edge.to.base().index
+
+			indegree[index]--
+			if indegree[index] == 0 {
+				queue = append(queue, edge.to)
+			}
+		}
+	}
+
+	if len(order) != len(g.nodes) {
+		return ErrGraphCycle
+	}
+
+	// Delay every input of a node to the input with the highest latency.
+	for _, node := range order {
+		base := node.base()
+
+		arrival := 0
+		for _, edge := range base.inputs {
+			arrival = max(arrival, edge.from.base().latency)
+		}
+
+		for _, edge := range base.inputs {
+			edge.delay(arrival - edge.from.base().latency)
+		}
+
+		base.latency = arrival + node.processorLatency()
+	}
+
+	g.order = order
+
+	return nil
+}
+
+// Process processes a single block through every node, compiling the graph first if it changed.
+// It stops at the first processor which fails.
+func (g *Graph) Process() error {
+	if g.order == nil {
+		err := g.Compile()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, node := range g.order {
+		err := node.process()
+		if err != nil {
+			return fmt.Errorf("gsp: Graph.Process: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (g *Graph) add(node graphNode) {
+	node.base().index = len(g.nodes)
+	g.nodes = append(g.nodes, node)
+	g.order = nil
+}
+
+// graphNode is the part of a [Node] which does not depend on its frame type.
+type graphNode interface {
+	base() *nodeBase
+	init(format Format)
+	process() error
+	processorLatency() int
+}
+
+type nodeBase struct {
+	graph           *Graph
+	index           int
+	inputs, outputs []*graphEdge
+	latency         int // Latency at the output, set when the graph is compiled.
+}
+
+func (n *nodeBase) base() *nodeBase {
+	return n
+}
+
+// graphEdge connects the output of one node to the input of another.
+type graphEdge struct {
+	from, to graphNode
+	delay    func(frames int) // Sets the number of frames by which the source output is delayed.
+	transfer func()           // Adds the delayed source output to the destination input.
+}
+
+// Node is a processor or bus in a [Graph].
+type Node[F Frame[T], T Type] struct {
+	nodeBase
+
+	processor BufferProcessor[F, T] // Nil for buses.
+
+	input, output []F // The output of a bus is its input.
+	external      []F // Frames written with [Node.Write].
+	written       int
+}
+
+// AddProcessor adds a node which processes the sum of its inputs with processor.
+func AddProcessor[F Frame[T], T Type](g *Graph, processor BufferProcessor[F, T]) *Node[F, T] {
+	node := newNode[F, T](g)
+	node.processor = processor
+	node.output = silence[F, T](g.blockSize, 0)
+
+	g.add(node)
+
+	return node
+}
+
+// AddBus adds a node which outputs the sum of its inputs, such as a mixer, an aux bus, or the source or sink of a graph.
+func AddBus[F Frame[T], T Type](g *Graph) *Node[F, T] {
+	node := newNode[F, T](g)
+	node.output = node.input
+
+	g.add(node)
+
+	return node
+}
+
+func newNode[F Frame[T], T Type](g *Graph) *Node[F, T] {
+	return &Node[F, T]{
+		nodeBase: nodeBase{graph: g},
+		input:    silence[F, T](g.blockSize, 0),
+		external: silence[F, T](g.blockSize, 0),
+	}
+}
+
+// Write sets frames which are added to the input of the node during the next block, which makes the node a source of the graph.
+// At most one block of frames is taken, missing frames are silent.
+func (n *Node[F, T]) Write(frames []F) (int, error) {
+	n.written = copyFrames[F, T](n.external, frames)
+
+	if n.written < len(frames) {
+		return n.written, io.ErrShortWrite
+	}
+
+	return n.written, nil
+}
+
+// Read copies the output of the node of the last processed block into frames.
+func (n *Node[F, T]) Read(frames []F) (int, error) {
+	return copyFrames[F, T](frames, n.output), nil
+}
+
+// Latency returns the number of frames by which the output of the node is delayed relative to the sources of the graph.
+// It is only valid after the graph is compiled.
+func (n *Node[F, T]) Latency() int {
+	return n.latency
+}
+
+func (n *Node[F, T]) init(format Format) {
+	initializer, ok := n.processor.(Initializer)
+	if !ok {
+		return
+	}
+
+	nodeFormat := format
+	nodeFormat.SampleType = SampleTypeOf[T]()
+
+	if channels := frameChannels[F, T](); channels > 0 {
+		nodeFormat.Channels = channels
+	}
+
+	initializer.Init(nodeFormat)
+}
+
+func (n *Node[F, T]) processorLatency() int {
+	if reporter, ok := n.processor.(LatencyReporter); ok {
+		return reporter.Latency()
+	}
+
+	return 0
+}
+
+func (n *Node[F, T]) process() error {
+	clearFrames[F, T](n.input)
+
+	if n.written > 0 {
+		addFrames[F, T](n.input, n.external[:n.written])
+		n.written = 0
+	}
+
+	for _, edge := range n.inputs {
+		edge.transfer()
+	}
+
+	if n.processor == nil {
+		return nil
+	}
+
+	if fallible, ok := n.processor.(FallibleBufferProcessor[F, T]); ok {
+		return fallible.TryProcessBuffer(n.output, n.input)
+	}
+
+	n.processor.ProcessBuffer(n.output, n.input)
+
+	return nil
+}
+
+// Connect adds the output of from to the input of to.
+func Connect[F Frame[T], T Type](from, to *Node[F, T]) error {
+	return connect(newGraphEdge(from, to, func(frames []F) {
+		addFrames[F, T](to.input, frames)
+	}))
+}
+
+// Send adds the output of from, scaled by a linear gain, to the input of to.
+func Send[F Frame[T], T Float](from, to *Node[F, T], gain T) error {
+	return connect(newGraphEdge(from, to, func(frames []F) {
+		addScaledFrames[F, T](to.input, frames, gain)
+	}))
+}
+
+// ConnectConverted adds the output of from to the input of to, converting the frames as a [Converter] with the options would.
+// Multi-channel frames are converted to the channel count of the graph format, or keep the channel count of the input if it is unknown.
+func ConnectConverted[In Frame[I], Out Frame[O], I Type, O Type](from *Node[In, I], to *Node[Out, O], opts ...ConvertOption) error {
+	cfg := newConvertConfig(opts...)
+	converted := make([]Out, from.graph.blockSize)
+
+	// Each channel of the converted frames keeps its own noise-shaping history.
+	if cfg.dithered() && isFloat[I]() && !isFloat[O]() {
+		cfg.quantizer = newQuantizer(&cfg, 0)
+	}
+
+	return connect(newGraphEdge(from, to, func(frames []In) {
+		for i := range frames {
+			if cfg.quantizer != nil {
+				cfg.quantizer.channel = 0
+			}
+
+			converted[i] = convert[In, Out, I, O](&cfg, frames[i], to.graph.format.Channels)
+		}
+
+		addFrames[Out, O](to.input, converted[:len(frames)])
+	}))
+}
+
+func newGraphEdge[F Frame[T], T Type](from *Node[F, T], to graphNode, add func(frames []F)) *graphEdge {
+	line := new(delayLine[F, T])
+
+	return &graphEdge{
+		from:  from,
+		to:    to,
+		delay: line.resize,
+		transfer: func() {
+			add(line.process(from.output))
+		},
+	}
+}
+
+func connect(edge *graphEdge) error {
+	from, to := edge.from.base(), edge.to.base()
+
+	if from.graph != to.graph {
+		panic("gsp: Connect: nodes belong to different graphs")
+	}
+
+	if reaches(to, from) {
+		return ErrGraphCycle
+	}
+
+	from.outputs = append(from.outputs, edge)
+	to.inputs = append(to.inputs, edge)
+	from.graph.order = nil
+
+	return nil
+}
+
+// reaches reports whether there is a path from node to target.
+func reaches(node, target *nodeBase) bool {
+	if node == target {
+		return true
+	}
+
+	for _, edge := range node.outputs {
+		if reaches(edge.to.base(), target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// delayLine delays frames by a fixed number of frames.
+type delayLine[F Frame[T], T Type] struct {
+	history, joined []F
+}
+
+// resize sets the delay, and clears the history if it changed.
+func (d *delayLine[F, T]) resize(frames int) {
+	if len(d.history) != frames {
+		d.history = silence[F, T](frames, 0)
+	}
+}
+
+// process returns the input delayed. The result is valid until the next call.
+func (d *delayLine[F, T]) process(input []F) []F {
+	if len(d.history) == 0 {
+		return input
+	}
+
+	size := len(d.history) + len(input)
+	if cap(d.joined) < size {
+		d.joined = make([]F, size)
+	}
+
+	d.joined = d.joined[:size]
+
+	copyFrames[F, T](d.joined, d.history)
+	copyFrames[F, T](d.joined[len(d.history):], input)
+	copyFrames[F, T](d.history, d.joined[len(input):])
+
+	return d.joined[:len(input)]
+}
+
+// copyFrames copies src into dst like the copy builtin, but copies the samples of multi-channel frames instead of sharing them.
+func copyFrames[F Frame[T], T Type](dst, src []F) int {
+	if frameChannels[F, T]() > 0 {
+		return copy(dst, src)
+	}
+
+	dstFrames := *(*[]MultiChannel[T])(unsafe.Pointer(&dst))
+	srcFrames := *(*[]MultiChannel[T])(unsafe.Pointer(&src))
+
+	n := min(len(dstFrames), len(srcFrames))
+	for i := range n {
+		dstFrames[i] = append(dstFrames[i][:0], srcFrames[i]...)
+	}
+
+	return n
+}
+
+// clearFrames sets all samples to the zero value of the sample type.
+func clearFrames[F Frame[T], T Type](frames []F) {
+	zero := Zero[T]()
+
+	if channels := frameChannels[F, T](); channels > 0 {
+		samples := unsafe.Slice((*T)(unsafe.Pointer(unsafe.SliceData(frames))), channels*len(frames))
+		for i := range samples {
+			samples[i] = zero
+		}
+
+		return
+	}
+
+	for _, frame := range *(*[]MultiChannel[T])(unsafe.Pointer(&frames)) {
+		for i := range frame {
+			frame[i] = zero
+		}
+	}
+}
+
+// addFrames adds the signal of src to dst. Multi-channel frames of dst grow to the channel count of src.
+// Integer samples wrap around on overflow.
+func addFrames[F Frame[T], T Type](dst, src []F) {
+	zero := Zero[T]()
+
+	if channels := frameChannels[F, T](); channels > 0 {
+		n := channels * min(len(dst), len(src))
+		dstSamples := unsafe.Slice((*T)(unsafe.Pointer(unsafe.SliceData(dst))), n)
+		srcSamples := unsafe.Slice((*T)(unsafe.Pointer(unsafe.SliceData(src))), n)
+
+		for i := range n {
+			dstSamples[i] += srcSamples[i] - zero
+		}
+
+		return
+	}
+
+	dstFrames := *(*[]MultiChannel[T])(unsafe.Pointer(&dst))
+	srcFrames := *(*[]MultiChannel[T])(unsafe.Pointer(&src))
+
+	for i := range min(len(dstFrames), len(srcFrames)) {
+		for len(dstFrames[i]) < len(srcFrames[i]) {
+			dstFrames[i] = append(dstFrames[i], zero)
+		}
+
+		for j, sample := range srcFrames[i] {
+			dstFrames[i][j] += sample - zero
+		}
+	}
+}
+
+// addScaledFrames adds the signal of src multiplied by gain to dst.
+func addScaledFrames[F Frame[T], T Float](dst, src []F, gain T) {
+	if channels := frameChannels[F, T](); channels > 0 {
+		n := channels * min(len(dst), len(src))
+		dstSamples := unsafe.Slice((*T)(unsafe.Pointer(unsafe.SliceData(dst))), n)
+		srcSamples := unsafe.Slice((*T)(unsafe.Pointer(unsafe.SliceData(src))), n)
+
+		for i := range n {
+			dstSamples[i] += gain * srcSamples[i]
+		}
+
+		return
+	}
+
+	dstFrames := *(*[]MultiChannel[T])(unsafe.Pointer(&dst))
+	srcFrames := *(*[]MultiChannel[T])(unsafe.Pointer(&src))
+
+	for i := range min(len(dstFrames), len(srcFrames)) {
+		for len(dstFrames[i]) < len(srcFrames[i]) {
+			dstFrames[i] = append(dstFrames[i], 0)
+		}
+
+		for j, sample := range srcFrames[i] {
+			dstFrames[i][j] += gain * sample
+		}
+	}
+}
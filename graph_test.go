@@ -0,0 +1,356 @@
+package gsp_test
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/samborkent/gsp"
+)
+
+// delayProcessor delays its input by a fixed number of frames, and reports it as latency.
+type delayProcessor struct {
+	history []float64
+}
+
+func (p *delayProcessor) ProcessBuffer(output, input []float64) {
+	joined := append(p.history, input...)
+	copy(output, joined[:len(input)])
+	p.history = append(p.history[:0], joined[len(input):]...)
+}
+
+func (p *delayProcessor) Latency() int {
+	return len(p.history)
+}
+
+func TestGraphSplitMerge(t *testing.T) {
+	t.Parallel()
+
+	graph := gsp.NewGraph(4)
+
+	source := gsp.AddBus[float64, float64](graph)
+	double := gsp.AddProcessor[float64, float64](graph, &scaleProcessor{scale: 2})
+	aux := gsp.AddBus[float64, float64](graph)
+	mix := gsp.AddBus[float64, float64](graph)
+
+	for _, err := range []error{
+		gsp.Connect(source, double),
+		gsp.Send(source, aux, 0.5),
+		gsp.Connect(double, mix),
+		gsp.Send(aux, mix, 0.5),
+	} {
+		if err != nil {
+			t.Fatalf("connecting nodes: error: %s", err.Error())
+		}
+	}
+
+	_, err := source.Write([]float64{0.1, 0.2, 0.3, 0.4})
+	if err != nil {
+		t.Fatalf("writing frames: error: %s", err.Error())
+	}
+
+	err = graph.Process()
+	if err != nil {
+		t.Fatalf("processing graph: error: %s", err.Error())
+	}
+
+	output := make([]float64, 4)
+	_, _ = mix.Read(output)
+
+	for i, want := range []float64{0.225, 0.45, 0.675, 0.9} {
+		if diff := output[i] - want; diff > 1e-12 || diff < -1e-12 {
+			t.Errorf("wrong frame at index '%d': got '%g', want '%g'", i, output[i], want)
+		}
+	}
+
+	// Written frames are only used for a single block.
+	err = graph.Process()
+	if err != nil {
+		t.Fatalf("processing graph: error: %s", err.Error())
+	}
+
+	_, _ = mix.Read(output)
+
+	for i := range output {
+		if output[i] != 0 {
+			t.Errorf("wrong frame at index '%d': got '%g', want '%g'", i, output[i], 0.0)
+		}
+	}
+}
+
+func TestGraphDelayCompensation(t *testing.T) {
+	t.Parallel()
+
+	blockSize, latency := 4, 6
+
+	graph := gsp.NewGraph(blockSize)
+
+	source := gsp.AddBus[float64, float64](graph)
+	delay := gsp.AddProcessor[float64, float64](graph, &delayProcessor{history: make([]float64, latency)})
+	mix := gsp.AddBus[float64, float64](graph)
+
+	_ = gsp.Connect(source, delay)
+	_ = gsp.Connect(delay, mix)
+	_ = gsp.Connect(source, mix)
+
+	err := graph.Compile()
+	if err != nil {
+		t.Fatalf("compiling graph: error: %s", err.Error())
+	}
+
+	if mix.Latency() != latency {
+		t.Errorf("wrong latency: got '%d', want '%d'", mix.Latency(), latency)
+	}
+
+	_, _ = source.Write([]float64{1})
+
+	var output []float64
+
+	for range 3 {
+		err = graph.Process()
+		if err != nil {
+			t.Fatalf("processing graph: error: %s", err.Error())
+		}
+
+		block := make([]float64, blockSize)
+		_, _ = mix.Read(block)
+		output = append(output, block...)
+	}
+
+	// Both branches of the impulse arrive at the same frame.
+	for i := range output {
+		want := 0.0
+		if i == latency {
+			want = 2
+		}
+
+		if output[i] != want {
+			t.Errorf("wrong frame at index '%d': got '%g', want '%g'", i, output[i], want)
+		}
+	}
+}
+
+func TestGraphCycle(t *testing.T) {
+	t.Parallel()
+
+	graph := gsp.NewGraph(4)
+
+	a := gsp.AddBus[float64, float64](graph)
+	b := gsp.AddBus[float64, float64](graph)
+	c := gsp.AddBus[float64, float64](graph)
+
+	_ = gsp.Connect(a, b)
+	_ = gsp.Connect(b, c)
+
+	err := gsp.Connect(c, a)
+	if !errors.Is(err, gsp.ErrGraphCycle) {
+		t.Errorf("wrong error: got '%v', want '%v'", err, gsp.ErrGraphCycle)
+	}
+
+	err = gsp.Connect(a, a)
+	if !errors.Is(err, gsp.ErrGraphCycle) {
+		t.Errorf("wrong error: got '%v', want '%v'", err, gsp.ErrGraphCycle)
+	}
+
+	err = graph.Process()
+	if err != nil {
+		t.Errorf("processing graph: error: %s", err.Error())
+	}
+}
+
+func TestGraphConverted(t *testing.T) {
+	t.Parallel()
+
+	graph := gsp.NewGraph(2)
+
+	source := gsp.AddBus[int16, int16](graph)
+	stereo := gsp.AddBus[gsp.Stereo[float32], float32](graph)
+	sink := gsp.AddBus[uint8, uint8](graph)
+
+	_ = gsp.ConnectConverted[int16, gsp.Stereo[float32], int16, float32](source, stereo)
+	_ = gsp.ConnectConverted[gsp.Stereo[float32], uint8, float32, uint8](stereo, sink)
+
+	_, _ = source.Write([]int16{16384, -16384})
+
+	err := graph.Process()
+	if err != nil {
+		t.Fatalf("processing graph: error: %s", err.Error())
+	}
+
+	stereoOutput := make([]gsp.Stereo[float32], 2)
+	_, _ = stereo.Read(stereoOutput)
+
+	for i, want := range []float32{0.5, -0.5} {
+		if diff := stereoOutput[i].L() - want; diff > 1e-4 || diff < -1e-4 || stereoOutput[i].L() != stereoOutput[i].R() {
+			t.Errorf("wrong stereo frame at index '%d': got '%v', want '%v'", i, stereoOutput[i], gsp.MonoToStereo(want))
+		}
+	}
+
+	sinkOutput := make([]uint8, 2)
+	_, _ = sink.Read(sinkOutput)
+
+	for i, want := range []uint8{192, 64} {
+		if diff := int(sinkOutput[i]) - int(want); diff > 1 || diff < -1 {
+			t.Errorf("wrong frame at index '%d': got '%d', want '%d'", i, sinkOutput[i], want)
+		}
+	}
+}
+
+func TestGraphConvertedDither(t *testing.T) {
+	t.Parallel()
+
+	blockSize, blocks := 64, 8
+
+	graph := gsp.NewGraph(blockSize)
+
+	source := gsp.AddBus[gsp.Stereo[float64], float64](graph)
+	dithered := gsp.AddBus[gsp.Stereo[int16], int16](graph)
+	plain := gsp.AddBus[gsp.Stereo[int16], int16](graph)
+
+	opts := []gsp.ConvertOption{gsp.ConvertDither(gsp.DitherTPDF), gsp.ConvertNoiseShaping(gsp.NoiseShapingLipshitz), gsp.ConvertSeed(5)}
+
+	for _, err := range []error{
+		gsp.ConnectConverted[gsp.Stereo[float64], gsp.Stereo[int16], float64, int16](source, dithered, opts...),
+		gsp.ConnectConverted[gsp.Stereo[float64], gsp.Stereo[int16], float64, int16](source, plain),
+	} {
+		if err != nil {
+			t.Fatalf("connecting nodes: error: %s", err.Error())
+		}
+	}
+
+	input := make([]gsp.Stereo[float64], blockSize*blocks)
+	interleaved := make([]float64, 2*len(input))
+
+	for i := range input {
+		input[i] = gsp.ToStereo(0.3*math.Sin(float64(i)), 0.2*math.Cos(0.1*float64(i)))
+		interleaved[2*i], interleaved[2*i+1] = input[i].L(), input[i].R()
+	}
+
+	// The edge shapes the noise of each channel separately, across blocks, as a ditherer of interleaved samples does.
+	want := make([]int16, len(interleaved))
+	gsp.NewDitherer[int16, float64](2, opts...).ConvertSlice(want, interleaved)
+
+	ditheredOutput := make([]gsp.Stereo[int16], len(input))
+	plainOutput := make([]gsp.Stereo[int16], len(input))
+
+	for block := range blocks {
+		start, end := block*blockSize, (block+1)*blockSize
+
+		_, _ = source.Write(input[start:end])
+
+		err := graph.Process()
+		if err != nil {
+			t.Fatalf("processing graph: error: %s", err.Error())
+		}
+
+		_, _ = dithered.Read(ditheredOutput[start:end])
+		_, _ = plain.Read(plainOutput[start:end])
+	}
+
+	for i := range ditheredOutput {
+		if ditheredOutput[i].L() != want[2*i] || ditheredOutput[i].R() != want[2*i+1] {
+			t.Fatalf("wrong frame at index '%d': got '%v', want '%v'", i, ditheredOutput[i], gsp.ToStereo(want[2*i], want[2*i+1]))
+		}
+	}
+
+	if slices.Equal(ditheredOutput, plainOutput) {
+		t.Error("dithered output equals undithered output")
+	}
+}
+
+func TestGraphConvertedMultiChannel(t *testing.T) {
+	t.Parallel()
+
+	t.Run("mono", func(t *testing.T) {
+		t.Parallel()
+
+		graph := gsp.NewGraph(2)
+
+		source := gsp.AddBus[float32, float32](graph)
+		sink := gsp.AddBus[gsp.MultiChannel[float32], float32](graph)
+
+		_ = gsp.ConnectConverted[float32, gsp.MultiChannel[float32], float32, float32](source, sink)
+
+		graph.Init(gsp.Format{SampleRate: 48000, Channels: 4})
+
+		testGraphConvertedMultiChannel(t, graph, sink, func() {
+			_, _ = source.Write([]float32{0.5, -0.25})
+		}, []gsp.MultiChannel[float32]{{0.5, 0, 0, 0}, {-0.25, 0, 0, 0}})
+	})
+
+	t.Run("stereo", func(t *testing.T) {
+		t.Parallel()
+
+		graph := gsp.NewGraph(2)
+
+		source := gsp.AddBus[gsp.Stereo[int16], int16](graph)
+		sink := gsp.AddBus[gsp.MultiChannel[int16], int16](graph)
+
+		_ = gsp.ConnectConverted[gsp.Stereo[int16], gsp.MultiChannel[int16], int16, int16](source, sink)
+
+		graph.Init(gsp.Format{SampleRate: 48000, Channels: 3})
+
+		testGraphConvertedMultiChannel(t, graph, sink, func() {
+			_, _ = source.Write([]gsp.Stereo[int16]{gsp.ToStereo[int16](1, 2), gsp.ToStereo[int16](3, 4)})
+		}, []gsp.MultiChannel[int16]{{1, 2, 0}, {3, 4, 0}})
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		t.Parallel()
+
+		graph := gsp.NewGraph(2)
+
+		source := gsp.AddBus[gsp.Stereo[int16], int16](graph)
+		sink := gsp.AddBus[gsp.MultiChannel[int16], int16](graph)
+
+		_ = gsp.ConnectConverted[gsp.Stereo[int16], gsp.MultiChannel[int16], int16, int16](source, sink)
+
+		// Without format, the frames keep the channel count of the input.
+		testGraphConvertedMultiChannel(t, graph, sink, func() {
+			_, _ = source.Write([]gsp.Stereo[int16]{gsp.ToStereo[int16](1, 2), gsp.ToStereo[int16](3, 4)})
+		}, []gsp.MultiChannel[int16]{{1, 2}, {3, 4}})
+	})
+}
+
+// testGraphConvertedMultiChannel processes a block written by write, and verifies the multi-channel frames of sink.
+func testGraphConvertedMultiChannel[T gsp.Type](t *testing.T, graph *gsp.Graph, sink *gsp.Node[gsp.MultiChannel[T], T], write func(), want []gsp.MultiChannel[T]) {
+	t.Helper()
+
+	write()
+
+	err := graph.Process()
+	if err != nil {
+		t.Fatalf("processing graph: error: %s", err.Error())
+	}
+
+	output := make([]gsp.MultiChannel[T], len(want))
+	_, _ = sink.Read(output)
+
+	for i := range want {
+		if !slices.Equal(output[i], want[i]) {
+			t.Errorf("wrong frame at index '%d': got '%v', want '%v'", i, output[i], want[i])
+		}
+	}
+}
+
+func TestGraphError(t *testing.T) {
+	t.Parallel()
+
+	graph := gsp.NewGraph(4)
+
+	source := gsp.AddBus[float64, float64](graph)
+	failing := gsp.AddProcessor[float64, float64](graph, &failingProcessor{scaleProcessor: scaleProcessor{scale: 1}, failAt: 1})
+
+	_ = gsp.Connect(source, failing)
+
+	err := graph.Process()
+	if err != nil {
+		t.Fatalf("processing first block: error: %s", err.Error())
+	}
+
+	err = graph.Process()
+	if !errors.Is(err, errProcessing) {
+		t.Errorf("wrong error: got '%v', want '%v'", err, errProcessing)
+	}
+}
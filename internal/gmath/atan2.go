@@ -0,0 +1,111 @@
+package gmath
+
+import (
+	"math"
+	"unsafe"
+
+	"golang.org/x/exp/constraints"
+
+	math32 "github.com/samborkent/math32"
+)
+
+// Atan2 returns the arc tangent of y/x, using
+// the signs of the two to determine the quadrant
+// of the return value.
+//
+// Special cases are (in order):
+//
+//	Atan2(y, NaN) = NaN
+//	Atan2(NaN, x) = NaN
+//	Atan2(+0, x>=0) = +0
+//	Atan2(-0, x>=0) = -0
+//	Atan2(+0, x<=-0) = +Pi
+//	Atan2(-0, x<=-0) = -Pi
+//	Atan2(y>0, 0) = +Pi/2
+//	Atan2(y<0, 0) = -Pi/2
+//	Atan2(+Inf, +Inf) = +Pi/4
+//	Atan2(-Inf, +Inf) = -Pi/4
+//	Atan2(+Inf, -Inf) = 3Pi/4
+//	Atan2(-Inf, -Inf) = -3Pi/4
+//	Atan2(y, +Inf) = 0
+//	Atan2(y>0, -Inf) = +Pi
+//	Atan2(y<0, -Inf) = -Pi
+//	Atan2(+Inf, x) = +Pi/2
+//	Atan2(-Inf, x) = -Pi/2
+func Atan2[float constraints.Float](y, x float) float {
+	switch unsafe.Sizeof(x) {
+	case 4:
+		return float(atan2f32(float32(y), float32(x)))
+	case 8:
+		return float(math.Atan2(float64(y), float64(x)))
+	default:
+		panic("gmath: Atan2: unknown float bit size detected")
+	}
+}
+
+func atan2f32(y, x float32) float32 {
+	// special cases
+	switch {
+	case math32.IsNaN(y) || math32.IsNaN(x):
+		return math32.NaN()
+	case y == 0:
+		if x >= 0 && !math32.Signbit(x) {
+			return math32.Copysign(0, y)
+		}
+
+		return math32.Copysign(math.Pi, y)
+	case x == 0:
+		return math32.Copysign(math.Pi/2, y)
+	case math32.IsInf(x, 0):
+		if math32.IsInf(x, 1) {
+			if math32.IsInf(y, 0) {
+				return math32.Copysign(math.Pi/4, y)
+			}
+
+			return math32.Copysign(0, y)
+		}
+
+		if math32.IsInf(y, 0) {
+			return math32.Copysign(3*math.Pi/4, y)
+		}
+
+		return math32.Copysign(math.Pi, y)
+	case math32.IsInf(y, 0):
+		return math32.Copysign(math.Pi/2, y)
+	}
+
+	q := atan32(y / x)
+
+	if x < 0 {
+		// The sign of y, as y/x may underflow to zero.
+		return q + math32.Copysign(math.Pi, y)
+	}
+
+	return q
+}
+
+// atan32 returns the arc tangent of x, with the range reduction and polynomial of the Cephes atanf.
+func atan32(x float32) float32 {
+	if x == 0 {
+		return x
+	}
+
+	sign := float32(1)
+	if x < 0 {
+		sign, x = -1, -x
+	}
+
+	var offset float32
+
+	switch {
+	case x > math.Sqrt2+1: // tan(3Pi/8)
+		offset, x = math.Pi/2, -1/x
+	case x > math.Sqrt2-1: // tan(Pi/8)
+		offset, x = math.Pi/4, (x-1)/(x+1)
+	}
+
+	z := x * x
+	y := (((8.05374449538e-2*z-1.38776856032e-1)*z+1.99777106478e-1)*z-3.33329491539e-1)*z*x + x
+
+	return sign * (offset + y)
+}
@@ -0,0 +1,34 @@
+package gmath_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/samborkent/gsp/internal/gmath"
+)
+
+func TestAtan2Float32(t *testing.T) {
+	t.Parallel()
+
+	inf, nan := math.Inf(1), math.NaN()
+
+	values := []float64{0, math.Copysign(0, -1), 1e-30, 1e-3, 0.3, 0.5, 1, 2, 2.5, 100, 1e30, inf}
+	for _, value := range values[1:] {
+		values = append(values, -value)
+	}
+
+	for _, y := range values {
+		for _, x := range values {
+			want := math.Atan2(y, x)
+			got := float64(gmath.Atan2(float32(y), float32(x)))
+
+			if math.Signbit(got) != math.Signbit(want) || math.Abs(got-want) > 4e-7*math.Abs(want)+1e-38 {
+				t.Errorf("wrong arc tangent of '%g'/'%g': got '%g', want '%g'", y, x, got, want)
+			}
+		}
+	}
+
+	if got := gmath.Atan2(float32(nan), 1); !math.IsNaN(float64(got)) {
+		t.Errorf("wrong arc tangent of NaN: got '%g', want '%g'", got, nan)
+	}
+}
@@ -0,0 +1,54 @@
+package gmath
+
+import (
+	"math"
+	"unsafe"
+
+	"golang.org/x/exp/constraints"
+
+	math32 "github.com/samborkent/math32"
+)
+
+// Hypot returns Sqrt(p*p + q*q), taking care to avoid
+// unnecessary overflow and underflow.
+//
+// Special cases are:
+//
+//	Hypot(±Inf, q) = +Inf
+//	Hypot(p, ±Inf) = +Inf
+//	Hypot(NaN, q) = NaN
+//	Hypot(p, NaN) = NaN
+func Hypot[float constraints.Float](p, q float) float {
+	switch unsafe.Sizeof(p) {
+	case 4:
+		return float(hypot32(float32(p), float32(q)))
+	case 8:
+		return float(math.Hypot(float64(p), float64(q)))
+	default:
+		panic("gmath: Hypot: unknown float bit size detected")
+	}
+}
+
+func hypot32(p, q float32) float32 {
+	p, q = math32.Abs(p), math32.Abs(q)
+
+	// special cases
+	switch {
+	case math32.IsInf(p, 1) || math32.IsInf(q, 1):
+		return math32.Inf(1)
+	case math32.IsNaN(p) || math32.IsNaN(q):
+		return math32.NaN()
+	}
+
+	if p < q {
+		p, q = q, p
+	}
+
+	if p == 0 {
+		return 0
+	}
+
+	q = q / p
+
+	return p * math32.Sqrt(1+q*q)
+}
@@ -0,0 +1,29 @@
+package gmath
+
+import (
+	"math"
+	"unsafe"
+
+	"golang.org/x/exp/constraints"
+
+	math32 "github.com/samborkent/math32"
+)
+
+// Sqrt returns the square root of x.
+//
+// Special cases are:
+//
+//	Sqrt(+Inf) = +Inf
+//	Sqrt(±0) = ±0
+//	Sqrt(x < 0) = NaN
+//	Sqrt(NaN) = NaN
+func Sqrt[float constraints.Float](x float) float {
+	switch unsafe.Sizeof(x) {
+	case 4:
+		return float(math32.Sqrt(float32(x)))
+	case 8:
+		return float(math.Sqrt(float64(x)))
+	default:
+		panic("gmath: Sqrt: unknown float bit size detected")
+	}
+}
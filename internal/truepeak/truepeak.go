@@ -0,0 +1,74 @@
+// Package truepeak estimates the true peak of a signal by oversampling, as described by ITU-R BS.1770.
+package truepeak
+
+import (
+	"github.com/samborkent/gsp"
+	"github.com/samborkent/gsp/firdesign"
+	"github.com/samborkent/gsp/internal/gmath"
+	"github.com/samborkent/gsp/window"
+)
+
+const (
+	// Oversampling is the oversampling factor.
+	Oversampling = 4
+
+	// taps is the number of filter taps per phase.
+	taps = 12
+
+	// Delay is the delay in samples of the detected peaks relative to the input.
+	Delay = taps / 2
+)
+
+// Detector estimates the true peak of a single channel.
+type Detector[T gsp.Float] struct {
+	phases   [Oversampling][taps]T
+	history  [2 * taps]T // Written twice to avoid wrapping.
+	position int
+}
+
+func NewDetector[T gsp.Float]() *Detector[T] {
+	d := &Detector[T]{}
+
+	// Interpolation filter with its cutoff at the input Nyquist frequency.
+	kernel := firdesign.LowPass(Oversampling*taps, 0.5/Oversampling, window.Kaiser[T](Oversampling*taps, firdesign.KaiserBeta(60)))
+
+	// Split the kernel into phases, in reverse order such that they can be multiplied with the history from oldest to newest sample.
+	for i, h := range kernel {
+		d.phases[i%Oversampling][taps-1-i/Oversampling] = Oversampling * h
+	}
+
+	return d
+}
+
+// Reset clears the filter state.
+func (d *Detector[T]) Reset() {
+	clear(d.history[:])
+	d.position = 0
+}
+
+// Process adds a sample, and returns the absolute peak of the sample delayed by [Delay] samples and the interpolated samples following it.
+func (d *Detector[T]) Process(sample T) T {
+	d.history[d.position] = sample
+	d.history[d.position+taps] = sample
+
+	d.position++
+	if d.position == taps {
+		d.position = 0
+	}
+
+	history := d.history[d.position : d.position+taps]
+
+	// The interpolated samples lie between the input samples, so include the input sample itself.
+	peak := gmath.Abs(history[taps-1-Delay])
+
+	for p := range d.phases {
+		var sum T
+		for i := range history {
+			sum += history[i] * d.phases[p][i]
+		}
+
+		peak = max(peak, gmath.Abs(sum))
+	}
+
+	return peak
+}
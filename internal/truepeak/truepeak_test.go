@@ -0,0 +1,94 @@
+package truepeak_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/samborkent/gsp/internal/truepeak"
+)
+
+func TestDetectorImpulse(t *testing.T) {
+	t.Parallel()
+
+	const at = 3
+
+	detector := truepeak.NewDetector[float64]()
+
+	peakIndex, peak := 0, 0.0
+
+	for i := range 4 * truepeak.Delay {
+		sample := 0.0
+		if i == at {
+			sample = -0.5
+		}
+
+		if detected := detector.Process(sample); detected > peak {
+			peakIndex, peak = i, detected
+		}
+	}
+
+	// The sample itself is the true peak of an impulse.
+	if peak != 0.5 {
+		t.Errorf("wrong peak: got '%g', want '%g'", peak, 0.5)
+	}
+
+	if peakIndex-at != truepeak.Delay {
+		t.Errorf("wrong delay: got '%d', want '%d'", peakIndex-at, truepeak.Delay)
+	}
+}
+
+func TestDetectorInterSamplePeak(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		frequency float64 // Relative to the sample rate.
+		phase     float64
+		low, high float64
+	}{
+		{"DC", 0, math.Pi / 2, 0.999, 1.001},
+		{"on samples", 0.25, 0, 0.999, 1.001},
+		// Sample peaks are 3 dB below the true peak, the nearest interpolated samples are an eighth of a sample away from it.
+		{"between samples", 0.25, math.Pi / 4, math.Cos(math.Pi/16) - 0.01, 1.001},
+		{"low frequency", 0.01, 0.3, 0.999, 1.001},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			detector := truepeak.NewDetector[float64]()
+
+			var peak float64
+
+			for i := range 1000 {
+				sample := math.Sin(2*math.Pi*test.frequency*float64(i) + test.phase)
+
+				// Skip the filter settling.
+				if detected := detector.Process(sample); i > 100 {
+					peak = max(peak, detected)
+				}
+			}
+
+			if peak < test.low || peak > test.high {
+				t.Errorf("wrong peak: got '%g', want between '%g' and '%g'", peak, test.low, test.high)
+			}
+		})
+	}
+}
+
+func TestDetectorReset(t *testing.T) {
+	t.Parallel()
+
+	detector := truepeak.NewDetector[float32]()
+
+	for range 5 {
+		_ = detector.Process(1)
+	}
+
+	detector.Reset()
+
+	if peak := detector.Process(0); peak != 0 {
+		t.Errorf("wrong peak after reset: got '%g', want '%g'", peak, 0.0)
+	}
+}
@@ -0,0 +1,66 @@
+package loudness
+
+import "math"
+
+// Parameters of the K-weighting filter stages, from which the coefficients are derived for any sample rate.
+// At 48 kHz they reproduce the coefficients given in ITU-R BS.1770.
+const (
+	shelfFrequency = 1681.974450955533
+	shelfGain      = 3.999843853973347
+	shelfQ         = 0.7071752369554196
+
+	highPassFrequency = 38.13547087602444
+	highPassQ         = 0.5003270373238773
+)
+
+type biquad struct {
+	b0, b1, b2, a1, a2 float64
+	s1, s2             float64
+}
+
+func (f *biquad) process(x float64) float64 {
+	y := f.b0*x + f.s1
+	f.s1 = f.b1*x - f.a1*y + f.s2
+	f.s2 = f.b2*x - f.a2*y
+
+	return y
+}
+
+// kWeighting is the pre-filter of ITU-R BS.1770, a high-shelf modelling the acoustic effect of the head followed by a high-pass.
+type kWeighting struct {
+	shelf, highPass biquad
+}
+
+func newKWeighting(sampleRate int) kWeighting {
+	var k kWeighting
+
+	K := math.Tan(math.Pi * shelfFrequency / float64(sampleRate))
+	vh := math.Pow(10, shelfGain/20)
+	vb := math.Pow(vh, 0.4996667741545416)
+	a0 := 1 + K/shelfQ + K*K
+
+	k.shelf = biquad{
+		b0: (vh + vb*K/shelfQ + K*K) / a0,
+		b1: 2 * (K*K - vh) / a0,
+		b2: (vh - vb*K/shelfQ + K*K) / a0,
+		a1: 2 * (K*K - 1) / a0,
+		a2: (1 - K/shelfQ + K*K) / a0,
+	}
+
+	K = math.Tan(math.Pi * highPassFrequency / float64(sampleRate))
+	a0 = 1 + K/highPassQ + K*K
+
+	k.highPass = biquad{
+		b0: 1,
+		b1: -2,
+		b2: 1,
+		a1: 2 * (K*K - 1) / a0,
+		a2: (1 - K/highPassQ + K*K) / a0,
+	}
+
+	return k
+}
+
+func (k *kWeighting) process(x float64) float64 {
+	return k.highPass.process(k.shelf.process(x))
+}
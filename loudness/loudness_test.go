@@ -0,0 +1,216 @@
+package loudness_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/samborkent/gsp"
+	"github.com/samborkent/gsp/loudness"
+)
+
+const sampleRate = 48000
+
+func TestMeasureSine(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		amplitude  float64
+		integrated float64
+	}{
+		{"-20 dBFS", 0.1, -20},
+		{"-6 dBFS", 0.5, -6.02},
+		{"-40 dBFS", 0.01, -40},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// A 1 kHz sine in both channels has the same loudness in LUFS as its level in dBFS.
+			frames := sine(10*sampleRate, 1000, test.amplitude)
+
+			result, err := loudness.Measure[gsp.Stereo[float64], float64](gsp.NewBuffer[gsp.Stereo[float64], float64](frames), sampleRate)
+			if err != nil {
+				t.Fatalf("measuring: error: %s", err.Error())
+			}
+
+			if math.Abs(result.Integrated-test.integrated) > 0.1 {
+				t.Errorf("wrong integrated loudness: got '%g', want '%g'", result.Integrated, test.integrated)
+			}
+
+			if math.Abs(result.MaxShortTerm-test.integrated) > 0.1 {
+				t.Errorf("wrong maximum short-term loudness: got '%g', want '%g'", result.MaxShortTerm, test.integrated)
+			}
+
+			if result.LoudnessRange > 0.1 {
+				t.Errorf("wrong loudness range: got '%g', want '%g'", result.LoudnessRange, 0.0)
+			}
+
+			if truePeak := 20 * math.Log10(test.amplitude); math.Abs(result.TruePeak-truePeak) > 0.1 {
+				t.Errorf("wrong true peak: got '%g', want '%g'", result.TruePeak, truePeak)
+			}
+
+			if result.Frames != int64(len(frames)) {
+				t.Errorf("wrong number of frames: got '%d', want '%d'", result.Frames, len(frames))
+			}
+		})
+	}
+}
+
+func TestMeterGating(t *testing.T) {
+	t.Parallel()
+
+	meter := loudness.NewMeter[gsp.Stereo[float32], float32](sampleRate)
+
+	// Loud and quiet passages of equal length, with silence which is excluded by the absolute gate.
+	for _, amplitude := range []float64{0.1, math.Pow(10, -25.0/20), 0} {
+		frames := sine(20*sampleRate, 1000, amplitude)
+
+		input := make([]gsp.Stereo[float32], len(frames))
+		for i := range frames {
+			input[i] = gsp.Stereo[float32]{float32(frames[i][0]), float32(frames[i][1])}
+		}
+
+		meter.ProcessBuffer(input, input)
+	}
+
+	// The quiet passage at -25 LUFS is within 10 LU of the loud passage at -20 LUFS, so both count.
+	want := 10 * math.Log10((math.Pow(10, -2)+math.Pow(10, -2.5))/2)
+	if got := float64(meter.Integrated()); math.Abs(got-want) > 0.1 {
+		t.Errorf("wrong integrated loudness: got '%g', want '%g'", got, want)
+	}
+
+	if got := float64(meter.LoudnessRange()); math.Abs(got-5) > 0.2 {
+		t.Errorf("wrong loudness range: got '%g', want '%g'", got, 5.0)
+	}
+
+	if got := meter.Momentary(); !math.IsInf(float64(got), -1) {
+		t.Errorf("wrong momentary loudness: got '%g', want '%g'", got, math.Inf(-1))
+	}
+}
+
+func TestMeterSurround(t *testing.T) {
+	t.Parallel()
+
+	meter := loudness.NewMeter[gsp.MultiChannel[float64], float64](sampleRate)
+	frames := sine(5*sampleRate, 1000, 0.1)
+
+	// A signal in the low-frequency effects channel is not measured, while a surround channel is weighted by 1.5 dB.
+	input := make([]gsp.MultiChannel[float64], len(frames))
+	for i := range frames {
+		input[i] = gsp.MultiChannel[float64]{0, 0, 0, frames[i][0], frames[i][0], 0}
+	}
+
+	meter.ProcessBuffer(input, input)
+
+	want := -20 - 3.01 + 10*math.Log10(1.41)
+	if got := meter.Integrated(); math.Abs(got-want) > 0.1 {
+		t.Errorf("wrong integrated loudness: got '%g', want '%g'", got, want)
+	}
+}
+
+func sine(frames int, frequency, amplitude float64) []gsp.Stereo[float64] {
+	samples := make([]gsp.Stereo[float64], frames)
+
+	for i := range samples {
+		sample := amplitude * math.Sin(2*math.Pi*frequency*float64(i)/sampleRate)
+		samples[i] = gsp.Stereo[float64]{sample, sample}
+	}
+
+	return samples
+}
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		target  float64
+		limited bool
+	}{
+		{"attenuate", -30, false},
+		{"amplify", -16, false},
+		{"amplify with limiting", -8, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// A sine at -20 dBFS with a short burst at -3 dBFS.
+			frames := sine(10*sampleRate, 1000, 0.1)
+			for i := 5 * sampleRate; i < 5*sampleRate+sampleRate/100; i++ {
+				frames[i] = frames[i].Multiply(7)
+			}
+
+			output := new(gsp.Buffer[gsp.Stereo[float64], float64])
+			config := loudness.NormalizerConfig[float64]{Target: test.target, Ceiling: -1}
+
+			gain, err := loudness.Normalize[gsp.Stereo[float64], float64](output, gsp.NewBuffer[gsp.Stereo[float64], float64](append([]gsp.Stereo[float64](nil), frames...)), sampleRate, config)
+			if err != nil {
+				t.Fatalf("normalizing: error: %s", err.Error())
+			}
+
+			if output.Len() != len(frames) {
+				t.Fatalf("wrong number of frames: got '%d', want '%d'", output.Len(), len(frames))
+			}
+
+			// Away from the burst, the output is the input scaled by the gain.
+			got, want := output.Frames()[sampleRate][gsp.L], frames[sampleRate][gsp.L]*gsp.DBToLinear(gain)
+			if math.Abs(got-want) > 1e-9 {
+				t.Errorf("wrong sample: got '%g', want '%g'", got, want)
+			}
+
+			result, err := loudness.Measure[gsp.Stereo[float64], float64](output, sampleRate)
+			if err != nil {
+				t.Fatalf("measuring: error: %s", err.Error())
+			}
+
+			// Limiting lowers the loudness slightly.
+			tolerance := 0.1
+			if test.limited {
+				tolerance = 0.5
+			}
+
+			if math.Abs(result.Integrated-test.target) > tolerance {
+				t.Errorf("wrong integrated loudness: got '%g', want '%g'", result.Integrated, test.target)
+			}
+
+			if test.limited && result.TruePeak > config.Ceiling+0.1 {
+				t.Errorf("wrong true peak: got '%g', want at most '%g'", result.TruePeak, config.Ceiling)
+			}
+		})
+	}
+}
+
+func TestNormalizer(t *testing.T) {
+	t.Parallel()
+
+	const target = -16
+
+	normalizer := loudness.NewNormalizer[gsp.Stereo[float32], float32](sampleRate, loudness.NormalizerConfig[float32]{
+		Target:     target,
+		Ceiling:    -1,
+		Adaptation: time.Second,
+	})
+
+	frames := sine(20*sampleRate, 1000, 0.03)
+	input := make([]gsp.Stereo[float32], len(frames))
+	for i := range frames {
+		input[i] = gsp.Stereo[float32]{float32(frames[i][0]), float32(frames[i][1])}
+	}
+
+	output := make([]gsp.Stereo[float32], len(input))
+	for start := 0; start < len(input); start += 480 {
+		normalizer.ProcessBuffer(output[start:start+480], input[start:start+480])
+	}
+
+	meter := loudness.NewMeter[gsp.Stereo[float32], float32](sampleRate)
+	meter.ProcessBuffer(output, output)
+
+	if got := meter.ShortTerm(); math.Abs(float64(got)-target) > 0.5 {
+		t.Errorf("wrong short-term loudness: got '%g', want '%g'", got, float64(target))
+	}
+}
@@ -0,0 +1,55 @@
+package loudness
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/samborkent/gsp"
+)
+
+// measureBufferSize is the number of frames read per call by [Measure].
+const measureBufferSize = 4096
+
+// Result contains the loudness measurements of a complete stream.
+type Result[T gsp.Float] struct {
+	Integrated    T     // Integrated loudness in LUFS.
+	LoudnessRange T     // Loudness range in LU.
+	TruePeak      T     // Maximum true peak in dBTP.
+	MaxMomentary  T     // Maximum momentary loudness in LUFS.
+	MaxShortTerm  T     // Maximum short-term loudness in LUFS.
+	Frames        int64 // Number of frames measured.
+}
+
+// Measure reads r until [io.EOF], and returns its loudness measurements.
+func Measure[F gsp.Frame[T], T gsp.Float](r gsp.Reader[F, T], sampleRate int, opts ...MeterOption) (Result[T], error) {
+	meter := NewMeter[F, T](sampleRate, opts...)
+	buffer := make([]F, measureBufferSize)
+
+	for {
+		n, err := r.Read(buffer)
+		meter.ProcessBuffer(buffer[:n], buffer[:n])
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return Result[T]{}, fmt.Errorf("gsp: loudness.Measure: reading frames: %w", err)
+		}
+	}
+
+	return meter.Result(), nil
+}
+
+// Result returns all measurements.
+func (m *Meter[F, T]) Result() Result[T] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return Result[T]{
+		Integrated:    T(integrated(m.momentaryBlocks)),
+		LoudnessRange: T(loudnessRange(m.shortTermBlocks)),
+		TruePeak:      T(amplitudeToDB(m.truePeak)),
+		MaxMomentary:  T(energyToLoudness(m.maxMomentary)),
+		MaxShortTerm:  T(energyToLoudness(m.maxShortTerm)),
+		Frames:        m.frames,
+	}
+}
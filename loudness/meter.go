@@ -0,0 +1,392 @@
+// Package loudness measures and normalizes loudness according to ITU-R BS.1770 and EBU R128.
+package loudness
+
+import (
+	"math"
+	"slices"
+	"sync"
+	"unsafe"
+
+	"github.com/samborkent/gsp"
+	"github.com/samborkent/gsp/internal/truepeak"
+)
+
+const (
+	// Gating block durations in steps of 100 ms.
+	momentarySteps = 4
+	shortTermSteps = 30
+
+	absoluteGate           = -70 // Absolute gating threshold in LUFS.
+	integratedRelativeGate = -10 // Relative gating threshold of integrated loudness in LU.
+	rangeRelativeGate      = -20 // Relative gating threshold of the loudness range in LU.
+)
+
+// MeterOption is a functional option for [Meter].
+type MeterOption func(cfg *MeterConfig)
+
+// MeterConfig contains all configuration options for [Meter].
+type MeterConfig struct {
+	Weights []float64 // Channel weights, defaults to the BS.1770 weights of mono, stereo and 5.1 streams.
+}
+
+// MeterWeights sets the weight of each channel.
+// Low-frequency effects channels should have a weight of zero, and surround channels a weight of 1.41.
+func MeterWeights(weights ...float64) MeterOption {
+	return func(cfg *MeterConfig) {
+		cfg.Weights = weights
+	}
+}
+
+// Meter measures the loudness of a stream according to ITU-R BS.1770 and EBU R128.
+// It is a pass-through processor, so it can be inserted anywhere in a pipeline.
+// The readings are safe to call concurrently with processing.
+type Meter[F gsp.Frame[T], T gsp.Float] struct {
+	mu sync.Mutex
+
+	sampleRate int
+	stepSize   int
+	weights    []float64
+	configured []float64
+
+	filters   []kWeighting
+	detectors []*truepeak.Detector[float64]
+	truePeak  float64
+
+	// Weighted energy of the current step, and of the most recent steps.
+	energy      float64
+	stepFrames  int
+	steps       [shortTermSteps]float64
+	step        int
+	stepsStored int
+
+	momentaryBlocks []float64 // Energy of all 400 ms blocks, used for integrated loudness.
+	shortTermBlocks []float64 // Energy of all 3 s blocks, used for the loudness range.
+	maxMomentary    float64
+	maxShortTerm    float64
+	frames          int64
+
+	frameChannels int // Number of channels of mono and stereo frames, zero for multi-channel frames.
+}
+
+func NewMeter[F gsp.Frame[T], T gsp.Float](sampleRate int, opts ...MeterOption) *Meter[F, T] {
+	var cfg MeterConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	meter := &Meter[F, T]{configured: cfg.Weights}
+
+	switch any(*new(F)).(type) {
+	case T:
+		meter.frameChannels = 1
+	case [2]T, gsp.Stereo[T]:
+		meter.frameChannels = 2
+	case []T, gsp.MultiChannel[T]:
+		// Channel count is taken from the first frame.
+	default:
+		panic("gsp: loudness.NewMeter: unknown audio frame type")
+	}
+
+	meter.Init(gsp.Format{SampleRate: sampleRate, Channels: meter.frameChannels})
+
+	return meter
+}
+
+// Init sets the sample rate and number of channels, and resets the measurement.
+func (m *Meter[F, T]) Init(format gsp.Format) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if format.SampleRate > 0 {
+		m.sampleRate = format.SampleRate
+	}
+
+	m.stepSize = max(1, m.sampleRate/10)
+
+	channels := len(m.filters)
+	if format.Channels > 0 {
+		channels = format.Channels
+	}
+
+	m.allocate(channels)
+}
+
+// Reset discards the measurement.
+func (m *Meter[F, T]) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.allocate(len(m.filters))
+}
+
+// Momentary returns the loudness in LUFS of the last 400 ms.
+func (m *Meter[F, T]) Momentary() T {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return T(m.recentLoudness(momentarySteps))
+}
+
+// ShortTerm returns the loudness in LUFS of the last 3 s.
+func (m *Meter[F, T]) ShortTerm() T {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return T(m.recentLoudness(shortTermSteps))
+}
+
+// MaxMomentary returns the maximum momentary loudness in LUFS.
+func (m *Meter[F, T]) MaxMomentary() T {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return T(energyToLoudness(m.maxMomentary))
+}
+
+// MaxShortTerm returns the maximum short-term loudness in LUFS.
+func (m *Meter[F, T]) MaxShortTerm() T {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return T(energyToLoudness(m.maxShortTerm))
+}
+
+// Integrated returns the gated loudness in LUFS of everything measured so far.
+// It returns negative infinity until a block above the absolute gate is measured.
+func (m *Meter[F, T]) Integrated() T {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return T(integrated(m.momentaryBlocks))
+}
+
+// LoudnessRange returns the loudness range (LRA) in LU of everything measured so far, according to EBU Tech 3342.
+func (m *Meter[F, T]) LoudnessRange() T {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return T(loudnessRange(m.shortTermBlocks))
+}
+
+// TruePeak returns the maximum true peak in dBTP of all channels.
+func (m *Meter[F, T]) TruePeak() T {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return T(amplitudeToDB(m.truePeak))
+}
+
+// Process measures sample and returns it unchanged.
+func (m *Meter[F, T]) Process(sample F) F {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.measure(m.samples(&sample))
+
+	return sample
+}
+
+// ProcessBuffer measures input and copies it to output.
+func (m *Meter[F, T]) ProcessBuffer(output, input []F) {
+	size := min(len(output), len(input))
+	if size == 0 {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range size {
+		m.measure(m.samples(&input[i]))
+	}
+
+	if unsafe.SliceData(output) != unsafe.SliceData(input) {
+		copy(output, input[:size])
+	}
+}
+
+// measure adds a single frame to the measurement.
+func (m *Meter[F, T]) measure(samples []T) {
+	if len(samples) != len(m.filters) {
+		m.allocate(len(samples))
+	}
+
+	for c, sample := range samples {
+		x := float64(sample)
+		m.truePeak = max(m.truePeak, m.detectors[c].Process(x))
+
+		z := m.filters[c].process(x)
+		m.energy += m.weights[c] * z * z
+	}
+
+	m.frames++
+	m.stepFrames++
+	if m.stepFrames < m.stepSize {
+		return
+	}
+
+	m.steps[m.step] = m.energy / float64(m.stepSize)
+	m.step = (m.step + 1) % shortTermSteps
+	m.stepsStored++
+	m.energy = 0
+	m.stepFrames = 0
+
+	if m.stepsStored >= momentarySteps {
+		energy := m.recentEnergy(momentarySteps)
+		m.momentaryBlocks = append(m.momentaryBlocks, energy)
+		m.maxMomentary = max(m.maxMomentary, energy)
+	}
+
+	if m.stepsStored >= shortTermSteps {
+		energy := m.recentEnergy(shortTermSteps)
+		m.shortTermBlocks = append(m.shortTermBlocks, energy)
+		m.maxShortTerm = max(m.maxShortTerm, energy)
+	}
+}
+
+// recentEnergy returns the mean weighted energy of the most recent steps.
+func (m *Meter[F, T]) recentEnergy(steps int) float64 {
+	sum := 0.0
+	for i := 1; i <= steps; i++ {
+		sum += m.steps[(m.step-i+shortTermSteps)%shortTermSteps]
+	}
+
+	return sum / float64(steps)
+}
+
+func (m *Meter[F, T]) recentLoudness(steps int) float64 {
+	if m.stepsStored < steps {
+		return math.Inf(-1)
+	}
+
+	return energyToLoudness(m.recentEnergy(steps))
+}
+
+// allocate creates filters for the number of channels, and resets the measurement.
+func (m *Meter[F, T]) allocate(channels int) {
+	m.filters = make([]kWeighting, channels)
+	m.detectors = make([]*truepeak.Detector[float64], channels)
+	m.weights = channelWeights(channels, m.configured)
+
+	for c := range channels {
+		m.filters[c] = newKWeighting(m.sampleRate)
+		m.detectors[c] = truepeak.NewDetector[float64]()
+	}
+
+	m.truePeak = 0
+	m.energy = 0
+	m.stepFrames = 0
+	m.steps = [shortTermSteps]float64{}
+	m.step = 0
+	m.stepsStored = 0
+	m.momentaryBlocks = m.momentaryBlocks[:0]
+	m.shortTermBlocks = m.shortTermBlocks[:0]
+	m.maxMomentary = 0
+	m.maxShortTerm = 0
+	m.frames = 0
+}
+
+// samples returns the samples of a frame as a slice aliasing the frame.
+func (m *Meter[F, T]) samples(frame *F) []T {
+	if m.frameChannels == 0 {
+		return *(*[]T)(unsafe.Pointer(frame))
+	}
+
+	return unsafe.Slice((*T)(unsafe.Pointer(frame)), m.frameChannels)
+}
+
+// channelWeights returns the configured weights, or the BS.1770 weights for the number of channels.
+// The 5.1 weights assume the channel order L, R, C, LFE, Ls, Rs.
+func channelWeights(channels int, configured []float64) []float64 {
+	weights := make([]float64, channels)
+
+	for c := range weights {
+		switch {
+		case c < len(configured):
+			weights[c] = configured[c]
+		case len(configured) > 0:
+			weights[c] = 0
+		case channels == 6 && c == 3:
+			weights[c] = 0
+		case channels == 6 && c >= 4:
+			weights[c] = 1.41
+		default:
+			weights[c] = 1
+		}
+	}
+
+	return weights
+}
+
+func amplitudeToDB(amplitude float64) float64 {
+	return 20 * math.Log10(amplitude)
+}
+
+func energyToLoudness(energy float64) float64 {
+	if energy <= 0 {
+		return math.Inf(-1)
+	}
+
+	return -0.691 + 10*math.Log10(energy)
+}
+
+func loudnessToEnergy(loudness float64) float64 {
+	return math.Pow(10, (loudness+0.691)/10)
+}
+
+// integrated returns the gated loudness of the momentary blocks.
+func integrated(blocks []float64) float64 {
+	threshold := loudnessToEnergy(absoluteGate)
+
+	relative := energyToLoudness(gatedMean(blocks, threshold)) + integratedRelativeGate
+	threshold = max(threshold, loudnessToEnergy(relative))
+
+	return energyToLoudness(gatedMean(blocks, threshold))
+}
+
+// loudnessRange returns the difference between the 95th and 10th percentile of the gated short-term loudness.
+func loudnessRange(blocks []float64) float64 {
+	threshold := loudnessToEnergy(absoluteGate)
+
+	relative := energyToLoudness(gatedMean(blocks, threshold)) + rangeRelativeGate
+	threshold = max(threshold, loudnessToEnergy(relative))
+
+	var gated []float64
+	for _, energy := range blocks {
+		if energy > threshold {
+			gated = append(gated, energy)
+		}
+	}
+
+	if len(gated) == 0 {
+		return 0
+	}
+
+	slices.Sort(gated)
+
+	return energyToLoudness(percentile(gated, 0.95)) - energyToLoudness(percentile(gated, 0.10))
+}
+
+// gatedMean returns the mean energy of the blocks above the threshold.
+func gatedMean(blocks []float64, threshold float64) float64 {
+	sum, count := 0.0, 0
+
+	for _, energy := range blocks {
+		if energy > threshold {
+			sum += energy
+			count++
+		}
+	}
+
+	if count == 0 {
+		return 0
+	}
+
+	return sum / float64(count)
+}
+
+// percentile returns the nearest-rank percentile of sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	index := int(math.Round(p * float64(len(sorted)-1)))
+	return sorted[index]
+}
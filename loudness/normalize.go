@@ -0,0 +1,194 @@
+package loudness
+
+import (
+	"fmt"
+	"math"
+	"time"
+	"unsafe"
+
+	"github.com/samborkent/gsp"
+	"github.com/samborkent/gsp/processors"
+)
+
+const (
+	normalizeBufferSize         = 4096
+	normalizerChunkSize         = 32
+	normalizerDefaultAdaptation = 10 * time.Second
+)
+
+// NormalizerConfig contains the parameters of loudness normalization.
+type NormalizerConfig[T gsp.Float] struct {
+	Target     T             // Target loudness in LUFS.
+	Ceiling    T             // Maximum true peak in dBTP, enforced by a true-peak limiter.
+	MaxGain    T             // Maximum gain in dB, which prevents amplifying noise, zero disables it.
+	Adaptation time.Duration // Time constant of the gain of streaming normalization, defaults to 10 s.
+}
+
+// Normalize reads r until [io.EOF], and writes it to w with the gain which brings its integrated loudness to the target.
+// If the gain pushes the true peak above the ceiling, the signal is limited, without changing its length or alignment.
+// The whole signal is buffered between measurement and processing, so Normalize is meant for files rather than live streams.
+// It returns the applied gain in dB.
+func Normalize[F gsp.Frame[T], T gsp.Float](w gsp.Writer[F, T], r gsp.Reader[F, T], sampleRate int, config NormalizerConfig[T]) (T, error) {
+	signal := new(gsp.Buffer[F, T])
+
+	_, err := signal.ReadFrom(r)
+	if err != nil {
+		return 0, fmt.Errorf("gsp: loudness.Normalize: reading frames: %w", err)
+	}
+
+	frames := signal.Frames()
+
+	meter := NewMeter[F, T](sampleRate)
+	meter.ProcessBuffer(frames, frames)
+	result := meter.Result()
+
+	gain := T(0)
+	if !math.IsInf(float64(result.Integrated), -1) {
+		gain = config.Target - result.Integrated
+	}
+
+	if config.MaxGain > 0 {
+		gain = min(gain, config.MaxGain)
+	}
+
+	gainProcessor := processors.NewGain[F, T](gain)
+
+	var limiter *processors.Limiter[F, T]
+	if result.TruePeak+gain > config.Ceiling {
+		limiter = processors.NewLimiter[F, T](sampleRate, processors.LimiterConfig[T]{
+			Ceiling:  config.Ceiling,
+			TruePeak: true,
+		})
+	}
+
+	latency := 0
+	if limiter != nil {
+		latency = limiter.Latency()
+
+		// Append silence to flush the limiter delay line.
+		tail := make([]F, latency)
+		if meter.frameChannels == 0 && len(frames) > 0 {
+			channels := len(*(*[]T)(unsafe.Pointer(&frames[0])))
+			for i := range tail {
+				frame := gsp.ZeroMultiChannel[T](channels)
+				tail[i] = *(*F)(unsafe.Pointer(&frame))
+			}
+		}
+
+		_, _ = signal.Write(tail)
+		frames = signal.Frames()
+	}
+
+	for start := 0; start < len(frames); start += normalizeBufferSize {
+		chunk := frames[start:min(start+normalizeBufferSize, len(frames))]
+
+		gainProcessor.ProcessBuffer(chunk, chunk)
+
+		if limiter != nil {
+			limiter.ProcessBuffer(chunk, chunk)
+		}
+	}
+
+	// Skip the frames delayed by the limiter.
+	_, err = w.Write(frames[latency:])
+	if err != nil {
+		return gain, fmt.Errorf("gsp: loudness.Normalize: writing frames: %w", err)
+	}
+
+	return gain, nil
+}
+
+// Normalizer is a streaming loudness normalizer for live streams.
+// Its gain slowly adapts towards the gain which brings the short-term loudness to the target,
+// and is held while the input is silent. A true-peak limiter enforces the ceiling.
+type Normalizer[F gsp.Frame[T], T gsp.Float] struct {
+	config     NormalizerConfig[T]
+	sampleRate int
+
+	meter   *Meter[F, T]
+	gain    *processors.Gain[F, T]
+	limiter *processors.Limiter[F, T]
+
+	adaptation T // Smoothing coefficient of the gain per chunk.
+	gainDB     T
+}
+
+func NewNormalizer[F gsp.Frame[T], T gsp.Float](sampleRate int, config NormalizerConfig[T]) *Normalizer[F, T] {
+	if config.Adaptation <= 0 {
+		config.Adaptation = normalizerDefaultAdaptation
+	}
+
+	normalizer := &Normalizer[F, T]{
+		config:     config,
+		sampleRate: sampleRate,
+		meter:      NewMeter[F, T](sampleRate),
+		gain:       processors.NewGain[F, T](0),
+		limiter: processors.NewLimiter[F, T](sampleRate, processors.LimiterConfig[T]{
+			Ceiling:  config.Ceiling,
+			TruePeak: true,
+		}),
+	}
+
+	normalizer.Init(gsp.Format{SampleRate: sampleRate})
+
+	return normalizer
+}
+
+// Init sets the sample rate and number of channels, and resets the gain.
+func (p *Normalizer[F, T]) Init(format gsp.Format) {
+	if format.SampleRate > 0 {
+		p.sampleRate = format.SampleRate
+	}
+
+	p.meter.Init(format)
+	p.limiter.Init(format)
+
+	p.adaptation = T(math.Exp(-normalizerChunkSize / (p.config.Adaptation.Seconds() * float64(p.sampleRate))))
+
+	p.Reset()
+}
+
+// Reset resets the gain and the loudness measurement.
+func (p *Normalizer[F, T]) Reset() {
+	p.meter.Reset()
+	p.limiter.Reset()
+	p.gainDB = 0
+}
+
+// Gain returns the current gain in dB.
+func (p *Normalizer[F, T]) Gain() T {
+	return p.gainDB
+}
+
+// Latency returns the number of frames by which the output is delayed.
+func (p *Normalizer[F, T]) Latency() int {
+	return p.limiter.Latency()
+}
+
+func (p *Normalizer[F, T]) ProcessBuffer(output, input []F) {
+	size := min(len(output), len(input))
+	if size == 0 {
+		return
+	}
+
+	for start := 0; start < size; start += normalizerChunkSize {
+		end := min(start+normalizerChunkSize, size)
+
+		p.meter.ProcessBuffer(input[start:end], input[start:end])
+
+		// Hold the gain while the loudness is unknown or below the absolute gate.
+		if loudness := p.meter.ShortTerm(); loudness > absoluteGate {
+			target := p.config.Target - loudness
+			if p.config.MaxGain > 0 {
+				target = min(target, p.config.MaxGain)
+			}
+
+			p.gainDB = p.adaptation*p.gainDB + (1-p.adaptation)*target
+		}
+
+		p.gain.Gain = gsp.DBToLinear(p.gainDB)
+		p.gain.ProcessBuffer(output[start:end], input[start:end])
+	}
+
+	p.limiter.ProcessBuffer(output[:size], output[:size])
+}
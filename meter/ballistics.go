@@ -0,0 +1,123 @@
+package meter
+
+import (
+	"math"
+	"time"
+)
+
+// Ballistics selects how a [Meter] responds to the signal.
+type Ballistics string
+
+const (
+	// BallisticsPeak is a digital sample peak meter with instant attack, falling back 20 dB in 1.7 s as in IEC 60268-18.
+	BallisticsPeak Ballistics = "peak"
+	// BallisticsRMS averages the signal power over 300 ms.
+	BallisticsRMS Ballistics = "RMS"
+	// BallisticsVU is a volume unit meter, which reaches 99% of a step in 300 ms.
+	// It reads the average rectified level, calibrated to read the RMS level of a sine.
+	BallisticsVU Ballistics = "VU"
+	// BallisticsPPMTypeI is a quasi-peak programme meter with 5 ms integration time, falling back 20 dB in 1.5 s as in IEC 60268-10 Type I.
+	BallisticsPPMTypeI Ballistics = "PPM type I"
+	// BallisticsPPMTypeII is a quasi-peak programme meter with 10 ms integration time, falling back 24 dB in 2.8 s as in IEC 60268-10 Type II.
+	BallisticsPPMTypeII Ballistics = "PPM type II"
+	// BallisticsCrest reads the crest factor, the ratio of the peak level to the RMS level.
+	BallisticsCrest Ballistics = "crest"
+)
+
+const (
+	rmsWindow = 300 * time.Millisecond
+
+	// The critically damped VU response reaches 99% after 6.64 time constants.
+	vuRiseTime      = 300 * time.Millisecond
+	vuTimeConstants = 6.64
+
+	// Ratio of the RMS level to the average rectified level of a sine.
+	sineFormFactor = math.Pi / (2 * math.Sqrt2)
+
+	// A tone burst of the integration time reads 2 dB below the steady state level.
+	ppmBurstLevel = -2
+)
+
+// coefficients holds the per-sample smoothing coefficients of a ballistics type.
+type coefficients struct {
+	attack float64 // One-pole smoothing coefficient of rising levels.
+	fall   float64 // Multiplier per sample of falling levels.
+	rms    float64 // One-pole smoothing coefficient of the mean square level.
+}
+
+func newCoefficients(ballistics Ballistics, sampleRate int) coefficients {
+	rate := float64(sampleRate)
+
+	onePole := func(timeConstant float64) float64 {
+		return math.Exp(-1 / (timeConstant * rate))
+	}
+
+	fall := func(dB float64, d time.Duration) float64 {
+		return math.Pow(10, -dB/20/(d.Seconds()*rate))
+	}
+
+	// The time constant for which a burst of the integration time reaches the burst level.
+	integration := func(d time.Duration) float64 {
+		return onePole(d.Seconds() / -math.Log(1-math.Pow(10, ppmBurstLevel/20.0)))
+	}
+
+	switch ballistics {
+	case BallisticsPeak:
+		return coefficients{fall: fall(20, 1700*time.Millisecond)}
+	case BallisticsRMS:
+		return coefficients{rms: onePole(rmsWindow.Seconds())}
+	case BallisticsVU:
+		return coefficients{attack: onePole(vuRiseTime.Seconds() / vuTimeConstants)}
+	case BallisticsPPMTypeI:
+		return coefficients{attack: integration(5 * time.Millisecond), fall: fall(20, 1500*time.Millisecond)}
+	case BallisticsPPMTypeII:
+		return coefficients{attack: integration(10 * time.Millisecond), fall: fall(24, 2800*time.Millisecond)}
+	case BallisticsCrest:
+		return coefficients{fall: fall(20, 1700*time.Millisecond), rms: onePole(rmsWindow.Seconds())}
+	default:
+		panic("gsp: meter: ballistics not implemented")
+	}
+}
+
+// channel is the meter state of a single channel.
+type channel struct {
+	envelope, smoothed, meanSquare float64
+}
+
+// update adds a sample, and returns the linear reading.
+func (s *channel) update(ballistics Ballistics, c *coefficients, sample float64) float64 {
+	rectified := math.Abs(sample)
+
+	switch ballistics {
+	case BallisticsPeak:
+		s.envelope = max(rectified, s.envelope*c.fall)
+		return s.envelope
+	case BallisticsRMS:
+		s.meanSquare = c.rms*s.meanSquare + (1-c.rms)*sample*sample
+		return math.Sqrt(s.meanSquare)
+	case BallisticsVU:
+		// Two cascaded one-pole filters give a critically damped response.
+		s.envelope = c.attack*s.envelope + (1-c.attack)*rectified
+		s.smoothed = c.attack*s.smoothed + (1-c.attack)*s.envelope
+		return s.smoothed * sineFormFactor
+	case BallisticsPPMTypeI, BallisticsPPMTypeII:
+		if rectified > s.envelope {
+			s.envelope = c.attack*s.envelope + (1-c.attack)*rectified
+		} else {
+			s.envelope *= c.fall
+		}
+
+		return s.envelope
+	case BallisticsCrest:
+		s.envelope = max(rectified, s.envelope*c.fall)
+		s.meanSquare = c.rms*s.meanSquare + (1-c.rms)*sample*sample
+
+		if s.meanSquare == 0 {
+			return 0
+		}
+
+		return s.envelope / math.Sqrt(s.meanSquare)
+	default:
+		return 0
+	}
+}
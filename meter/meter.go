@@ -0,0 +1,175 @@
+// Package meter provides level meters with standard ballistics, for driving level displays from a running pipeline.
+package meter
+
+import (
+	"math"
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/samborkent/gsp"
+)
+
+// Meter is a pass-through processor which measures the level of each channel.
+// Readings are published after every processed buffer, and are safe to read from another goroutine.
+type Meter[F gsp.Frame[T], T gsp.Float] struct {
+	ballistics   Ballistics
+	coefficients coefficients
+	channels     []channel
+	readings     []float64
+
+	levels atomic.Pointer[[]atomic.Uint64] // Published readings in dB per channel, as float64 bits.
+
+	frameChannels int // Number of channels of mono and stereo frames, zero for multi-channel frames.
+}
+
+// New returns a [Meter] with the given ballistics, for the sample rate and number of channels of format.
+// The channel count of multi-channel frames is taken from the first frame if the format does not specify it.
+func New[F gsp.Frame[T], T gsp.Float](ballistics Ballistics, format gsp.Format) *Meter[F, T] {
+	meter := &Meter[F, T]{ballistics: ballistics}
+
+	switch any(*new(F)).(type) {
+	case T:
+		meter.frameChannels = 1
+	case [2]T, gsp.Stereo[T]:
+		meter.frameChannels = 2
+	case []T, gsp.MultiChannel[T]:
+	default:
+		panic("gsp: meter.New: unknown audio frame type")
+	}
+
+	if format.Channels == 0 {
+		format.Channels = meter.frameChannels
+	}
+
+	meter.Init(format)
+
+	return meter
+}
+
+// Init sets the sample rate and number of channels, and resets the readings.
+// It must not be called concurrently with processing.
+func (m *Meter[F, T]) Init(format gsp.Format) {
+	if format.SampleRate > 0 {
+		m.coefficients = newCoefficients(m.ballistics, format.SampleRate)
+	}
+
+	channels := len(m.channels)
+	if format.Channels > 0 {
+		channels = format.Channels
+	}
+
+	m.allocate(channels)
+}
+
+// Reset resets the readings.
+// It must not be called concurrently with processing.
+func (m *Meter[F, T]) Reset() {
+	m.allocate(len(m.channels))
+}
+
+// Ballistics returns the ballistics of the meter.
+func (m *Meter[F, T]) Ballistics() Ballistics {
+	return m.ballistics
+}
+
+// Channels returns the number of published channel readings.
+func (m *Meter[F, T]) Channels() int {
+	levels := m.levels.Load()
+	if levels == nil {
+		return 0
+	}
+
+	return len(*levels)
+}
+
+// Level returns the reading in dB of a channel, or negative infinity if the channel does not exist.
+// Levels are relative to full scale, the crest factor is the ratio of peak to RMS level.
+func (m *Meter[F, T]) Level(channel int) float64 {
+	levels := m.levels.Load()
+	if levels == nil || channel < 0 || channel >= len(*levels) {
+		return math.Inf(-1)
+	}
+
+	return math.Float64frombits((*levels)[channel].Load())
+}
+
+// Levels appends the readings in dB of all channels to dst, and returns the extended slice.
+func (m *Meter[F, T]) Levels(dst []float64) []float64 {
+	levels := m.levels.Load()
+	if levels == nil {
+		return dst
+	}
+
+	for i := range *levels {
+		dst = append(dst, math.Float64frombits((*levels)[i].Load()))
+	}
+
+	return dst
+}
+
+// Process measures sample and returns it unchanged.
+func (m *Meter[F, T]) Process(sample F) F {
+	m.measure(m.samples(&sample))
+	m.publish()
+
+	return sample
+}
+
+// ProcessBuffer measures input and copies it to output.
+func (m *Meter[F, T]) ProcessBuffer(output, input []F) {
+	size := min(len(output), len(input))
+	if size == 0 {
+		return
+	}
+
+	for i := range size {
+		m.measure(m.samples(&input[i]))
+	}
+
+	if unsafe.SliceData(output) != unsafe.SliceData(input) {
+		copy(output, input[:size])
+	}
+
+	m.publish()
+}
+
+func (m *Meter[F, T]) measure(samples []T) {
+	if len(samples) != len(m.channels) {
+		m.allocate(len(samples))
+	}
+
+	for c, sample := range samples {
+		m.readings[c] = m.channels[c].update(m.ballistics, &m.coefficients, float64(sample))
+	}
+}
+
+// publish stores the current readings in dB for readers.
+func (m *Meter[F, T]) publish() {
+	levels := *m.levels.Load()
+
+	for c, reading := range m.readings {
+		levels[c].Store(math.Float64bits(20 * math.Log10(reading)))
+	}
+}
+
+// allocate resets the state for the number of channels, and publishes a new set of readings.
+func (m *Meter[F, T]) allocate(channels int) {
+	m.channels = make([]channel, channels)
+	m.readings = make([]float64, channels)
+
+	levels := make([]atomic.Uint64, channels)
+	for c := range levels {
+		levels[c].Store(math.Float64bits(math.Inf(-1)))
+	}
+
+	m.levels.Store(&levels)
+}
+
+// samples returns the samples of a frame as a slice aliasing the frame.
+func (m *Meter[F, T]) samples(frame *F) []T {
+	if m.frameChannels == 0 {
+		return *(*[]T)(unsafe.Pointer(frame))
+	}
+
+	return unsafe.Slice((*T)(unsafe.Pointer(frame)), m.frameChannels)
+}
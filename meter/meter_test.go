@@ -0,0 +1,220 @@
+package meter_test
+
+import (
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/samborkent/gsp"
+	"github.com/samborkent/gsp/meter"
+)
+
+func TestMeterSine(t *testing.T) {
+	t.Parallel()
+
+	sampleRate := 48000
+	amplitude := 0.5
+	peak := 20 * math.Log10(amplitude)
+
+	tests := []struct {
+		ballistics meter.Ballistics
+		want       float64
+		tolerance  float64
+	}{
+		{meter.BallisticsPeak, peak, 0.01},
+		{meter.BallisticsRMS, peak - 3.0103, 0.1},
+		{meter.BallisticsVU, peak - 3.0103, 0.1},
+		// Quasi-peak meters settle slightly below the peak of a continuous tone.
+		{meter.BallisticsPPMTypeI, peak, 0.5},
+		{meter.BallisticsPPMTypeII, peak, 0.5},
+		{meter.BallisticsCrest, 3.0103, 0.1},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.ballistics), func(t *testing.T) {
+			t.Parallel()
+
+			m := meter.New[gsp.Stereo[float64], float64](test.ballistics, gsp.Format{SampleRate: sampleRate})
+
+			input := make([]gsp.Stereo[float64], 2*sampleRate)
+			for i := range input {
+				sample := amplitude * math.Sin(2*math.Pi*1000*float64(i)/float64(sampleRate))
+				input[i] = gsp.ToStereo(sample, 0)
+			}
+
+			output := make([]gsp.Stereo[float64], len(input))
+			m.ProcessBuffer(output, input)
+
+			if output[100] != input[100] {
+				t.Errorf("wrong output: got '%v', want '%v'", output[100], input[100])
+			}
+
+			if m.Channels() != 2 {
+				t.Fatalf("wrong number of channels: got '%d', want '%d'", m.Channels(), 2)
+			}
+
+			if level := m.Level(gsp.L); math.Abs(level-test.want) > test.tolerance {
+				t.Errorf("wrong level: got '%v', want '%v'", level, test.want)
+			}
+
+			if test.ballistics != meter.BallisticsCrest {
+				if level := m.Level(gsp.R); !math.IsInf(level, -1) {
+					t.Errorf("wrong silent level: got '%v', want '%v'", level, math.Inf(-1))
+				}
+			}
+
+			m.Reset()
+
+			if level := m.Level(gsp.L); !math.IsInf(level, -1) {
+				t.Errorf("wrong level after reset: got '%v', want '%v'", level, math.Inf(-1))
+			}
+		})
+	}
+}
+
+func TestMeterFallBack(t *testing.T) {
+	t.Parallel()
+
+	sampleRate := 48000
+
+	tests := []struct {
+		ballistics meter.Ballistics
+		fall       float64 // dB
+		seconds    float64
+	}{
+		{meter.BallisticsPeak, 20, 1.7},
+		{meter.BallisticsPPMTypeI, 20, 1.5},
+		{meter.BallisticsPPMTypeII, 24, 2.8},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.ballistics), func(t *testing.T) {
+			t.Parallel()
+
+			m := meter.New[float64, float64](test.ballistics, gsp.Format{SampleRate: sampleRate})
+
+			input := make([]float64, sampleRate)
+			for i := range input {
+				input[i] = 1
+			}
+
+			m.ProcessBuffer(input, input)
+			start := m.Level(0)
+
+			silence := make([]float64, int(test.seconds*float64(sampleRate)))
+			m.ProcessBuffer(silence, silence)
+
+			if fall := start - m.Level(0); math.Abs(fall-test.fall) > 0.1 {
+				t.Errorf("wrong fall back: got '%v', want '%v'", fall, test.fall)
+			}
+		})
+	}
+}
+
+func TestMeterIntegrationTime(t *testing.T) {
+	t.Parallel()
+
+	sampleRate := 48000
+
+	tests := []struct {
+		ballistics meter.Ballistics
+		burst      int // Samples
+	}{
+		{meter.BallisticsPPMTypeI, 5 * sampleRate / 1000},
+		{meter.BallisticsPPMTypeII, 10 * sampleRate / 1000},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.ballistics), func(t *testing.T) {
+			t.Parallel()
+
+			m := meter.New[float32, float32](test.ballistics, gsp.Format{SampleRate: sampleRate})
+
+			burst := make([]float32, test.burst)
+			for i := range burst {
+				burst[i] = 1
+			}
+
+			m.ProcessBuffer(burst, burst)
+
+			if level := m.Level(0); math.Abs(level+2) > 0.05 {
+				t.Errorf("wrong burst level: got '%v', want '%v'", level, -2)
+			}
+		})
+	}
+}
+
+func TestMeterMultiChannel(t *testing.T) {
+	t.Parallel()
+
+	m := meter.New[gsp.MultiChannel[float64], float64](meter.BallisticsPeak, gsp.Format{SampleRate: 48000})
+
+	if m.Channels() != 0 {
+		t.Fatalf("wrong number of channels: got '%d', want '%d'", m.Channels(), 0)
+	}
+
+	frame := m.Process(gsp.ToMultiChannel(0.1, 0.5, 1.0))
+	if len(frame) != 3 {
+		t.Fatalf("wrong output frame: got '%v', want '%v'", frame, gsp.ToMultiChannel(0.1, 0.5, 1.0))
+	}
+
+	levels := m.Levels(nil)
+	want := []float64{-20, -6.0206, 0}
+
+	if len(levels) != len(want) {
+		t.Fatalf("wrong number of levels: got '%d', want '%d'", len(levels), len(want))
+	}
+
+	for c := range want {
+		if math.Abs(levels[c]-want[c]) > 1e-3 {
+			t.Errorf("wrong level of channel '%d': got '%v', want '%v'", c, levels[c], want[c])
+		}
+	}
+
+	if level := m.Level(3); !math.IsInf(level, -1) {
+		t.Errorf("wrong level of missing channel: got '%v', want '%v'", level, math.Inf(-1))
+	}
+}
+
+func TestMeterConcurrentRead(t *testing.T) {
+	t.Parallel()
+
+	m := meter.New[gsp.MultiChannel[float32], float32](meter.BallisticsRMS, gsp.Format{SampleRate: 48000, Channels: 2})
+
+	done := make(chan struct{})
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		var levels []float64
+
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				levels = m.Levels(levels[:0])
+			}
+		}
+	}()
+
+	buffer := make([]gsp.MultiChannel[float32], 256)
+	for i := range buffer {
+		buffer[i] = gsp.ToMultiChannel[float32](0.5, -0.5, 0.25)
+	}
+
+	for range 100 {
+		m.ProcessBuffer(buffer, buffer)
+	}
+
+	close(done)
+	wg.Wait()
+
+	if m.Channels() != 3 {
+		t.Errorf("wrong number of channels: got '%d', want '%d'", m.Channels(), 3)
+	}
+}
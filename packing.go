@@ -0,0 +1,99 @@
+package gsp
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// checkPacking panics if the sample type cannot be used with the packing.
+func checkPacking[T Type](packing Packing, caller string) {
+	if packing == PackingNone {
+		return
+	}
+
+	// Unknown packings panic.
+	_ = packing.byteSize()
+
+	switch SampleTypeOf[T]() {
+	case SampleTypeInt32, SampleTypeFloat32:
+	default:
+		panic("gsp: " + caller + ": 24-bit packing requires int32 or float32 samples")
+	}
+}
+
+// decode24 decodes packed 24-bit samples from src into dst and returns the number of samples decoded.
+func decode24[T Type](dst []T, src []byte, packing Packing, bigEndian bool) int {
+	byteSize := packing.byteSize()
+	length := min(len(dst), len(src)/byteSize)
+	float := isFloat[T]()
+
+	var order binary.ByteOrder = binary.LittleEndian
+	if bigEndian {
+		order = binary.BigEndian
+	}
+
+	for i := range length {
+		data := src[byteSize*i : byteSize*(i+1)]
+
+		var value int32
+
+		switch packing {
+		case Packing24:
+			if bigEndian {
+				value = int32(data[0])<<24 | int32(data[1])<<16 | int32(data[2])<<8
+			} else {
+				value = int32(data[2])<<24 | int32(data[1])<<16 | int32(data[0])<<8
+			}
+
+			value >>= 8
+		case Packing24In32Left:
+			value = int32(order.Uint32(data)) >> 8
+		case Packing24In32Right:
+			// The most significant byte is ignored, as not all writers sign-extend.
+			value = int32(order.Uint32(data)<<8) >> 8
+		}
+
+		if float {
+			dst[i] = T(max(float32(value)*invMaxInt24_32, -1))
+		} else {
+			dst[i] = T(value << 8)
+		}
+	}
+
+	return length
+}
+
+// append24 appends the samples of src as packed 24-bit samples to b.
+func append24[T Type](b []byte, src []T, packing Packing, bigEndian bool) []byte {
+	float := isFloat[T]()
+
+	var order binary.AppendByteOrder = binary.LittleEndian
+	if bigEndian {
+		order = binary.BigEndian
+	}
+
+	for _, sample := range src {
+		var value int32
+
+		if float {
+			value = int32(math.Round(float64(min(max(float32(sample), -1), 1) * maxInt24_32)))
+		} else {
+			value = int32(sample) >> 8
+		}
+
+		switch packing {
+		case Packing24:
+			if bigEndian {
+				b = append(b, byte(value>>16), byte(value>>8), byte(value))
+			} else {
+				b = append(b, byte(value), byte(value>>8), byte(value>>16))
+			}
+		case Packing24In32Left:
+			b = order.AppendUint32(b, uint32(value)<<8)
+		case Packing24In32Right:
+			b = order.AppendUint32(b, uint32(value))
+		}
+	}
+
+	return b
+}
@@ -2,85 +2,585 @@ package gsp
 
 import (
 	"context"
-	"runtime"
+	"errors"
+	"io"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"unsafe"
 )
 
+var ErrPipelineClosed = errors.New("gsp: Pipeline: closed")
+
+const (
+	pipelineFrames = 4096 // Capacity in frames of the rings of a pipeline, larger writes are split.
+	pipelineBlocks = 16   // Capacity in buffers of the rings of a pipeline.
+)
+
+// Pipeline processes buffers of frames with a chain of processors on a separate goroutine.
+// The goroutine stops when the context is cancelled, a processor fails, or the pipeline is closed.
+// Buffers are transported over lock-free rings, and every written buffer is processed as a whole.
+//
+// A clocked pipeline processes a block on every tick of its clock, as an audio device would.
+// If no input was written in time, it processes a block of silence instead, so processors receive a continuous signal.
 type Pipeline[F Frame[T], T Type] struct {
+	ctx        context.Context // Cancelled by the context of the pipeline, or with [ErrPipelineClosed] by Close.
+	cancel     context.CancelCauseFunc
 	processors []BufferProcessor[F, T]
+	scratch    []bool               // Whether each processor writes to the scratch buffer instead of the output buffer.
+	planar     []PlanarProcessor[T] // Processors which are part of a run of consecutive planar processors, nil otherwise.
 
-	input, output chan []F
+	format Format
+
+	input, output *blockQueue[F, T]
+	pending       [][]F // Processed buffers which did not fit the output after Close, only read after done is closed.
+	remaining     int   // Frames of the current output buffer which are not read yet, only used by the reader.
+
+	loop     chan []F // Processed buffers forwarded by [Pipeline.Loop].
+	loopOnce sync.Once
 
 	pool *FramePool[F, T]
 
-	initialized bool
+	// Scratch buffers of runs of planar processors, only used by the processing goroutine.
+	planarInput, planarOutput Planar[T]
+
+	mu        sync.RWMutex  // Held for reading by writers, and for writing when closing the input.
+	stop      chan struct{} // Closed by Close to abort blocked writers.
+	done      chan struct{} // Closed when the processing goroutine has stopped.
+	closeOnce sync.Once
+	err       error // Reason the processing goroutine stopped, only read after done is closed.
+
+	clock               ClockConfig
+	silence             []F // Input of ticks without written input.
+	underruns, overruns atomic.Int64
 }
 
-func NewPipeline[F Frame[T], T Type](processors ...BufferProcessor[F, T]) *Pipeline[F, T] {
-	if len(processors) == 0 {
-		return &Pipeline[F, T]{}
-	}
+// NewPipeline starts a [Pipeline] which processes buffers with the processors in order.
+// Processors implementing [Initializer] are initialized with the stream format before processing starts, unless the format is the zero value.
+// Without processors, buffers are passed through unchanged.
+// The pipeline must be closed, or its context cancelled, to stop its goroutine.
+func NewPipeline[F Frame[T], T Type](ctx context.Context, format Format, processors ...BufferProcessor[F, T]) *Pipeline[F, T] {
+	ctx, cancel := context.WithCancelCause(ctx)
 
 	pipeline := &Pipeline[F, T]{
-		processors:  processors,
-		input:       make(chan []F, 1),
-		output:      make(chan []F, 1),
-		pool:        NewFramePool[F, T](1024),
-		initialized: true,
+		ctx:        ctx,
+		cancel:     cancel,
+		processors: processors,
+		scratch:    chainTargets(processors),
+		planar:     planarRuns(processors),
+		format:     format,
+		input:      newBlockQueue[F, T](pipelineFrames, pipelineBlocks),
+		output:     newBlockQueue[F, T](pipelineFrames, pipelineBlocks),
+		pool:       NewFramePool[F, T](1024),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
+	for _, processor := range processors {
+		initialize(processor, format)
+	}
 
-	runtime.AddCleanup(pipeline, func(_ int) {
-		cancel()
-		close(pipeline.input)
-		close(pipeline.output)
-	}, 0)
+	go pipeline.run()
 
-	go pipeline.run(ctx)
+	return pipeline
+}
+
+// NewClockedPipeline starts a [Pipeline] which processes a block on every tick of the configured clock.
+// The processors are initialized with the configured format.
+// Written buffers should hold a single block, and processed buffers must be read within a tick to prevent overruns.
+func NewClockedPipeline[F Frame[T], T Type](ctx context.Context, config ClockConfig, processors ...BufferProcessor[F, T]) *Pipeline[F, T] {
+	if config.Format.SampleRate <= 0 || config.BlockSize <= 0 {
+		panic("gsp: NewClockedPipeline: sample rate and block size must be positive")
+	}
+
+	if config.Clock == nil {
+		config.Clock = SystemClock{}
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	pipeline := &Pipeline[F, T]{
+		ctx:        ctx,
+		cancel:     cancel,
+		processors: processors,
+		scratch:    chainTargets(processors),
+		planar:     planarRuns(processors),
+		format:     config.Format,
+		input:      newBlockQueue[F, T](config.BlockSize, 1),
+		output:     newBlockQueue[F, T](config.BlockSize, 1),
+		pool:       NewFramePool[F, T](config.BlockSize),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+		clock:      config,
+		silence:    silence[F, T](config.BlockSize, config.Format.Channels),
+	}
+
+	for _, processor := range processors {
+		initialize(processor, config.Format)
+	}
+
+	go pipeline.runClocked()
 
 	return pipeline
 }
 
+// Format returns the format the pipeline was created with.
+func (p *Pipeline[F, T]) Format() Format {
+	return p.format
+}
+
+// Loop returns a channel of processed buffers, which is closed when the pipeline stops.
+// The first call starts forwarding processed buffers to the channel, after which [Pipeline.Read] must not be used.
+// Buffers received from the channel are owned by the receiver.
 func (p *Pipeline[F, T]) Loop() <-chan []F {
-	return p.output
+	p.loopOnce.Do(func() {
+		p.loop = make(chan []F)
+
+		go func() {
+			defer close(p.loop)
+
+			for {
+				size, err := p.output.next(nil)
+				if err != nil {
+					break
+				}
+
+				buffer := make([]F, size)
+				p.output.take(buffer)
+
+				p.loop <- buffer
+			}
+
+			for _, buffer := range p.pending {
+				p.loop <- buffer
+			}
+
+			p.pending = nil
+		}()
+	})
+
+	return p.loop
 }
 
+// Read copies the next processed frames into output, blocking until they are available.
+// A single read returns frames of at most one processed buffer, frames which do not fit into output are returned by the next read.
+// Once the pipeline has stopped and all processed buffers are read, it returns the processing error, or [io.EOF].
 func (p *Pipeline[F, T]) Read(output []F) (int, error) {
-	return copy(output, <-p.output), nil
+	if p.remaining == 0 {
+		size, err := p.output.next(nil)
+		if err != nil {
+			return p.readPending(output)
+		}
+
+		p.remaining = size
+	}
+
+	n := min(p.remaining, len(output))
+	p.output.take(output[:n])
+	p.remaining -= n
+
+	return n, nil
 }
 
+// readPending reads the buffers which were kept after [Pipeline.Close], once the output is drained.
+func (p *Pipeline[F, T]) readPending(output []F) (int, error) {
+	if len(p.pending) > 0 {
+		n := copyFrames[F, T](output, p.pending[0])
+
+		p.pending[0] = p.pending[0][n:]
+		if len(p.pending[0]) == 0 {
+			p.pending = p.pending[1:]
+		}
+
+		return n, nil
+	}
+
+	if err := p.Err(); err != nil {
+		return 0, err
+	}
+
+	return 0, io.EOF
+}
+
+// Write copies input into the pipeline, blocking until the pipeline accepts it.
+// Input larger than the capacity of the pipeline is split into multiple buffers.
+// It returns [ErrPipelineClosed] after [Pipeline.Close], or the processing error if the pipeline has stopped.
 func (p *Pipeline[F, T]) Write(input []F) (int, error) {
-	p.input <- input
-	return len(input), nil
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	select {
+	case <-p.stop:
+		return 0, ErrPipelineClosed
+	case <-p.done:
+		return 0, p.writeError()
+	default:
+	}
+
+	written := 0
+
+	for {
+		size := min(len(input)-written, p.input.capacity())
+
+		err := p.input.put(input[written:written+size], true, p.stop)
+		if err != nil {
+			select {
+			case <-p.stop:
+				return written, ErrPipelineClosed
+			default:
+				// The processing goroutine has stopped.
+				return written, p.writeError()
+			}
+		}
+
+		written += size
+		if written == len(input) {
+			return written, nil
+		}
+	}
+}
+
+// Close stops accepting writes, and waits until all written buffers are processed.
+// Processed buffers which do not fit the output are kept for [Pipeline.Read], so Close returns even if the output is not read.
+// It returns the processing error, if any, and is safe to call multiple times.
+func (p *Pipeline[F, T]) Close() error {
+	p.closeOnce.Do(func() {
+		close(p.stop)
+
+		// Wait for blocked writers to return, after which the input can be closed safely.
+		p.mu.Lock()
+		p.input.close()
+		p.mu.Unlock()
+
+		// Stop waiting for the reader.
+		p.cancel(ErrPipelineClosed)
+	})
+
+	<-p.done
+
+	return p.err
+}
+
+// Underruns returns the number of ticks of a clocked pipeline for which no input was written in time, and silence was processed instead.
+func (p *Pipeline[F, T]) Underruns() int64 {
+	return p.underruns.Load()
+}
+
+// Overruns returns the number of processed buffers of a clocked pipeline which were dropped, because the previous buffer was not read in time.
+func (p *Pipeline[F, T]) Overruns() int64 {
+	return p.overruns.Load()
+}
+
+// Err returns the reason the pipeline stopped, which is nil while it is running or after a clean [Pipeline.Close].
+func (p *Pipeline[F, T]) Err() error {
+	select {
+	case <-p.done:
+		return p.err
+	default:
+		return nil
+	}
+}
+
+// closing reports whether the internal context was cancelled by [Pipeline.Close].
+func (p *Pipeline[F, T]) closing() bool {
+	return errors.Is(context.Cause(p.ctx), ErrPipelineClosed)
+}
+
+// writeError returns the error of a write to a stopped pipeline.
+func (p *Pipeline[F, T]) writeError() error {
+	if p.err != nil {
+		return p.err
+	}
+
+	return ErrPipelineClosed
+}
+
+func (p *Pipeline[F, T]) run() {
+	// Readers may only see the closed output once the error is set, and writers once done is closed.
+	defer p.output.close()
+	defer p.input.close()
+	defer close(p.done)
+	defer p.cancel(nil)
+
+	for {
+		size, err := p.input.next(p.ctx.Done())
+		if errors.Is(err, errRingAborted) && p.closing() {
+			// The input is closed as well, so this no longer waits.
+			size, err = p.input.next(nil)
+		}
+
+		if errors.Is(err, io.EOF) {
+			// Closed and drained.
+			return
+		} else if err != nil {
+			p.err = p.ctx.Err()
+			return
+		}
+
+		input := p.buffer(size)
+		p.input.take(input)
+
+		output := p.buffer(size)
+
+		err = p.process(output, input)
+		p.pool.Put(&input)
+
+		if err != nil {
+			p.pool.Put(&output)
+			p.err = err
+
+			return
+		}
+
+		if len(p.pending) == 0 {
+			err = p.output.put(output, true, p.ctx.Done())
+			if err == nil {
+				p.pool.Put(&output)
+				continue
+			}
+
+			if !p.closing() {
+				p.pool.Put(&output)
+				p.err = p.ctx.Err()
+
+				return
+			}
+		}
+
+		// After Close, buffers which do not fit the output are kept in order, such that Close does not wait for the reader.
+		p.pending = append(p.pending, output)
+	}
 }
 
-func (p *Pipeline[F, T]) run(ctx context.Context) {
-	output := []F{}
+func (p *Pipeline[F, T]) runClocked() {
+	defer p.output.close()
+	defer p.input.close()
+	defer close(p.done)
+
+	defer p.cancel(nil)
+
+	ticks, stop := p.clock.Clock.Ticks(p.clock.interval())
+	defer stop()
 
 	for {
 		select {
-		case <-ctx.Done():
+		case <-p.ctx.Done():
+			if !p.closing() {
+				p.err = p.ctx.Err()
+				return
+			}
+			// After Close, the remaining input is processed without waiting for ticks.
+		case <-ticks:
+		}
+
+		var input []F
+
+		switch {
+		case p.input.ready():
+			size, _ := p.input.next(nil)
+			input = p.buffer(size)
+			p.input.take(input)
+		case p.input.drained():
+			return
+		default:
+			p.underruns.Add(1)
+			input = p.silence
+		}
+
+		output := p.buffer(len(input))
+
+		err := p.process(output, input)
+		if len(input) > 0 && &input[0] != &p.silence[0] {
+			p.pool.Put(&input)
+		}
+
+		if err != nil {
+			p.pool.Put(&output)
+			p.err = err
+
 			return
-		case input := <-p.input:
-			bufPtr := p.pool.Get()
-			output = *bufPtr
+		}
 
-			if len(input) > len(output) {
-				output = make([]F, len(input))
+		if p.closing() {
+			// After Close, buffers which do not fit the output are kept in order, as in run, instead of counted as overruns.
+			if len(p.pending) == 0 && p.output.put(output, true, p.ctx.Done()) == nil {
+				p.pool.Put(&output)
+			} else {
+				p.pending = append(p.pending, output)
 			}
 
-			p.process(output, input)
-			p.output <- output[:len(input)]
+			continue
+		}
 
-			clear(output)
-			bufPtr = &output
-			p.pool.Put(bufPtr)
+		// The audio goroutine must never block on a slow reader.
+		err = p.output.put(output, false, nil)
+		if err != nil {
+			p.overruns.Add(1)
 		}
+
+		p.pool.Put(&output)
+	}
+}
+
+// buffer returns a buffer of size frames from the pool.
+func (p *Pipeline[F, T]) buffer(size int) []F {
+	var buffer []F
+
+	if bufPtr := p.pool.Get(); bufPtr != nil {
+		buffer = *bufPtr
+	}
+
+	if cap(buffer) < size {
+		buffer = make([]F, size)
 	}
+
+	return buffer[:size]
 }
 
-func (p *Pipeline[F, T]) process(output, input []F) {
-	for _, processor := range p.processors {
-		processor.ProcessBuffer(output, input)
+// process runs the processors in series, every processor reads the output of its predecessor.
+// The buffers alternate between the output and a scratch buffer, such that the last processor writes to the output.
+func (p *Pipeline[F, T]) process(output, input []F) error {
+	if len(p.processors) == 0 {
+		copy(output, input)
+		return nil
 	}
+
+	var scratch []F
+
+	if slices.Contains(p.scratch, true) {
+		scratch = p.buffer(len(input))
+		defer p.pool.Put(&scratch)
+	}
+
+	source := input
+
+	for i := 0; i < len(p.processors); i++ {
+		// A run of planar processors writes to the buffer of its last processor.
+		end := i
+		for end < len(p.planar) && p.planar[end] != nil {
+			end++
+		}
+
+		if end > i {
+			destination := output
+			if p.scratch[end-1] {
+				destination = scratch
+			}
+
+			if p.processPlanar(p.planar[i:end], destination, source) {
+				source = destination
+				i = end - 1
+
+				continue
+			}
+		}
+
+		destination := output
+		if p.scratch[i] {
+			destination = scratch
+		}
+
+		err := p.processBuffer(p.processors[i], destination, source)
+		if err != nil {
+			return err
+		}
+
+		source = destination
+	}
+
+	return nil
+}
+
+func (p *Pipeline[F, T]) processBuffer(processor BufferProcessor[F, T], output, input []F) error {
+	if fallible, ok := processor.(FallibleBufferProcessor[F, T]); ok {
+		return fallible.TryProcessBuffer(output, input)
+	}
+
+	processor.ProcessBuffer(output, input)
+
+	return nil
+}
+
+// chainTargets returns for every processor whether it writes to the scratch buffer instead of the output buffer.
+// Starting from the last processor, which writes to the output, a processor only writes to the buffer its successor writes to if the successor processes in place.
+func chainTargets[F Frame[T], T Type](processors []BufferProcessor[F, T]) []bool {
+	targets := make([]bool, len(processors))
+
+	for i := len(processors) - 2; i >= 0; i-- {
+		if inPlace[F, T](processors[i+1]) {
+			targets[i] = targets[i+1]
+		} else {
+			targets[i] = !targets[i+1]
+		}
+	}
+
+	return targets
+}
+
+// inPlace reports whether the processor accepts an output buffer which aliases its input buffer.
+func inPlace[F Frame[T], T Type](processor BufferProcessor[F, T]) bool {
+	inPlaceProcessor, ok := processor.(InPlaceProcessor)
+	return ok && inPlaceProcessor.InPlace()
+}
+
+// planarRuns returns the processors which implement [PlanarProcessor] and have a neighbour which does too.
+// Fallible processors are always called with TryProcessBuffer.
+func planarRuns[F Frame[T], T Type](processors []BufferProcessor[F, T]) []PlanarProcessor[T] {
+	planar := make([]PlanarProcessor[T], len(processors))
+
+	for i, processor := range processors {
+		if _, fallible := processor.(FallibleBufferProcessor[F, T]); !fallible {
+			planar[i], _ = processor.(PlanarProcessor[T])
+		}
+	}
+
+	runs := make([]PlanarProcessor[T], len(processors))
+
+	for i := range planar {
+		if planar[i] != nil && (i > 0 && planar[i-1] != nil || i < len(planar)-1 && planar[i+1] != nil) {
+			runs[i] = planar[i]
+		}
+	}
+
+	return runs
+}
+
+// processPlanar deinterleaves input, processes it with a run of planar processors and interleaves the result into output.
+// It returns false if the channel count of the input is unknown or inconsistent.
+func (p *Pipeline[F, T]) processPlanar(processors []PlanarProcessor[T], output, input []F) bool {
+	if len(input) == 0 {
+		return true
+	}
+
+	channels := frameChannels[F, T]()
+	if channels == 0 {
+		channels = len(*(*[]T)(unsafe.Pointer(&input[0])))
+	}
+
+	if channels == 0 {
+		return false
+	}
+
+	if len(p.planarInput) != channels {
+		p.planarInput = NewPlanar[T](channels, len(input))
+		p.planarOutput = NewPlanar[T](channels, len(input))
+	}
+
+	p.planarInput = p.planarInput.Resize(len(input))
+	p.planarOutput = p.planarOutput.Resize(len(input))
+
+	_, err := Deinterleave(p.planarInput, input)
+	if err != nil {
+		return false
+	}
+
+	// Every processor reads the output of its predecessor.
+	for _, processor := range processors {
+		processor.ProcessPlanar(p.planarOutput, p.planarInput)
+		p.planarInput, p.planarOutput = p.planarOutput, p.planarInput
+	}
+
+	_, _ = Interleave(output, p.planarInput)
+
+	return true
 }
@@ -0,0 +1,748 @@
+package gsp_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/samborkent/gsp"
+	"github.com/samborkent/gsp/processors"
+)
+
+type scaleProcessor struct {
+	scale float64
+}
+
+func (p *scaleProcessor) ProcessBuffer(output, input []float64) {
+	for i := range min(len(output), len(input)) {
+		output[i] = p.scale * input[i]
+	}
+}
+
+var errProcessing = errors.New("processing failed")
+
+// failingProcessor fails on the buffer with the given index.
+type failingProcessor struct {
+	scaleProcessor
+	failAt, buffers int
+}
+
+func (p *failingProcessor) TryProcessBuffer(output, input []float64) error {
+	p.buffers++
+	if p.buffers > p.failAt {
+		return errProcessing
+	}
+
+	p.ProcessBuffer(output, input)
+
+	return nil
+}
+
+// readAll reads from the pipeline until it returns an error.
+func readAll(pipeline *gsp.Pipeline[float64, float64], size int) ([][]float64, error) {
+	var buffers [][]float64
+
+	for {
+		buffer := make([]float64, size)
+
+		n, err := pipeline.Read(buffer)
+		if err != nil {
+			return buffers, err
+		}
+
+		buffers = append(buffers, buffer[:n])
+	}
+}
+
+func TestPipelineClose(t *testing.T) {
+	t.Parallel()
+
+	N, size := 10, 32
+
+	pipeline := gsp.NewPipeline[float64, float64](context.Background(), gsp.Format{}, &scaleProcessor{scale: 2})
+
+	type result struct {
+		buffers [][]float64
+		err     error
+	}
+
+	results := make(chan result)
+
+	go func() {
+		buffers, err := readAll(pipeline, size)
+		results <- result{buffers, err}
+	}()
+
+	input := make([]float64, size)
+
+	for n := range N {
+		// The input is reused, so the pipeline must copy it.
+		for i := range input {
+			input[i] = float64(n*size + i)
+		}
+
+		_, err := pipeline.Write(input)
+		if err != nil {
+			t.Fatalf("writing buffer: error: %s", err.Error())
+		}
+	}
+
+	err := pipeline.Close()
+	if err != nil {
+		t.Fatalf("closing pipeline: error: %s", err.Error())
+	}
+
+	res := <-results
+	if res.err != io.EOF {
+		t.Errorf("wrong read error: got '%v', want '%v'", res.err, io.EOF)
+	}
+
+	if len(res.buffers) != N {
+		t.Fatalf("wrong number of buffers: got '%d', want '%d'", len(res.buffers), N)
+	}
+
+	for n, buffer := range res.buffers {
+		for i, sample := range buffer {
+			if want := 2 * float64(n*size+i); sample != want {
+				t.Fatalf("wrong sample at buffer '%d', index '%d': got '%g', want '%g'", n, i, sample, want)
+			}
+		}
+	}
+
+	_, err = pipeline.Write(input)
+	if !errors.Is(err, gsp.ErrPipelineClosed) {
+		t.Errorf("wrong write error after close: got '%v', want '%v'", err, gsp.ErrPipelineClosed)
+	}
+
+	err = pipeline.Close()
+	if err != nil {
+		t.Errorf("wrong error closing twice: got '%v', want '%v'", err, nil)
+	}
+}
+
+func TestPipelineCloseWithoutReader(t *testing.T) {
+	t.Parallel()
+
+	// More buffers than the output holds, which are only read after closing.
+	N, size := 24, 32
+
+	pipeline := gsp.NewPipeline[float64, float64](context.Background(), gsp.Format{}, &scaleProcessor{scale: 2})
+
+	input := make([]float64, size)
+
+	for n := range N {
+		for i := range input {
+			input[i] = float64(n*size + i)
+		}
+
+		_, err := pipeline.Write(input)
+		if err != nil {
+			t.Fatalf("writing buffer: error: %s", err.Error())
+		}
+	}
+
+	closed := make(chan error)
+
+	go func() {
+		closed <- pipeline.Close()
+	}()
+
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatalf("closing pipeline: error: %s", err.Error())
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("closing pipeline: timed out waiting for reader")
+	}
+
+	buffers, err := readAll(pipeline, size)
+	if err != io.EOF {
+		t.Errorf("wrong read error: got '%v', want '%v'", err, io.EOF)
+	}
+
+	if len(buffers) != N {
+		t.Fatalf("wrong number of buffers: got '%d', want '%d'", len(buffers), N)
+	}
+
+	for n, buffer := range buffers {
+		for i, sample := range buffer {
+			if want := 2 * float64(n*size+i); sample != want {
+				t.Fatalf("wrong sample at buffer '%d', index '%d': got '%g', want '%g'", n, i, sample, want)
+			}
+		}
+	}
+}
+
+func TestPipelineReadRemainder(t *testing.T) {
+	t.Parallel()
+
+	pipeline := gsp.NewPipeline[float64, float64](context.Background(), gsp.Format{})
+
+	input := make([]float64, 10)
+	for i := range input {
+		input[i] = float64(i)
+	}
+
+	_, _ = pipeline.Write(input)
+
+	err := pipeline.Close()
+	if err != nil {
+		t.Fatalf("closing pipeline: error: %s", err.Error())
+	}
+
+	// Frames which do not fit are returned by the following reads.
+	buffers, err := readAll(pipeline, 4)
+	if err != io.EOF {
+		t.Errorf("wrong read error: got '%v', want '%v'", err, io.EOF)
+	}
+
+	var output []float64
+
+	for n, buffer := range buffers {
+		if want := min(4, len(input)-4*n); len(buffer) != want {
+			t.Errorf("wrong size of read '%d': got '%d', want '%d'", n, len(buffer), want)
+		}
+
+		output = append(output, buffer...)
+	}
+
+	if !slices.Equal(output, input) {
+		t.Errorf("wrong frames: got '%v', want '%v'", output, input)
+	}
+}
+
+func TestPipelineError(t *testing.T) {
+	t.Parallel()
+
+	pipeline := gsp.NewPipeline[float64, float64](context.Background(), gsp.Format{}, &failingProcessor{scaleProcessor: scaleProcessor{scale: 1}, failAt: 2})
+
+	go func() {
+		input := make([]float64, 16)
+
+		for {
+			_, err := pipeline.Write(input)
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	buffers, err := readAll(pipeline, 16)
+	if !errors.Is(err, errProcessing) {
+		t.Errorf("wrong read error: got '%v', want '%v'", err, errProcessing)
+	}
+
+	if len(buffers) != 2 {
+		t.Errorf("wrong number of buffers: got '%d', want '%d'", len(buffers), 2)
+	}
+
+	_, err = pipeline.Write(make([]float64, 16))
+	if !errors.Is(err, errProcessing) {
+		t.Errorf("wrong write error: got '%v', want '%v'", err, errProcessing)
+	}
+
+	err = pipeline.Close()
+	if !errors.Is(err, errProcessing) {
+		t.Errorf("wrong close error: got '%v', want '%v'", err, errProcessing)
+	}
+}
+
+func TestPipelineContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	pipeline := gsp.NewPipeline[float64, float64](ctx, gsp.Format{})
+
+	_, err := pipeline.Write([]float64{1, 2, 3})
+	if err != nil {
+		t.Fatalf("writing buffer: error: %s", err.Error())
+	}
+
+	output := make([]float64, 3)
+
+	n, err := pipeline.Read(output)
+	if err != nil || n != 3 || output[2] != 3 {
+		t.Fatalf("wrong pass-through: got '%v' with error '%v', want '%v'", output[:n], err, []float64{1, 2, 3})
+	}
+
+	cancel()
+
+	_, err = pipeline.Read(output)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("wrong read error: got '%v', want '%v'", err, context.Canceled)
+	}
+
+	err = pipeline.Close()
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("wrong close error: got '%v', want '%v'", err, context.Canceled)
+	}
+}
+
+// formatProcessor records the format it is initialized with.
+type formatProcessor struct {
+	scaleProcessor
+	format gsp.Format
+}
+
+func (p *formatProcessor) Init(format gsp.Format) {
+	p.format = format
+}
+
+func TestPipelineFormat(t *testing.T) {
+	t.Parallel()
+
+	format := gsp.NewFormat[float64, float64](48000)
+	processor := &formatProcessor{scaleProcessor: scaleProcessor{scale: 1}}
+
+	pipeline := gsp.NewPipeline[float64, float64](context.Background(), format, processor)
+
+	// Processors are initialized before the processing goroutine starts.
+	if processor.format != format {
+		t.Errorf("wrong processor format: got '%+v', want '%+v'", processor.format, format)
+	}
+
+	if pipeline.Format() != format {
+		t.Errorf("wrong pipeline format: got '%+v', want '%+v'", pipeline.Format(), format)
+	}
+
+	_ = pipeline.Close()
+
+	// The zero format leaves processors as they are.
+	unknown := &formatProcessor{format: format}
+
+	_ = gsp.NewPipeline[float64, float64](context.Background(), gsp.Format{}, unknown).Close()
+
+	if unknown.format != format {
+		t.Errorf("wrong processor format: got '%+v', want '%+v'", unknown.format, format)
+	}
+}
+
+// manualClock ticks when the test sends on its channel.
+type manualClock struct {
+	ticks chan time.Time
+}
+
+func (c *manualClock) Ticks(time.Duration) (<-chan time.Time, func()) {
+	return c.ticks, func() {}
+}
+
+func TestPipelineClocked(t *testing.T) {
+	t.Parallel()
+
+	blockSize := 4
+	clock := &manualClock{ticks: make(chan time.Time)}
+
+	pipeline := gsp.NewClockedPipeline[gsp.Stereo[uint8], uint8](context.Background(), gsp.ClockConfig{
+		Format:    gsp.NewFormat[gsp.Stereo[uint8], uint8](48000),
+		BlockSize: blockSize,
+		Clock:     clock,
+	})
+
+	output := make([]gsp.Stereo[uint8], blockSize)
+
+	// Underrun, silence of unsigned samples is the mid-point.
+	clock.ticks <- time.Time{}
+
+	n, err := pipeline.Read(output)
+	if err != nil || n != blockSize {
+		t.Fatalf("reading silence: got '%d' frames with error '%v', want '%d'", n, err, blockSize)
+	}
+
+	for i := range output {
+		if output[i] != gsp.ZeroStereo[uint8]() {
+			t.Fatalf("wrong silence at index '%d': got '%v', want '%v'", i, output[i], gsp.ZeroStereo[uint8]())
+		}
+	}
+
+	if pipeline.Underruns() != 1 {
+		t.Errorf("wrong number of underruns: got '%d', want '%d'", pipeline.Underruns(), 1)
+	}
+
+	// Written input is processed on the next tick.
+	input := []gsp.Stereo[uint8]{{1, 2}, {3, 4}, {5, 6}, {7, 8}}
+
+	_, err = pipeline.Write(input)
+	if err != nil {
+		t.Fatalf("writing buffer: error: %s", err.Error())
+	}
+
+	clock.ticks <- time.Time{}
+
+	_, err = pipeline.Read(output)
+	if err != nil {
+		t.Fatalf("reading buffer: error: %s", err.Error())
+	}
+
+	for i := range output {
+		if output[i] != input[i] {
+			t.Errorf("wrong frame at index '%d': got '%v', want '%v'", i, output[i], input[i])
+		}
+	}
+
+	if pipeline.Underruns() != 1 {
+		t.Errorf("wrong number of underruns: got '%d', want '%d'", pipeline.Underruns(), 1)
+	}
+
+	// Without reading, the second block overruns. A tick is only received once the previous one is processed.
+	clock.ticks <- time.Time{}
+	clock.ticks <- time.Time{}
+	clock.ticks <- time.Time{}
+
+	if pipeline.Overruns() < 1 {
+		t.Errorf("wrong number of overruns: got '%d', want at least '%d'", pipeline.Overruns(), 1)
+	}
+
+	if pipeline.Underruns() < 3 {
+		t.Errorf("wrong number of underruns: got '%d', want at least '%d'", pipeline.Underruns(), 3)
+	}
+
+	closed := make(chan error)
+
+	go func() {
+		closed <- pipeline.Close()
+	}()
+
+	// The pipeline stops at the next tick after closing.
+	for {
+		select {
+		case clock.ticks <- time.Time{}:
+			continue
+		case err = <-closed:
+		}
+
+		break
+	}
+
+	if err != nil {
+		t.Errorf("closing pipeline: error: %s", err.Error())
+	}
+}
+
+func TestPipelineClockedClose(t *testing.T) {
+	t.Parallel()
+
+	blockSize := 4
+	clock := &manualClock{ticks: make(chan time.Time)}
+
+	pipeline := gsp.NewClockedPipeline[float64, float64](context.Background(), gsp.ClockConfig{
+		Format:    gsp.NewFormat[float64, float64](48000),
+		BlockSize: blockSize,
+		Clock:     clock,
+	}, &scaleProcessor{scale: 2})
+
+	input := []float64{1, 2, 3, 4, 5, 6, 7, 8}
+
+	// The first block fills the output, the second block is still queued when closing.
+	_, err := pipeline.Write(input[:blockSize])
+	if err != nil {
+		t.Fatalf("writing buffer: error: %s", err.Error())
+	}
+
+	clock.ticks <- time.Time{}
+
+	_, err = pipeline.Write(input[blockSize:])
+	if err != nil {
+		t.Fatalf("writing buffer: error: %s", err.Error())
+	}
+
+	err = pipeline.Close()
+	if err != nil {
+		t.Fatalf("closing pipeline: error: %s", err.Error())
+	}
+
+	buffers, err := readAll(pipeline, blockSize)
+	if err != io.EOF {
+		t.Errorf("wrong read error: got '%v', want '%v'", err, io.EOF)
+	}
+
+	var output []float64
+	for _, buffer := range buffers {
+		output = append(output, buffer...)
+	}
+
+	if len(output) != len(input) {
+		t.Fatalf("wrong number of frames: got '%d', want '%d'", len(output), len(input))
+	}
+
+	for i := range output {
+		if want := 2 * input[i]; output[i] != want {
+			t.Errorf("wrong frame at index '%d': got '%g', want '%g'", i, output[i], want)
+		}
+	}
+
+	if pipeline.Overruns() != 0 {
+		t.Errorf("wrong number of overruns: got '%d', want '%d'", pipeline.Overruns(), 0)
+	}
+}
+
+// aliasProcessor adds one to every frame, and records whether its output aliased its input.
+type aliasProcessor struct {
+	inPlace, aliased bool
+}
+
+func (p *aliasProcessor) ProcessBuffer(output, input []float64) {
+	if len(output) > 0 && len(input) > 0 && &output[0] == &input[0] {
+		p.aliased = true
+	}
+
+	for i := range min(len(output), len(input)) {
+		output[i] = input[i] + 1
+	}
+}
+
+func (p *aliasProcessor) InPlace() bool {
+	return p.inPlace
+}
+
+func TestPipelineChain(t *testing.T) {
+	t.Parallel()
+
+	t.Run("gain->compander", func(t *testing.T) {
+		t.Parallel()
+
+		testPipelineChain(t,
+			[]gsp.BufferProcessor[float64, float64]{
+				processors.NewGain[float64](-6.0),
+				processors.NewCompander[float64, float64](processors.CompanderAlgorithmMuLaw, false),
+			},
+			[]gsp.SampleProcessor[float64, float64]{
+				processors.NewGain[float64](-6.0),
+				processors.NewCompander[float64, float64](processors.CompanderAlgorithmMuLaw, false),
+			},
+		)
+	})
+
+	t.Run("compander->gain", func(t *testing.T) {
+		t.Parallel()
+
+		testPipelineChain(t,
+			[]gsp.BufferProcessor[float64, float64]{
+				processors.NewCompander[float64, float64](processors.CompanderAlgorithmALaw, false),
+				processors.NewGain[float64](-6.0),
+			},
+			[]gsp.SampleProcessor[float64, float64]{
+				processors.NewCompander[float64, float64](processors.CompanderAlgorithmALaw, false),
+				processors.NewGain[float64](-6.0),
+			},
+		)
+	})
+
+	t.Run("gain->scale->compander->scale", func(t *testing.T) {
+		t.Parallel()
+
+		testPipelineChain(t,
+			[]gsp.BufferProcessor[float64, float64]{
+				processors.NewGain[float64](6.0),
+				&scaleProcessor{scale: 0.5},
+				processors.NewCompander[float64, float64](processors.CompanderAlgorithmSine, false),
+				&scaleProcessor{scale: -1},
+			},
+			[]gsp.SampleProcessor[float64, float64]{
+				processors.NewGain[float64](6.0),
+				sampleFunc(func(x float64) float64 { return 0.5 * x }),
+				processors.NewCompander[float64, float64](processors.CompanderAlgorithmSine, false),
+				sampleFunc(func(x float64) float64 { return -x }),
+			},
+		)
+	})
+}
+
+type sampleFunc func(x float64) float64
+
+func (f sampleFunc) Process(x float64) float64 {
+	return f(x)
+}
+
+// testPipelineChain verifies that the pipeline output equals the processors applied in series to every sample.
+func testPipelineChain(t *testing.T, bufferProcessors []gsp.BufferProcessor[float64, float64], sampleProcessors []gsp.SampleProcessor[float64, float64]) {
+	t.Helper()
+
+	input := make([]float64, 64)
+	for i := range input {
+		input[i] = 2*float64(i)/float64(len(input)) - 1
+	}
+
+	pipeline := gsp.NewPipeline(context.Background(), gsp.Format{}, bufferProcessors...)
+	defer pipeline.Close()
+
+	_, err := pipeline.Write(input)
+	if err != nil {
+		t.Fatalf("writing buffer: error: %s", err.Error())
+	}
+
+	output := make([]float64, len(input))
+
+	_, err = pipeline.Read(output)
+	if err != nil {
+		t.Fatalf("reading buffer: error: %s", err.Error())
+	}
+
+	for i := range input {
+		want := input[i]
+		for _, processor := range sampleProcessors {
+			want = processor.Process(want)
+		}
+
+		if math.Abs(output[i]-want) > 1e-12 {
+			t.Errorf("wrong frame at index '%d': got '%g', want '%g'", i, output[i], want)
+		}
+	}
+}
+
+func TestPipelineInPlace(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		inPlace     []bool
+		wantAliased []bool
+	}{
+		{"copy", []bool{false, false, false}, []bool{false, false, false}},
+		{"in-place", []bool{true, true, true}, []bool{false, true, true}},
+		{"mixed", []bool{false, true, false, true}, []bool{false, true, false, true}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			aliasProcessors := make([]*aliasProcessor, len(test.inPlace))
+			bufferProcessors := make([]gsp.BufferProcessor[float64, float64], len(test.inPlace))
+
+			for i, inPlace := range test.inPlace {
+				aliasProcessors[i] = &aliasProcessor{inPlace: inPlace}
+				bufferProcessors[i] = aliasProcessors[i]
+			}
+
+			pipeline := gsp.NewPipeline(context.Background(), gsp.Format{}, bufferProcessors...)
+
+			input := make([]float64, 16)
+
+			_, _ = pipeline.Write(input)
+
+			output := make([]float64, len(input))
+			_, _ = pipeline.Read(output)
+
+			err := pipeline.Close()
+			if err != nil {
+				t.Fatalf("closing pipeline: error: %s", err.Error())
+			}
+
+			want := float64(len(test.inPlace))
+			for i := range output {
+				if output[i] != want {
+					t.Fatalf("wrong frame at index '%d': got '%g', want '%g'", i, output[i], want)
+				}
+			}
+
+			for i, processor := range aliasProcessors {
+				if processor.aliased != test.wantAliased[i] {
+					t.Errorf("wrong aliasing of processor '%d': got '%t', want '%t'", i, processor.aliased, test.wantAliased[i])
+				}
+			}
+		})
+	}
+}
+
+// stereoScaleProcessor scales interleaved stereo frames.
+type stereoScaleProcessor struct {
+	buffers int
+}
+
+func (p *stereoScaleProcessor) ProcessBuffer(output, input []gsp.Stereo[float64]) {
+	for i := range min(len(output), len(input)) {
+		output[i] = gsp.ToStereo(2*input[i].L(), 2*input[i].R())
+	}
+
+	p.buffers++
+}
+
+// planarScaleProcessor also scales non-interleaved buffers.
+type planarScaleProcessor struct {
+	stereoScaleProcessor
+	planarBuffers int
+}
+
+func (p *planarScaleProcessor) ProcessPlanar(output, input gsp.Planar[float64]) {
+	for c := range input {
+		for i := range input[c] {
+			output[c][i] = 2 * input[c][i]
+		}
+	}
+
+	p.planarBuffers++
+}
+
+func TestPipelinePlanar(t *testing.T) {
+	t.Parallel()
+
+	// Processors are either interleaved (I) or planar (P), only consecutive planar processors are processed planar.
+	tests := []struct {
+		name       string
+		processors string
+		wantPlanar []bool
+	}{
+		{"single", "P", []bool{false}},
+		{"run", "PP", []bool{true, true}},
+		{"between interleaved", "IPI", []bool{false, false, false}},
+		{"runs", "PPIPPP", []bool{true, true, false, true, true, true}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			scaleProcessors := make([]*stereoScaleProcessor, len(test.processors))
+			planarProcessors := make([]*planarScaleProcessor, len(test.processors))
+			bufferProcessors := make([]gsp.BufferProcessor[gsp.Stereo[float64], float64], len(test.processors))
+
+			for i, kind := range test.processors {
+				if kind == 'P' {
+					planarProcessors[i] = &planarScaleProcessor{}
+					scaleProcessors[i] = &planarProcessors[i].stereoScaleProcessor
+					bufferProcessors[i] = planarProcessors[i]
+				} else {
+					scaleProcessors[i] = &stereoScaleProcessor{}
+					bufferProcessors[i] = scaleProcessors[i]
+				}
+			}
+
+			pipeline := gsp.NewPipeline(context.Background(), gsp.Format{}, bufferProcessors...)
+
+			input := make([]gsp.Stereo[float64], 16)
+			for i := range input {
+				input[i] = gsp.ToStereo(float64(i), -float64(i))
+			}
+
+			_, _ = pipeline.Write(input)
+
+			output := make([]gsp.Stereo[float64], len(input))
+			_, _ = pipeline.Read(output)
+
+			err := pipeline.Close()
+			if err != nil {
+				t.Fatalf("closing pipeline: error: %s", err.Error())
+			}
+
+			scale := math.Pow(2, float64(len(test.processors)))
+			for i := range output {
+				if want := gsp.ToStereo(scale*input[i].L(), scale*input[i].R()); output[i] != want {
+					t.Fatalf("wrong frame at index '%d': got '%v', want '%v'", i, output[i], want)
+				}
+			}
+
+			for i, wantPlanar := range test.wantPlanar {
+				planar := planarProcessors[i] != nil && planarProcessors[i].planarBuffers == 1
+				if planar != wantPlanar || !planar && scaleProcessors[i].buffers != 1 {
+					t.Errorf("wrong processing of processor '%d': got planar '%t', want '%t'", i, planar, wantPlanar)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package gsp
+
+import "unsafe"
+
+// Planar is a buffer of non-interleaved samples, holding a contiguous slice of samples per channel.
+// Buffers created by [NewPlanar] share a single backing array between all channels.
+type Planar[T Type] [][]T
+
+// NewPlanar returns a [Planar] buffer with the given number of channels and frames.
+func NewPlanar[T Type](channels, frames int) Planar[T] {
+	planar := make(Planar[T], channels)
+	store := make([]T, channels*frames)
+
+	for c := range planar {
+		// Limit the capacity, so growing a channel never overwrites the next channel.
+		planar[c] = store[c*frames : (c+1)*frames : (c+1)*frames]
+	}
+
+	return planar
+}
+
+// Channels returns the number of channels.
+func (p Planar[T]) Channels() int {
+	return len(p)
+}
+
+// Frames returns the number of frames, which is the length of the shortest channel.
+func (p Planar[T]) Frames() int {
+	if len(p) == 0 {
+		return 0
+	}
+
+	frames := len(p[0])
+	for _, channel := range p[1:] {
+		frames = min(frames, len(channel))
+	}
+
+	return frames
+}
+
+// Resize sets the number of frames of every channel, and returns the resized buffer.
+// Channels are resliced in place if their capacity suffices,
+// otherwise a new backing array is allocated and the samples of every channel are copied into it.
+func (p Planar[T]) Resize(frames int) Planar[T] {
+	for _, channel := range p {
+		if cap(channel) < frames {
+			resized := NewPlanar[T](len(p), frames)
+			for c := range p {
+				copy(resized[c], p[c])
+			}
+
+			return resized
+		}
+	}
+
+	for c := range p {
+		p[c] = p[c][:frames]
+	}
+
+	return p
+}
+
+// Interleave copies the samples of src into the frames of dst, and returns the number of frames copied.
+// Multi-channel frames which do not match the channel count of src are reallocated.
+// Mono and stereo frames require src to have one or two channels respectively.
+func Interleave[F Frame[T], T Type](dst []F, src Planar[T]) (int, error) {
+	frames := min(len(dst), src.Frames())
+	if frames == 0 {
+		return 0, nil
+	}
+
+	switch any(*new(F)).(type) {
+	case T:
+		if len(src) != 1 {
+			return 0, ErrChannelMismatch
+		}
+
+		copy(unsafe.Slice((*T)(unsafe.Pointer(&dst[0])), frames), src[0])
+	case [2]T, Stereo[T]:
+		if len(src) != 2 {
+			return 0, ErrChannelMismatch
+		}
+
+		stereo := unsafe.Slice((*Stereo[T])(unsafe.Pointer(&dst[0])), frames)
+		left, right := src[L][:frames], src[R][:frames]
+
+		for i := range stereo {
+			stereo[i] = Stereo[T]{left[i], right[i]}
+		}
+	case []T, MultiChannel[T]:
+		multiChannel := unsafe.Slice((*[]T)(unsafe.Pointer(&dst[0])), frames)
+
+		for i := range multiChannel {
+			if len(multiChannel[i]) != len(src) {
+				multiChannel[i] = make([]T, len(src))
+			}
+
+			for c := range src {
+				multiChannel[i][c] = src[c][i]
+			}
+		}
+	default:
+		panic("gsp: Interleave: unknown audio frame type")
+	}
+
+	return frames, nil
+}
+
+// Deinterleave copies the frames of src into the channels of dst, and returns the number of frames copied.
+// The channel count of dst must match the channel count of the frames.
+func Deinterleave[F Frame[T], T Type](dst Planar[T], src []F) (int, error) {
+	frames := min(dst.Frames(), len(src))
+	if frames == 0 {
+		return 0, nil
+	}
+
+	switch any(*new(F)).(type) {
+	case T:
+		if len(dst) != 1 {
+			return 0, ErrChannelMismatch
+		}
+
+		copy(dst[0], unsafe.Slice((*T)(unsafe.Pointer(&src[0])), frames))
+	case [2]T, Stereo[T]:
+		if len(dst) != 2 {
+			return 0, ErrChannelMismatch
+		}
+
+		stereo := unsafe.Slice((*Stereo[T])(unsafe.Pointer(&src[0])), frames)
+		left, right := dst[L][:frames], dst[R][:frames]
+
+		for i := range stereo {
+			left[i], right[i] = stereo[i][L], stereo[i][R]
+		}
+	case []T, MultiChannel[T]:
+		multiChannel := unsafe.Slice((*[]T)(unsafe.Pointer(&src[0])), frames)
+
+		for i := range multiChannel {
+			if len(multiChannel[i]) != len(dst) {
+				return i, ErrChannelMismatch
+			}
+
+			for c := range dst {
+				dst[c][i] = multiChannel[i][c]
+			}
+		}
+	default:
+		panic("gsp: Deinterleave: unknown audio frame type")
+	}
+
+	return frames, nil
+}
@@ -0,0 +1,172 @@
+package gsp_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/samborkent/gsp"
+)
+
+func TestPlanar(t *testing.T) {
+	t.Parallel()
+
+	planar := gsp.NewPlanar[float32](3, 8)
+
+	if planar.Channels() != 3 || planar.Frames() != 8 {
+		t.Fatalf("wrong size: got '%d' channels and '%d' frames, want '%d' and '%d'", planar.Channels(), planar.Frames(), 3, 8)
+	}
+
+	planar[0][7] = 1
+
+	resized := planar.Resize(4)
+	if resized.Frames() != 4 || &resized[0][0] != &planar[0][0] {
+		t.Errorf("wrong resize: got '%d' frames, want '%d' frames in place", resized.Frames(), 4)
+	}
+
+	// Channels must not overlap after growing within their capacity.
+	resized = resized.Resize(8)
+	if resized[0][7] != 1 || resized[1][0] != 0 {
+		t.Errorf("wrong samples after resize: got '%v', want '%v'", resized[0][7], 1)
+	}
+
+	grown := resized.Resize(16)
+	if grown.Channels() != 3 || grown.Frames() != 16 {
+		t.Errorf("wrong size after growing: got '%d' channels and '%d' frames, want '%d' and '%d'", grown.Channels(), grown.Frames(), 3, 16)
+	}
+
+	// Samples are kept when the backing array is reallocated.
+	if grown[0][7] != 1 || grown[0][8] != 0 {
+		t.Errorf("wrong samples after growing: got '%v', want '%v'", grown[0][7:9], []float32{1, 0})
+	}
+}
+
+func TestInterleave(t *testing.T) {
+	t.Parallel()
+
+	N := 64
+
+	t.Run("mono", func(t *testing.T) {
+		t.Parallel()
+
+		input := make([]float64, N)
+		for i := range input {
+			input[i] = float64(i)
+		}
+
+		testInterleave[float64, float64](t, input, 1)
+	})
+
+	t.Run("stereo", func(t *testing.T) {
+		t.Parallel()
+
+		input := make([]gsp.Stereo[int16], N)
+		for i := range input {
+			input[i] = gsp.ToStereo(int16(i), int16(-i))
+		}
+
+		testInterleave[gsp.Stereo[int16], int16](t, input, 2)
+	})
+
+	t.Run("multi-channel", func(t *testing.T) {
+		t.Parallel()
+
+		input := make([]gsp.MultiChannel[float32], N)
+		for i := range input {
+			input[i] = gsp.ToMultiChannel(float32(i), float32(2*i), float32(3*i), float32(4*i))
+		}
+
+		testInterleave[gsp.MultiChannel[float32], float32](t, input, 4)
+
+		planar := gsp.NewPlanar[float32](3, N)
+
+		_, err := gsp.Deinterleave(planar, input)
+		if !errors.Is(err, gsp.ErrChannelMismatch) {
+			t.Errorf("wrong error: got '%v', want '%v'", err, gsp.ErrChannelMismatch)
+		}
+	})
+
+	t.Run("channel mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := gsp.Interleave(make([]gsp.Stereo[float32], N), gsp.NewPlanar[float32](1, N))
+		if !errors.Is(err, gsp.ErrChannelMismatch) {
+			t.Errorf("wrong error: got '%v', want '%v'", err, gsp.ErrChannelMismatch)
+		}
+	})
+}
+
+func testInterleave[F gsp.Frame[T], T gsp.Type](t *testing.T, input []F, channels int) {
+	t.Helper()
+
+	planar := gsp.NewPlanar[T](channels, len(input))
+
+	n, err := gsp.Deinterleave(planar, input)
+	if err != nil {
+		t.Fatalf("deinterleaving: error: %s", err.Error())
+	}
+
+	if n != len(input) {
+		t.Fatalf("wrong number of frames: got '%d', want '%d'", n, len(input))
+	}
+
+	output := make([]F, len(input))
+
+	n, err = gsp.Interleave(output, planar)
+	if err != nil {
+		t.Fatalf("interleaving: error: %s", err.Error())
+	}
+
+	if n != len(input) {
+		t.Fatalf("wrong number of frames: got '%d', want '%d'", n, len(input))
+	}
+
+	for i := range input {
+		if !equalFrames[F, T](output[i], input[i]) {
+			t.Errorf("frame mismatch at index '%d': got '%v', want '%v'", i, output[i], input[i])
+		}
+	}
+}
+
+func equalFrames[F gsp.Frame[T], T gsp.Type](a, b F) bool {
+	switch a := any(a).(type) {
+	case gsp.MultiChannel[T]:
+		b := any(b).(gsp.MultiChannel[T])
+		if len(a) != len(b) {
+			return false
+		}
+
+		for c := range a {
+			if a[c] != b[c] {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return any(a) == any(b)
+	}
+}
+
+func TestInterleaveAllocations(t *testing.T) {
+	N := 256
+
+	stereo := make([]gsp.Stereo[float32], N)
+	multiChannel := make([]gsp.MultiChannel[float32], N)
+	for i := range multiChannel {
+		multiChannel[i] = make(gsp.MultiChannel[float32], 6)
+	}
+
+	planarStereo := gsp.NewPlanar[float32](2, N)
+	planarMultiChannel := gsp.NewPlanar[float32](6, N)
+
+	allocs := testing.AllocsPerRun(10, func() {
+		_, _ = gsp.Deinterleave(planarStereo, stereo)
+		_, _ = gsp.Interleave(stereo, planarStereo)
+		_, _ = gsp.Deinterleave(planarMultiChannel, multiChannel)
+		_, _ = gsp.Interleave(multiChannel, planarMultiChannel)
+	})
+
+	if allocs != 0 {
+		t.Errorf("wrong number of allocations: got '%g', want '%d'", allocs, 0)
+	}
+}
@@ -9,6 +9,50 @@ type SampleProcessor[F Frame[T], T Type] interface {
 type BufferProcessor[F Frame[T], T Type] interface {
 	// This function gets called for each buffer of an input signal. It should contain the signal processing logic.
 	// For stateful algorithms such as filters, the processor is responsible for keeping state.
-	// The pipeline will assure that the processor is fed a continuous signal by sending a zero buffer in case no input was provided within the sample rate clock interval.
+	// A clocked [Pipeline] assures that the processor is fed a continuous signal by sending a buffer of silence in case no input was provided within the clock interval.
+	// Output and input are distinct buffers, unless the processor implements [InPlaceProcessor].
 	ProcessBuffer(outputBuffer, inputBuffer []F)
 }
+
+// FallibleBufferProcessor is implemented by buffer processors which can fail, for example when they depend on I/O.
+// Pipelines call TryProcessBuffer instead of ProcessBuffer for processors implementing it, and stop on the first error.
+type FallibleBufferProcessor[F Frame[T], T Type] interface {
+	BufferProcessor[F, T]
+	TryProcessBuffer(outputBuffer, inputBuffer []F) error
+}
+
+// InPlaceProcessor is implemented by buffer processors which can process in place.
+// Pipelines chain such processors without intermediate buffers, by passing the same buffer as output and input.
+type InPlaceProcessor interface {
+	// InPlace reports whether the output buffer may alias the input buffer.
+	InPlace() bool
+}
+
+// PlanarProcessor is implemented by buffer processors which can also process non-interleaved buffers.
+// Pipelines call ProcessPlanar instead of ProcessBuffer for consecutive processors implementing it,
+// such that a buffer is only deinterleaved before the first and interleaved after the last of them.
+// A single planar processor between interleaved processors is called with ProcessBuffer.
+type PlanarProcessor[T Type] interface {
+	// Output and input have the same number of channels and frames.
+	ProcessPlanar(outputBuffer, inputBuffer Planar[T])
+}
+
+// Initializer is implemented by processors which depend on the stream format, such as filters, delays, and envelopes.
+// Pipelines call Init on each of their processors with the [Format] they are created with, before processing starts.
+type Initializer interface {
+	Init(format Format)
+}
+
+// initialize calls Init on processor if it implements [Initializer] and the format is known.
+func initialize(processor any, format Format) {
+	if initializer, ok := processor.(Initializer); ok && format != (Format{}) {
+		initializer.Init(format)
+	}
+}
+
+// LatencyReporter is implemented by processors which delay their output, such as look-ahead limiters and block convolvers.
+// A [Graph] uses it to align branches of differing latency.
+type LatencyReporter interface {
+	// Latency returns the number of frames by which the output is delayed.
+	Latency() int
+}
@@ -0,0 +1,343 @@
+package processors
+
+import (
+	"math"
+	"unsafe"
+
+	"github.com/samborkent/gsp"
+)
+
+var _ gsp.InPlaceProcessor = &Biquad[float32, float32]{}
+
+type BiquadType string
+
+const (
+	BiquadLowPass   BiquadType = "low-pass"
+	BiquadHighPass  BiquadType = "high-pass"
+	BiquadBandPass  BiquadType = "band-pass"
+	BiquadNotch     BiquadType = "notch"
+	BiquadAllPass   BiquadType = "all-pass"
+	BiquadPeaking   BiquadType = "peaking"
+	BiquadLowShelf  BiquadType = "low-shelf"
+	BiquadHighShelf BiquadType = "high-shelf"
+)
+
+// biquadGlideTime is the time in seconds over which coefficient changes are interpolated.
+const biquadGlideTime = 0.005
+
+// Biquad is a second-order IIR filter designed with the formulas of the RBJ audio EQ cookbook.
+// Parameter changes are interpolated over a few milliseconds to prevent clicks.
+// The setters must not be called concurrently with processing.
+type Biquad[F gsp.Frame[T], T gsp.Float] struct {
+	filterType BiquadType
+	sampleRate int
+	frequency  T // Cutoff or center frequency in Hz.
+	q          T
+	gain       T // Gain in dB, only used by the peaking and shelving filters.
+
+	coefficients, target, step biquadCoefficients[T]
+	glideLength, glide         int
+
+	state []biquadState[T]
+	mode  Mode
+}
+
+// NewBiquad returns a filter of filterType with cutoff or center frequency in Hz.
+// It panics if the frequency is not between zero and half the sample rate, or if q is not positive.
+func NewBiquad[F gsp.Frame[T], T gsp.Float](filterType BiquadType, sampleRate int, frequency, q, gain T) *Biquad[F, T] {
+	validateBiquad("NewBiquad", sampleRate, frequency, q)
+
+	biquad := &Biquad[F, T]{
+		filterType: filterType,
+		sampleRate: sampleRate,
+		frequency:  frequency,
+		q:          q,
+		gain:       gain,
+	}
+
+	switch any(*new(F)).(type) {
+	case T:
+		biquad.mode = ModeMono
+		biquad.state = make([]biquadState[T], 1)
+	case [2]T, gsp.Stereo[T]:
+		biquad.mode = ModeStereo
+		biquad.state = make([]biquadState[T], 2)
+	case []T, gsp.MultiChannel[T]:
+		biquad.mode = ModeMultiChannel
+	default:
+		panic("gsp: NewBiquad: unknown audio frame type")
+	}
+
+	biquad.Init(gsp.Format{SampleRate: sampleRate})
+
+	return biquad
+}
+
+// Init sets the sample rate, redesigns the filter and clears the filter state.
+// It panics if the frequency is not below half the new sample rate.
+func (p *Biquad[F, T]) Init(format gsp.Format) {
+	if format.SampleRate > 0 {
+		validateBiquad("Biquad.Init", format.SampleRate, p.frequency, p.q)
+		p.sampleRate = format.SampleRate
+	}
+
+	if p.mode == ModeMultiChannel && format.Channels > 0 {
+		p.state = make([]biquadState[T], format.Channels)
+	}
+
+	p.glideLength = max(1, int(biquadGlideTime*float64(p.sampleRate)))
+	p.coefficients = designBiquad[T](p.filterType, p.sampleRate, p.frequency, p.q, p.gain)
+	p.target = p.coefficients
+	p.glide = 0
+
+	p.Reset()
+}
+
+// Reset clears the filter state.
+func (p *Biquad[F, T]) Reset() {
+	clear(p.state)
+}
+
+// SetType changes the filter design.
+func (p *Biquad[F, T]) SetType(filterType BiquadType) {
+	p.filterType = filterType
+	p.redesign()
+}
+
+// SetFrequency changes the cutoff or center frequency in Hz.
+// It panics if the frequency is not between zero and half the sample rate.
+func (p *Biquad[F, T]) SetFrequency(frequency T) {
+	validateBiquad("Biquad.SetFrequency", p.sampleRate, frequency, p.q)
+	p.frequency = frequency
+	p.redesign()
+}
+
+// SetQ changes the quality factor.
+// It panics if q is not positive.
+func (p *Biquad[F, T]) SetQ(q T) {
+	validateBiquad("Biquad.SetQ", p.sampleRate, p.frequency, q)
+	p.q = q
+	p.redesign()
+}
+
+// SetGain changes the gain in dB of the peaking and shelving filters.
+func (p *Biquad[F, T]) SetGain(gain T) {
+	p.gain = gain
+	p.redesign()
+}
+
+func (p *Biquad[F, T]) Process(sample F) F {
+	p.advance()
+
+	switch p.mode {
+	case ModeMono:
+		monoSample := *(*T)(unsafe.Pointer(&sample))
+		processedSample := p.state[0].process(&p.coefficients, monoSample)
+		return *(*F)(unsafe.Pointer(&processedSample))
+	case ModeStereo:
+		stereoSample := *(*gsp.Stereo[T])(unsafe.Pointer(&sample))
+		processedSamples := [2]T{
+			p.state[gsp.L].process(&p.coefficients, stereoSample.L()),
+			p.state[gsp.R].process(&p.coefficients, stereoSample.R()),
+		}
+		return *(*F)(unsafe.Pointer(&processedSamples))
+	case ModeMultiChannel:
+		// Filtered in place, like the other processors do with multi-channel frames.
+		multiChannelSample := *(*gsp.MultiChannel[T])(unsafe.Pointer(&sample))
+		p.grow(len(multiChannelSample))
+
+		for i := range multiChannelSample {
+			multiChannelSample[i] = p.state[i].process(&p.coefficients, multiChannelSample[i])
+		}
+
+		return sample
+	default:
+		return *new(F)
+	}
+}
+
+// InPlace reports that every frame is read before it is written, so output may alias input.
+func (p *Biquad[F, T]) InPlace() bool {
+	return true
+}
+
+func (p *Biquad[F, T]) ProcessBuffer(output, input []F) {
+	size := min(len(output), len(input))
+	if size == 0 {
+		return
+	}
+
+	switch p.mode {
+	case ModeMono:
+		samplePtr := (*T)(unsafe.Pointer(&input[0]))
+		monoSamples := unsafe.Slice(samplePtr, len(input))
+
+		for i := range size {
+			p.advance()
+			processedSample := p.state[0].process(&p.coefficients, monoSamples[i])
+			output[i] = *(*F)(unsafe.Pointer(&processedSample))
+		}
+	case ModeStereo:
+		samplePtr := (*gsp.Stereo[T])(unsafe.Pointer(&input[0]))
+		stereoSamples := unsafe.Slice(samplePtr, len(input))
+
+		for i := range size {
+			p.advance()
+			processedSamples := [2]T{
+				p.state[gsp.L].process(&p.coefficients, stereoSamples[i].L()),
+				p.state[gsp.R].process(&p.coefficients, stereoSamples[i].R()),
+			}
+			output[i] = *(*F)(unsafe.Pointer(&processedSamples))
+		}
+	case ModeMultiChannel:
+		samplePtr := (*gsp.MultiChannel[T])(unsafe.Pointer(&input[0]))
+		multiChannelSamples := unsafe.Slice(samplePtr, len(input))
+
+		outputPtr := (*gsp.MultiChannel[T])(unsafe.Pointer(&output[0]))
+		outputSamples := unsafe.Slice(outputPtr, len(output))
+
+		for i := range size {
+			p.advance()
+			p.grow(len(multiChannelSamples[i]))
+
+			if len(outputSamples[i]) != len(multiChannelSamples[i]) {
+				outputSamples[i] = make(gsp.MultiChannel[T], len(multiChannelSamples[i]))
+			}
+
+			for j := range multiChannelSamples[i] {
+				outputSamples[i][j] = p.state[j].process(&p.coefficients, multiChannelSamples[i][j])
+			}
+		}
+	}
+}
+
+// redesign computes the new target coefficients, towards which the current coefficients glide.
+func (p *Biquad[F, T]) redesign() {
+	p.target = designBiquad[T](p.filterType, p.sampleRate, p.frequency, p.q, p.gain)
+	p.glide = p.glideLength
+
+	invGlide := 1 / T(p.glideLength)
+	p.step = biquadCoefficients[T]{
+		b0: (p.target.b0 - p.coefficients.b0) * invGlide,
+		b1: (p.target.b1 - p.coefficients.b1) * invGlide,
+		b2: (p.target.b2 - p.coefficients.b2) * invGlide,
+		a1: (p.target.a1 - p.coefficients.a1) * invGlide,
+		a2: (p.target.a2 - p.coefficients.a2) * invGlide,
+	}
+}
+
+// advance moves the coefficients one sample further towards their target.
+func (p *Biquad[F, T]) advance() {
+	if p.glide == 0 {
+		return
+	}
+
+	p.glide--
+
+	if p.glide == 0 {
+		p.coefficients = p.target
+		return
+	}
+
+	p.coefficients.b0 += p.step.b0
+	p.coefficients.b1 += p.step.b1
+	p.coefficients.b2 += p.step.b2
+	p.coefficients.a1 += p.step.a1
+	p.coefficients.a2 += p.step.a2
+}
+
+// grow ensures there is filter state for the number of channels.
+func (p *Biquad[F, T]) grow(channels int) {
+	if len(p.state) < channels {
+		p.state = append(p.state, make([]biquadState[T], channels-len(p.state))...)
+	}
+}
+
+// validateBiquad panics if the filter parameters would result in invalid or unstable coefficients.
+func validateBiquad[T gsp.Float](function string, sampleRate int, frequency, q T) {
+	switch {
+	case sampleRate <= 0:
+		panic("gsp: " + function + ": sample rate must be positive")
+	case !(frequency > 0) || float64(frequency) >= float64(sampleRate)/2:
+		panic("gsp: " + function + ": frequency must be between zero and half the sample rate")
+	case !(q > 0):
+		panic("gsp: " + function + ": q must be positive")
+	}
+}
+
+// biquadCoefficients are normalized such that a0 equals one.
+type biquadCoefficients[T gsp.Float] struct {
+	b0, b1, b2, a1, a2 T
+}
+
+// biquadState is the state of a transposed direct form II biquad, which behaves well under coefficient changes.
+type biquadState[T gsp.Float] struct {
+	s1, s2 T
+}
+
+func (s *biquadState[T]) process(c *biquadCoefficients[T], x T) T {
+	y := c.b0*x + s.s1
+	s.s1 = c.b1*x - c.a1*y + s.s2
+	s.s2 = c.b2*x - c.a2*y
+
+	return y
+}
+
+func designBiquad[T gsp.Float](filterType BiquadType, sampleRate int, frequency, q, gain T) biquadCoefficients[T] {
+	w0 := 2 * math.Pi * float64(frequency) / float64(sampleRate)
+	sin, cos := math.Sincos(w0)
+	alpha := sin / (2 * float64(q))
+	a := math.Pow(10, float64(gain)/40)
+
+	var b0, b1, b2, a0, a1, a2 float64
+
+	switch filterType {
+	case BiquadLowPass:
+		b0, b1, b2 = (1-cos)/2, 1-cos, (1-cos)/2
+		a0, a1, a2 = 1+alpha, -2*cos, 1-alpha
+	case BiquadHighPass:
+		b0, b1, b2 = (1+cos)/2, -(1 + cos), (1+cos)/2
+		a0, a1, a2 = 1+alpha, -2*cos, 1-alpha
+	case BiquadBandPass:
+		// Constant 0 dB peak gain.
+		b0, b1, b2 = alpha, 0, -alpha
+		a0, a1, a2 = 1+alpha, -2*cos, 1-alpha
+	case BiquadNotch:
+		b0, b1, b2 = 1, -2*cos, 1
+		a0, a1, a2 = 1+alpha, -2*cos, 1-alpha
+	case BiquadAllPass:
+		b0, b1, b2 = 1-alpha, -2*cos, 1+alpha
+		a0, a1, a2 = 1+alpha, -2*cos, 1-alpha
+	case BiquadPeaking:
+		b0, b1, b2 = 1+alpha*a, -2*cos, 1-alpha*a
+		a0, a1, a2 = 1+alpha/a, -2*cos, 1-alpha/a
+	case BiquadLowShelf:
+		sqrtA := 2 * math.Sqrt(a) * alpha
+		b0 = a * ((a + 1) - (a-1)*cos + sqrtA)
+		b1 = 2 * a * ((a - 1) - (a+1)*cos)
+		b2 = a * ((a + 1) - (a-1)*cos - sqrtA)
+		a0 = (a + 1) + (a-1)*cos + sqrtA
+		a1 = -2 * ((a - 1) + (a+1)*cos)
+		a2 = (a + 1) + (a-1)*cos - sqrtA
+	case BiquadHighShelf:
+		sqrtA := 2 * math.Sqrt(a) * alpha
+		b0 = a * ((a + 1) + (a-1)*cos + sqrtA)
+		b1 = -2 * a * ((a - 1) + (a+1)*cos)
+		b2 = a * ((a + 1) + (a-1)*cos - sqrtA)
+		a0 = (a + 1) - (a-1)*cos + sqrtA
+		a1 = 2 * ((a - 1) - (a+1)*cos)
+		a2 = (a + 1) - (a-1)*cos - sqrtA
+	default:
+		panic("gsp: processors: Biquad: filter type not implemented")
+	}
+
+	invA0 := 1 / a0
+
+	return biquadCoefficients[T]{
+		b0: T(b0 * invA0),
+		b1: T(b1 * invA0),
+		b2: T(b2 * invA0),
+		a1: T(a1 * invA0),
+		a2: T(a2 * invA0),
+	}
+}
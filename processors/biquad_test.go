@@ -0,0 +1,263 @@
+package processors_test
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"github.com/samborkent/gsp"
+	"github.com/samborkent/gsp/processors"
+)
+
+const (
+	biquadSampleRate = 48000
+	biquadFrequency  = 1000
+	biquadQ          = math.Sqrt2 / 2
+	biquadGain       = 6
+)
+
+// magnitude returns the magnitude of the frequency response of impulse response h at frequency in Hz.
+func magnitude(h []float64, frequency float64, sampleRate int) float64 {
+	w := 2 * math.Pi * frequency / float64(sampleRate)
+
+	var response complex128
+	for n := range h {
+		response += complex(h[n], 0) * cmplx.Exp(complex(0, -w*float64(n)))
+	}
+
+	return cmplx.Abs(response)
+}
+
+// impulseResponse returns the first n frames of the response of a mono processor to a unit impulse.
+func impulseResponse(p gsp.SampleProcessor[float64, float64], n int) []float64 {
+	h := make([]float64, n)
+	h[0] = 1
+
+	for i := range h {
+		h[i] = p.Process(h[i])
+	}
+
+	return h
+}
+
+func TestBiquadMagnitude(t *testing.T) {
+	t.Parallel()
+
+	gain := math.Pow(10, biquadGain/20.0)
+	halfGain := math.Pow(10, biquadGain/40.0)
+
+	tests := []struct {
+		filterType      processors.BiquadType
+		dc, fc, nyquist float64
+	}{
+		{processors.BiquadLowPass, 1, biquadQ, 0},
+		{processors.BiquadHighPass, 0, biquadQ, 1},
+		{processors.BiquadBandPass, 0, 1, 0},
+		{processors.BiquadNotch, 1, 0, 1},
+		{processors.BiquadAllPass, 1, 1, 1},
+		{processors.BiquadPeaking, 1, gain, 1},
+		{processors.BiquadLowShelf, gain, halfGain, 1},
+		{processors.BiquadHighShelf, 1, halfGain, gain},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.filterType), func(t *testing.T) {
+			t.Parallel()
+
+			biquad := processors.NewBiquad[float64, float64](test.filterType, biquadSampleRate, biquadFrequency, biquadQ, biquadGain)
+			h := impulseResponse(biquad, 1<<14)
+
+			for _, point := range []struct {
+				name      string
+				frequency float64
+				want      float64
+			}{
+				{"DC", 0, test.dc},
+				{"fc", biquadFrequency, test.fc},
+				{"Nyquist", biquadSampleRate / 2, test.nyquist},
+			} {
+				got := magnitude(h, point.frequency, biquadSampleRate)
+				if math.Abs(got-point.want) > 1e-6 {
+					t.Errorf("wrong magnitude at %s: got '%g', want '%g'", point.name, got, point.want)
+				}
+			}
+		})
+	}
+}
+
+func TestBiquadChannels(t *testing.T) {
+	t.Parallel()
+
+	const N = 256
+
+	signal := func(channel, i int) float64 {
+		return math.Sin(2 * math.Pi * float64((channel+1)*i) / 37)
+	}
+
+	// Every channel must match a mono filter which only processed that channel.
+	want := make([][]float64, 3)
+
+	for c := range want {
+		mono := processors.NewBiquad[float64, float64](processors.BiquadLowPass, biquadSampleRate, biquadFrequency, biquadQ, 0)
+
+		want[c] = make([]float64, N)
+		for i := range want[c] {
+			want[c][i] = mono.Process(signal(c, i))
+		}
+	}
+
+	t.Run("stereo", func(t *testing.T) {
+		t.Parallel()
+
+		stereo := processors.NewBiquad[gsp.Stereo[float64], float64](processors.BiquadLowPass, biquadSampleRate, biquadFrequency, biquadQ, 0)
+
+		input := make([]gsp.Stereo[float64], N)
+		for i := range input {
+			input[i] = gsp.ToStereo(signal(0, i), signal(1, i))
+		}
+
+		output := make([]gsp.Stereo[float64], N)
+
+		// Half of the frames per frame, the other half per buffer.
+		for i := range N / 2 {
+			output[i] = stereo.Process(input[i])
+		}
+
+		stereo.ProcessBuffer(output[N/2:], input[N/2:])
+
+		for i := range output {
+			if output[i].L() != want[0][i] || output[i].R() != want[1][i] {
+				t.Fatalf("wrong frame at index '%d': got '%v', want '%v'", i, output[i], gsp.ToStereo(want[0][i], want[1][i]))
+			}
+		}
+	})
+
+	t.Run("multi-channel", func(t *testing.T) {
+		t.Parallel()
+
+		multiChannel := processors.NewBiquad[gsp.MultiChannel[float64], float64](processors.BiquadLowPass, biquadSampleRate, biquadFrequency, biquadQ, 0)
+
+		input := make([]gsp.MultiChannel[float64], N)
+		for i := range input {
+			input[i] = gsp.ToMultiChannel(signal(0, i), signal(1, i), signal(2, i))
+		}
+
+		output := make([]gsp.MultiChannel[float64], N)
+
+		multiChannel.ProcessBuffer(output[:N/2], input[:N/2])
+
+		for i := N / 2; i < N; i++ {
+			output[i] = multiChannel.Process(input[i])
+		}
+
+		for i := range output {
+			for c := range want {
+				if output[i][c] != want[c][i] {
+					t.Fatalf("wrong sample at index '%d' of channel '%d': got '%g', want '%g'", i, c, output[i][c], want[c][i])
+				}
+			}
+		}
+	})
+}
+
+func TestBiquadGlide(t *testing.T) {
+	t.Parallel()
+
+	biquad := processors.NewBiquad[float64, float64](processors.BiquadLowPass, biquadSampleRate, biquadFrequency, biquadQ, 0)
+
+	sine := func(i int) float64 {
+		return 0.5 * math.Sin(2*math.Pi*200*float64(i)/biquadSampleRate)
+	}
+
+	// Largest step between consecutive samples in the steady state.
+	var previous, steady float64
+
+	for i := range biquadSampleRate {
+		sample := biquad.Process(sine(i))
+
+		if i > biquadSampleRate/2 {
+			steady = max(steady, math.Abs(sample-previous))
+		}
+
+		previous = sample
+	}
+
+	// The sine is in the pass band of both designs, so a step much larger than in the steady state is a click.
+	// Without gliding, the step right after the change is about seven times larger.
+	biquad.SetFrequency(4 * biquadFrequency)
+
+	for i := biquadSampleRate; i < 2*biquadSampleRate; i++ {
+		sample := biquad.Process(sine(i))
+
+		if step := math.Abs(sample - previous); step > 2*steady {
+			t.Fatalf("click at index '%d': got step '%g', want at most '%g'", i, step, 2*steady)
+		}
+
+		previous = sample
+	}
+
+	// Once the glide has ended, the filter matches the new design.
+	biquad.Reset()
+
+	want := processors.NewBiquad[float64, float64](processors.BiquadLowPass, biquadSampleRate, 4*biquadFrequency, biquadQ, 0)
+
+	got, wantResponse := impulseResponse(biquad, 64), impulseResponse(want, 64)
+	for i := range got {
+		if math.Abs(got[i]-wantResponse[i]) > 1e-12 {
+			t.Errorf("wrong impulse response at index '%d': got '%g', want '%g'", i, got[i], wantResponse[i])
+		}
+	}
+}
+
+func TestBiquadInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		frequency, q float64
+	}{
+		{"zero frequency", 0, 1},
+		{"nyquist", biquadSampleRate / 2, 1},
+		{"above nyquist", biquadSampleRate, 1},
+		{"zero q", biquadFrequency, 0},
+		{"negative q", biquadFrequency, -1},
+		{"NaN q", biquadFrequency, math.NaN()},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if recover() == nil {
+					t.Errorf("no panic for frequency '%g' and q '%g'", test.frequency, test.q)
+				}
+			}()
+
+			_ = processors.NewBiquad[float64, float64](processors.BiquadLowPass, biquadSampleRate, test.frequency, test.q, 0)
+		})
+	}
+
+	biquad := processors.NewBiquad[float64, float64](processors.BiquadLowPass, biquadSampleRate, biquadFrequency, biquadQ, 0)
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("no panic for frequency '%d'", biquadSampleRate/2)
+		}
+	}()
+
+	biquad.SetFrequency(biquadSampleRate / 2)
+}
+
+func TestBiquadAllocations(t *testing.T) {
+	biquad := processors.NewBiquad[gsp.MultiChannel[float32], float32](processors.BiquadLowPass, biquadSampleRate, biquadFrequency, biquadQ, 0)
+	frame := gsp.ToMultiChannel[float32](0.1, 0.2, 0.3, 0.4)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		frame = biquad.Process(frame)
+	})
+
+	if allocs != 0 {
+		t.Errorf("wrong number of allocations: got '%g', want '%d'", allocs, 0)
+	}
+}
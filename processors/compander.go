@@ -8,6 +8,8 @@ import (
 	"github.com/samborkent/gsp/internal/gmath"
 )
 
+var _ gsp.InPlaceProcessor = &Compander[float32, float32]{}
+
 type CompanderAlgorithm string
 
 const (
@@ -126,6 +128,11 @@ func (p *Compander[F, T]) Process(sample F) F {
 	}
 }
 
+// InPlace reports that every frame is read before it is written, so output may alias input.
+func (p *Compander[F, T]) InPlace() bool {
+	return true
+}
+
 func (p *Compander[F, T]) ProcessBuffer(output, input []F) {
 	size := min(len(output), len(input))
 	if size == 0 {
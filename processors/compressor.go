@@ -0,0 +1,231 @@
+package processors
+
+import (
+	"time"
+
+	"github.com/samborkent/gsp"
+	"github.com/samborkent/gsp/internal/gmath"
+)
+
+// CompressorConfig contains the parameters of a [Compressor].
+type CompressorConfig[T gsp.Float] struct {
+	Threshold  T                 // Level in dB above which the gain is reduced.
+	Ratio      T                 // Ratio of input to output level change above the threshold, values below one are treated as one.
+	Knee       T                 // Width in dB of the soft knee centered on the threshold, zero gives a hard knee.
+	Attack     time.Duration     // Time constant of increasing gain reduction.
+	Release    time.Duration     // Time constant of decreasing gain reduction.
+	MakeupGain T                 // Gain in dB applied after compression.
+	Detection  DynamicsDetection // Level detection, defaults to peak detection.
+	RMSWindow  time.Duration     // Averaging time of RMS detection, defaults to 10 ms.
+	Linked     bool              // Compute a single gain from the loudest channel, which preserves the stereo image.
+}
+
+// Compressor is a feed-forward dynamic range compressor.
+// The gain is computed from the level of the input, or from an external sidechain signal,
+// and is smoothed in the log domain with separate attack and release time constants.
+type Compressor[F gsp.Frame[T], T gsp.Float] struct {
+	config     CompressorConfig[T]
+	sampleRate int
+
+	attack, release, rms T // Smoothing coefficients.
+	makeup               T // Linear makeup gain.
+
+	meanSquare []T // Mean square level per sidechain channel, only used by RMS detection.
+	gain       []T // Smoothed gain in dB per channel, only the first is used by linked detection.
+
+	gainReduction atomicFloat
+	mode          Mode
+}
+
+func NewCompressor[F gsp.Frame[T], T gsp.Float](sampleRate int, config CompressorConfig[T]) *Compressor[F, T] {
+	compressor := &Compressor[F, T]{
+		config:     config,
+		sampleRate: sampleRate,
+	}
+
+	if compressor.config.Ratio < 1 {
+		compressor.config.Ratio = 1
+	}
+
+	if compressor.config.Detection == "" {
+		compressor.config.Detection = DynamicsDetectionPeak
+	}
+
+	if compressor.config.RMSWindow <= 0 {
+		compressor.config.RMSWindow = dynamicsRMSWindow
+	}
+
+	switch any(*new(F)).(type) {
+	case T:
+		compressor.mode = ModeMono
+		compressor.grow(1)
+	case [2]T, gsp.Stereo[T]:
+		compressor.mode = ModeStereo
+		compressor.grow(2)
+	case []T, gsp.MultiChannel[T]:
+		compressor.mode = ModeMultiChannel
+	default:
+		panic("gsp: NewCompressor: unknown audio frame type")
+	}
+
+	compressor.Init(gsp.Format{SampleRate: sampleRate})
+
+	return compressor
+}
+
+// Init sets the sample rate, and clears the detector state.
+func (p *Compressor[F, T]) Init(format gsp.Format) {
+	if format.SampleRate > 0 {
+		p.sampleRate = format.SampleRate
+	}
+
+	if format.Channels > 0 {
+		p.grow(format.Channels)
+	}
+
+	p.attack = smoothingCoefficient[T](p.config.Attack, p.sampleRate)
+	p.release = smoothingCoefficient[T](p.config.Release, p.sampleRate)
+	p.rms = smoothingCoefficient[T](p.config.RMSWindow, p.sampleRate)
+	p.makeup = gsp.DBToLinear(p.config.MakeupGain)
+
+	p.Reset()
+}
+
+// Reset clears the detector state.
+func (p *Compressor[F, T]) Reset() {
+	clear(p.meanSquare)
+	clear(p.gain)
+	p.gainReduction.Store(0)
+}
+
+// GainReduction returns the current gain reduction in dB, as a positive number.
+// It is safe to call concurrently with processing, which makes it suitable for metering.
+func (p *Compressor[F, T]) GainReduction() T {
+	return T(p.gainReduction.Load())
+}
+
+func (p *Compressor[F, T]) Process(sample F) F {
+	return p.ProcessSidechain(sample, sample)
+}
+
+// ProcessSidechain compresses sample, with the gain computed from the sidechain frame.
+func (p *Compressor[F, T]) ProcessSidechain(sample, sidechain F) F {
+	var processedSample F
+
+	input := frameSamples[F, T](p.mode, &sample)
+	output := outputSamples[F, T](p.mode, &processedSample, len(input))
+
+	p.gainReduction.Store(float64(p.processFrame(output, input, frameSamples[F, T](p.mode, &sidechain))))
+
+	return processedSample
+}
+
+func (p *Compressor[F, T]) ProcessBuffer(output, input []F) {
+	p.ProcessBufferSidechain(output, input, input)
+}
+
+// ProcessBufferSidechain compresses input into output, with the gain computed from the sidechain frames.
+// The sidechain may have a different number of channels than the input.
+func (p *Compressor[F, T]) ProcessBufferSidechain(output, input, sidechain []F) {
+	size := min(len(output), len(input), len(sidechain))
+	if size == 0 {
+		return
+	}
+
+	var reduction T
+
+	for i := range size {
+		inputFrame := frameSamples[F, T](p.mode, &input[i])
+		outputFrame := outputSamples[F, T](p.mode, &output[i], len(inputFrame))
+
+		reduction = p.processFrame(outputFrame, inputFrame, frameSamples[F, T](p.mode, &sidechain[i]))
+	}
+
+	p.gainReduction.Store(float64(reduction))
+}
+
+// processFrame compresses a single frame, and returns the largest gain reduction in dB.
+func (p *Compressor[F, T]) processFrame(output, input, sidechain []T) T {
+	if len(sidechain) == 0 {
+		copy(output, input)
+		return 0
+	}
+
+	p.grow(max(len(input), len(sidechain)))
+
+	if p.config.Linked {
+		level := T(minLevel)
+		for c := range sidechain {
+			level = max(level, p.detect(c, sidechain[c]))
+		}
+
+		gain := p.smooth(0, p.computeGain(level))
+		linearGain := gsp.DBToLinear(gain) * p.makeup
+
+		for c := range input {
+			output[c] = input[c] * linearGain
+		}
+
+		return -gain
+	}
+
+	var reduction T
+
+	for c := range input {
+		// Channels without a sidechain channel of their own share the sidechain channels.
+		key := c % len(sidechain)
+
+		gain := p.smooth(c, p.computeGain(p.detect(key, sidechain[key])))
+		output[c] = input[c] * gsp.DBToLinear(gain) * p.makeup
+
+		reduction = max(reduction, -gain)
+	}
+
+	return reduction
+}
+
+// detect returns the level in dB of a sidechain channel.
+func (p *Compressor[F, T]) detect(channel int, sample T) T {
+	if p.config.Detection == DynamicsDetectionRMS {
+		p.meanSquare[channel] = p.rms*p.meanSquare[channel] + (1-p.rms)*sample*sample
+		return levelToDB(gmath.Sqrt(p.meanSquare[channel]))
+	}
+
+	return levelToDB(gmath.Abs(sample))
+}
+
+// computeGain returns the static gain in dB for a level in dB, with a quadratic soft knee.
+func (p *Compressor[F, T]) computeGain(level T) T {
+	overshoot := level - p.config.Threshold
+	slope := 1/p.config.Ratio - 1
+
+	switch {
+	case 2*overshoot <= -p.config.Knee:
+		return 0
+	case 2*overshoot < p.config.Knee:
+		x := overshoot + p.config.Knee/2
+		return slope * x * x / (2 * p.config.Knee)
+	default:
+		return slope * overshoot
+	}
+}
+
+// smooth moves the gain of a channel towards the target gain, with the attack time constant when the gain decreases.
+func (p *Compressor[F, T]) smooth(channel int, target T) T {
+	coefficient := p.release
+	if target < p.gain[channel] {
+		coefficient = p.attack
+	}
+
+	p.gain[channel] = coefficient*p.gain[channel] + (1-coefficient)*target
+
+	return p.gain[channel]
+}
+
+// grow ensures there is detector state for the number of channels.
+func (p *Compressor[F, T]) grow(channels int) {
+	if len(p.gain) < channels {
+		p.gain = append(p.gain, make([]T, channels-len(p.gain))...)
+		p.meanSquare = append(p.meanSquare, make([]T, channels-len(p.meanSquare))...)
+	}
+}
@@ -0,0 +1,280 @@
+package processors_test
+
+import (
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samborkent/gsp"
+	"github.com/samborkent/gsp/processors"
+)
+
+const dynamicsSampleRate = 48000
+
+// constant returns n samples of the linear level of a level in dB.
+func constant(level float64, n int) []float64 {
+	signal := make([]float64, n)
+	for i := range signal {
+		signal[i] = gsp.DBToLinear(level)
+	}
+
+	return signal
+}
+
+// gainDB returns the gain in dB applied to a non-zero input sample.
+func gainDB(output, input float64) float64 {
+	return gsp.LinearToDB(output / input)
+}
+
+func TestCompressorStaticCurve(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		knee  float64
+		level float64
+		want  float64
+	}{
+		{"below threshold", 0, -30, 0},
+		{"at threshold", 0, -20, 0},
+		{"above threshold", 0, -10, -7.5},
+		{"below knee", 10, -26, 0},
+		{"knee start", 10, -25, 0},
+		{"knee center", 10, -20, -0.9375},
+		{"knee end", 10, -15, -3.75},
+		{"above knee", 10, -5, -11.25},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Without attack and release, the gain follows the static curve.
+			compressor := processors.NewCompressor[float64, float64](dynamicsSampleRate, processors.CompressorConfig[float64]{
+				Threshold:  -20,
+				Ratio:      4,
+				Knee:       test.knee,
+				MakeupGain: 3,
+			})
+
+			input := constant(test.level, 16)
+			output := make([]float64, len(input))
+			compressor.ProcessBuffer(output, input)
+
+			if got := gainDB(output[15], input[15]); math.Abs(got-(test.want+3)) > 1e-9 {
+				t.Errorf("wrong gain: got '%g' dB, want '%g' dB", got, test.want+3)
+			}
+
+			if got := compressor.GainReduction(); math.Abs(got+test.want) > 1e-9 {
+				t.Errorf("wrong gain reduction: got '%g' dB, want '%g' dB", got, -test.want)
+			}
+		})
+	}
+
+	// Ratios below one do not expand.
+	compressor := processors.NewCompressor[float64, float64](dynamicsSampleRate, processors.CompressorConfig[float64]{Threshold: -20, Ratio: 0.5})
+
+	if got := gainDB(compressor.Process(0.5), 0.5); got != 0 {
+		t.Errorf("wrong gain for ratio below one: got '%g' dB, want '%g' dB", got, 0.0)
+	}
+}
+
+func TestCompressorTimeConstants(t *testing.T) {
+	t.Parallel()
+
+	const timeConstant = 10 * time.Millisecond
+
+	frames := int(timeConstant.Seconds() * dynamicsSampleRate)
+
+	compressor := processors.NewCompressor[float64, float64](dynamicsSampleRate, processors.CompressorConfig[float64]{
+		Threshold: -20,
+		Ratio:     math.Inf(1),
+		Attack:    timeConstant,
+		Release:   4 * timeConstant,
+	})
+
+	// A 10 dB overshoot without knee targets 10 dB of gain reduction.
+	loud := constant(-10, frames)
+	output := make([]float64, frames)
+	compressor.ProcessBuffer(output, loud)
+
+	// After one time constant, the gain covered 1 - 1/e of the step.
+	want := -10 * (1 - 1/math.E)
+	if got := gainDB(output[frames-1], loud[frames-1]); math.Abs(got-want) > 0.05 {
+		t.Errorf("wrong gain after attack time: got '%g' dB, want '%g' dB", got, want)
+	}
+
+	// Settle on the target.
+	for range 20 {
+		compressor.ProcessBuffer(output, loud)
+	}
+
+	if got := compressor.GainReduction(); math.Abs(got-10) > 1e-6 {
+		t.Fatalf("wrong settled gain reduction: got '%g' dB, want '%g' dB", got, 10.0)
+	}
+
+	// Below the threshold, the gain returns with the release time constant.
+	quiet := constant(-40, 4*frames)
+	output = make([]float64, 4*frames)
+	compressor.ProcessBuffer(output, quiet)
+
+	want = -10 / math.E
+	if got := gainDB(output[4*frames-1], quiet[4*frames-1]); math.Abs(got-want) > 0.05 {
+		t.Errorf("wrong gain after release time: got '%g' dB, want '%g' dB", got, want)
+	}
+}
+
+func TestCompressorDetection(t *testing.T) {
+	t.Parallel()
+
+	// A full-scale sine has a peak level of 0 dB and an RMS level of about -3 dB.
+	sine := make([]float64, dynamicsSampleRate/2)
+	for i := range sine {
+		sine[i] = math.Sin(2 * math.Pi * 1000 * float64(i) / dynamicsSampleRate)
+	}
+
+	tests := []struct {
+		detection processors.DynamicsDetection
+		want      float64
+	}{
+		{processors.DynamicsDetectionPeak, 2},
+		{processors.DynamicsDetectionRMS, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.detection), func(t *testing.T) {
+			t.Parallel()
+
+			compressor := processors.NewCompressor[float64, float64](dynamicsSampleRate, processors.CompressorConfig[float64]{
+				Threshold: -2,
+				Ratio:     math.Inf(1),
+				Detection: test.detection,
+			})
+
+			var reduction float64
+
+			for i := range sine {
+				_ = compressor.Process(sine[i])
+
+				// Skip the settling of the RMS detector.
+				if i > len(sine)/2 {
+					reduction = max(reduction, compressor.GainReduction())
+				}
+			}
+
+			if math.Abs(reduction-test.want) > 0.01 {
+				t.Errorf("wrong largest gain reduction: got '%g' dB, want '%g' dB", reduction, test.want)
+			}
+		})
+	}
+}
+
+func TestCompressorLinked(t *testing.T) {
+	t.Parallel()
+
+	loud, quiet := gsp.DBToLinear(-10.0), gsp.DBToLinear(-40.0)
+
+	tests := []struct {
+		name      string
+		linked    bool
+		wantRight float64
+	}{
+		{"unlinked", false, 0},
+		{"linked", true, -10},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			compressor := processors.NewCompressor[gsp.Stereo[float64], float64](dynamicsSampleRate, processors.CompressorConfig[float64]{
+				Threshold: -20,
+				Ratio:     math.Inf(1),
+				Linked:    test.linked,
+			})
+
+			frame := compressor.Process(gsp.ToStereo(loud, quiet))
+
+			if got := gainDB(frame.L(), loud); math.Abs(got+10) > 1e-9 {
+				t.Errorf("wrong left gain: got '%g' dB, want '%g' dB", got, -10.0)
+			}
+
+			// A linked compressor reduces the quiet channel by the gain of the loud channel.
+			if got := gainDB(frame.R(), quiet); math.Abs(got-test.wantRight) > 1e-9 {
+				t.Errorf("wrong right gain: got '%g' dB, want '%g' dB", got, test.wantRight)
+			}
+		})
+	}
+}
+
+func TestCompressorSidechain(t *testing.T) {
+	t.Parallel()
+
+	compressor := processors.NewCompressor[float64, float64](dynamicsSampleRate, processors.CompressorConfig[float64]{
+		Threshold: -20,
+		Ratio:     2,
+	})
+
+	// A quiet input is ducked by a loud sidechain.
+	input, sidechain := constant(-40, 4), constant(0, 4)
+	output := make([]float64, len(input))
+	compressor.ProcessBufferSidechain(output, input, sidechain)
+
+	if got := gainDB(output[3], input[3]); math.Abs(got+10) > 1e-9 {
+		t.Errorf("wrong gain with loud sidechain: got '%g' dB, want '%g' dB", got, -10.0)
+	}
+
+	// A loud input is left alone by a quiet sidechain.
+	input, sidechain = constant(0, 4), constant(-40, 4)
+	compressor.ProcessBufferSidechain(output, input, sidechain)
+
+	if got := gainDB(output[3], input[3]); math.Abs(got) > 1e-9 {
+		t.Errorf("wrong gain with quiet sidechain: got '%g' dB, want '%g' dB", got, 0.0)
+	}
+
+	want := -(gsp.LinearToDB(0.5) + 20) / 2
+	if got := gainDB(compressor.ProcessSidechain(1, 0.5), 1); math.Abs(got-want) > 1e-9 {
+		t.Errorf("wrong gain of single frame: got '%g' dB, want '%g' dB", got, want)
+	}
+}
+
+func TestCompressorGainReduction(t *testing.T) {
+	t.Parallel()
+
+	compressor := processors.NewCompressor[float64, float64](dynamicsSampleRate, processors.CompressorConfig[float64]{
+		Threshold: -20,
+		Ratio:     math.Inf(1),
+		Attack:    time.Millisecond,
+		Release:   time.Millisecond,
+	})
+
+	input := constant(-10, 256)
+	output := make([]float64, len(input))
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		for range 100 {
+			compressor.ProcessBuffer(output, input)
+		}
+	}()
+
+	// Metering reads the gain reduction while the audio goroutine writes it.
+	for range 1000 {
+		if got := compressor.GainReduction(); got < 0 || got > 10+1e-9 {
+			t.Errorf("wrong gain reduction: got '%g' dB, want between '%g' and '%g' dB", got, 0.0, 10.0)
+			break
+		}
+	}
+
+	wg.Wait()
+
+	if got := compressor.GainReduction(); math.Abs(got-10) > 1e-6 {
+		t.Errorf("wrong final gain reduction: got '%g' dB, want '%g' dB", got, 10.0)
+	}
+}
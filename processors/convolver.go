@@ -0,0 +1,282 @@
+package processors
+
+import (
+	"fmt"
+	"io"
+	"unsafe"
+
+	"github.com/samborkent/gsp"
+	"github.com/samborkent/gsp/fft"
+)
+
+var _ gsp.LatencyReporter = &Convolver[float32, float32]{}
+
+// convolverDefaultBlockSize is the default partition size of a [Convolver].
+const convolverDefaultBlockSize = 256
+
+// Convolver convolves a stream with long impulse responses, such as reverbs and cabinet simulations.
+// The impulse responses are split into uniform partitions which are convolved in the frequency domain,
+// so the processing cost grows slowly with the impulse response length, and the latency equals the block size.
+type Convolver[F gsp.Frame[T], T gsp.Float] struct {
+	blockSize int
+	plan      *fft.RealPlan[T]
+	filters   []*partitionedFilter[T]   // Impulse response per channel, a single impulse response is used for all channels.
+	matrix    [][]*partitionedFilter[T] // Impulse responses of true-stereo mode, indexed by input then output channel.
+	stream    *partitionedStream[T]
+
+	channels int
+	mode     Mode
+}
+
+// NewConvolver returns a [Convolver] with an impulse response per channel.
+// If a single impulse response is given, it is used for all channels.
+// Channels without an impulse response are silenced.
+func NewConvolver[F gsp.Frame[T], T gsp.Float](irs [][]T, options ...ConvolverOption) *Convolver[F, T] {
+	if len(irs) == 0 {
+		panic("gsp: NewConvolver: no impulse response")
+	}
+
+	convolver := newConvolver[F, T]("NewConvolver", options)
+
+	convolver.filters = make([]*partitionedFilter[T], len(irs))
+	for c, ir := range irs {
+		convolver.filters[c] = newPartitionedFilter(convolver.plan, convolver.blockSize, ir)
+	}
+
+	switch convolver.mode {
+	case ModeMono:
+		convolver.allocate(1)
+	case ModeStereo:
+		convolver.allocate(2)
+	case ModeMultiChannel:
+		if len(irs) > 1 {
+			convolver.allocate(len(irs))
+		}
+	}
+
+	return convolver
+}
+
+// NewTrueStereoConvolver returns a [Convolver] for stereo frames, where both input channels feed both output channels.
+// The impulse responses are named after their input and output channel, such that lr is the response of the right output to the left input.
+// Mono and multi-channel frames are not supported.
+func NewTrueStereoConvolver[F gsp.Frame[T], T gsp.Float](ll, lr, rl, rr []T, options ...ConvolverOption) *Convolver[F, T] {
+	convolver := newConvolver[F, T]("NewTrueStereoConvolver", options)
+	if convolver.mode != ModeStereo {
+		panic("gsp: NewTrueStereoConvolver: frame type is not stereo")
+	}
+
+	filter := func(ir []T) *partitionedFilter[T] {
+		return newPartitionedFilter(convolver.plan, convolver.blockSize, ir)
+	}
+
+	convolver.matrix = [][]*partitionedFilter[T]{
+		gsp.L: {gsp.L: filter(ll), gsp.R: filter(lr)},
+		gsp.R: {gsp.L: filter(rl), gsp.R: filter(rr)},
+	}
+
+	convolver.allocate(2)
+
+	return convolver
+}
+
+func newConvolver[F gsp.Frame[T], T gsp.Float](function string, options []ConvolverOption) *Convolver[F, T] {
+	cfg := &ConvolverConfig{}
+	for _, option := range options {
+		option(cfg)
+	}
+
+	if cfg.BlockSize <= 0 {
+		cfg.BlockSize = convolverDefaultBlockSize
+	}
+
+	convolver := &Convolver[F, T]{
+		blockSize: cfg.BlockSize,
+		plan:      fft.NewRealPlan[T](2 * cfg.BlockSize),
+	}
+
+	switch any(*new(F)).(type) {
+	case T:
+		convolver.mode = ModeMono
+	case [2]T, gsp.Stereo[T]:
+		convolver.mode = ModeStereo
+	case []T, gsp.MultiChannel[T]:
+		convolver.mode = ModeMultiChannel
+	default:
+		panic("gsp: " + function + ": unknown audio frame type")
+	}
+
+	return convolver
+}
+
+// Init allocates the convolution state of multi-channel streams, and clears it.
+func (p *Convolver[F, T]) Init(format gsp.Format) {
+	if p.mode == ModeMultiChannel && format.Channels > 0 {
+		p.allocate(format.Channels)
+	}
+
+	p.Reset()
+}
+
+// Latency returns the number of frames by which the output is delayed.
+func (p *Convolver[F, T]) Latency() int {
+	return p.blockSize
+}
+
+// Reset clears the convolution state.
+func (p *Convolver[F, T]) Reset() {
+	if p.stream != nil {
+		p.stream.reset()
+	}
+}
+
+func (p *Convolver[F, T]) Process(sample F) F {
+	switch p.mode {
+	case ModeMono:
+		monoSample := *(*T)(unsafe.Pointer(&sample))
+		processedSample := p.stream.exchange(0, monoSample)
+		p.stream.advance()
+
+		return *(*F)(unsafe.Pointer(&processedSample))
+	case ModeStereo:
+		stereoSample := *(*gsp.Stereo[T])(unsafe.Pointer(&sample))
+		processedSamples := [2]T{
+			p.stream.exchange(gsp.L, stereoSample.L()),
+			p.stream.exchange(gsp.R, stereoSample.R()),
+		}
+		p.stream.advance()
+
+		return *(*F)(unsafe.Pointer(&processedSamples))
+	case ModeMultiChannel:
+		multiChannelSample := *(*gsp.MultiChannel[T])(unsafe.Pointer(&sample))
+		p.grow(len(multiChannelSample))
+
+		processedSamples := make([]T, len(multiChannelSample))
+		for i := range multiChannelSample {
+			processedSamples[i] = p.stream.exchange(i, multiChannelSample[i])
+		}
+		p.stream.advance()
+
+		return *(*F)(unsafe.Pointer(&processedSamples))
+	default:
+		return *new(F)
+	}
+}
+
+func (p *Convolver[F, T]) ProcessBuffer(output, input []F) {
+	size := min(len(output), len(input))
+	if size == 0 {
+		return
+	}
+
+	switch p.mode {
+	case ModeMono:
+		samplePtr := (*T)(unsafe.Pointer(&input[0]))
+		monoSamples := unsafe.Slice(samplePtr, len(input))
+
+		for i := range size {
+			processedSample := p.stream.exchange(0, monoSamples[i])
+			p.stream.advance()
+			output[i] = *(*F)(unsafe.Pointer(&processedSample))
+		}
+	case ModeStereo:
+		samplePtr := (*gsp.Stereo[T])(unsafe.Pointer(&input[0]))
+		stereoSamples := unsafe.Slice(samplePtr, len(input))
+
+		for i := range size {
+			processedSamples := [2]T{
+				p.stream.exchange(gsp.L, stereoSamples[i].L()),
+				p.stream.exchange(gsp.R, stereoSamples[i].R()),
+			}
+			p.stream.advance()
+			output[i] = *(*F)(unsafe.Pointer(&processedSamples))
+		}
+	case ModeMultiChannel:
+		samplePtr := (*gsp.MultiChannel[T])(unsafe.Pointer(&input[0]))
+		multiChannelSamples := unsafe.Slice(samplePtr, len(input))
+
+		outputPtr := (*gsp.MultiChannel[T])(unsafe.Pointer(&output[0]))
+		outputSamples := unsafe.Slice(outputPtr, len(output))
+
+		for i := range size {
+			p.grow(len(multiChannelSamples[i]))
+
+			if len(outputSamples[i]) != len(multiChannelSamples[i]) {
+				outputSamples[i] = make(gsp.MultiChannel[T], len(multiChannelSamples[i]))
+			}
+
+			for j := range multiChannelSamples[i] {
+				outputSamples[i][j] = p.stream.exchange(j, multiChannelSamples[i][j])
+			}
+			p.stream.advance()
+		}
+	}
+}
+
+// grow ensures there is convolution state for the number of channels.
+func (p *Convolver[F, T]) grow(channels int) {
+	if p.channels < channels {
+		p.allocate(channels)
+	}
+}
+
+// allocate creates cleared convolution state for the number of channels.
+func (p *Convolver[F, T]) allocate(channels int) {
+	p.channels = channels
+
+	filters := p.matrix
+	if filters == nil {
+		filters = make([][]*partitionedFilter[T], channels)
+
+		for c := range filters {
+			filters[c] = make([]*partitionedFilter[T], channels)
+
+			if len(p.filters) == 1 {
+				filters[c][c] = p.filters[0]
+			} else if c < len(p.filters) {
+				filters[c][c] = p.filters[c]
+			}
+		}
+	}
+
+	p.stream = newPartitionedStream(p.plan, p.blockSize, filters)
+}
+
+// DecodeIR decodes an impulse response from r, and returns its samples per channel.
+// The stream must be raw PCM or floating-point samples of type T, described by the encoding options.
+func DecodeIR[F gsp.Frame[T], T gsp.Float](r io.Reader, options ...gsp.EncodingOption) ([][]T, error) {
+	decoder := gsp.NewDecoder[F, T](r, options...)
+	frames := new(gsp.Buffer[F, T])
+
+	err := decoder.DecodeTo(frames)
+	if err != nil {
+		return nil, fmt.Errorf("gsp: DecodeIR: %w", err)
+	}
+
+	channels := decoder.Channels()
+	if channels == 0 {
+		return nil, fmt.Errorf("gsp: DecodeIR: %w", gsp.ErrUnknownChannelCount)
+	}
+
+	irs := make([][]T, channels)
+	for c := range irs {
+		irs[c] = make([]T, frames.Len())
+	}
+
+	for i, frame := range frames.Frames() {
+		var samples []T
+
+		switch any(frame).(type) {
+		case []T, gsp.MultiChannel[T]:
+			samples = *(*[]T)(unsafe.Pointer(&frame))
+		default:
+			samples = unsafe.Slice((*T)(unsafe.Pointer(&frame)), channels)
+		}
+
+		for c := range min(channels, len(samples)) {
+			irs[c][i] = samples[c]
+		}
+	}
+
+	return irs, nil
+}
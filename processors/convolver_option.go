@@ -0,0 +1,17 @@
+package processors
+
+// ConvolverOption is a functional option for [Convolver].
+type ConvolverOption func(cfg *ConvolverConfig)
+
+// ConvolverConfig contains all configuration options for [Convolver].
+type ConvolverConfig struct {
+	BlockSize int // Partition size of the impulse response, which is also the latency in frames.
+}
+
+// ConvolverBlockSize sets the partition size of the impulse response, which defaults to 256 frames.
+// Smaller blocks lower the latency, at the cost of more processing.
+func ConvolverBlockSize(frames int) ConvolverOption {
+	return func(cfg *ConvolverConfig) {
+		cfg.BlockSize = frames
+	}
+}
@@ -0,0 +1,148 @@
+package processors_test
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/samborkent/gsp"
+	"github.com/samborkent/gsp/processors"
+	"github.com/samborkent/gsp/wav"
+)
+
+func TestConvolverImpulse(t *testing.T) {
+	t.Parallel()
+
+	const blockSize = 32
+
+	convolver := processors.NewConvolver[float64, float64]([][]float64{{1}}, processors.ConvolverBlockSize(blockSize))
+
+	if convolver.Latency() != blockSize {
+		t.Fatalf("wrong latency: got '%d', want '%d'", convolver.Latency(), blockSize)
+	}
+
+	input := randomSignal(500, 6)
+	output := make([]float64, len(input))
+	convolver.ProcessBuffer(output, input)
+
+	// A unit impulse only delays the input by the latency.
+	for i := range output {
+		want := 0.0
+		if i >= blockSize {
+			want = input[i-blockSize]
+		}
+
+		if math.Abs(output[i]-want) > 1e-12 {
+			t.Fatalf("wrong sample at index '%d': got '%g', want '%g'", i, output[i], want)
+		}
+	}
+}
+
+func TestConvolverPartitioned(t *testing.T) {
+	t.Parallel()
+
+	const blockSize = 32
+
+	// Three and a half partitions per impulse response, and a different response per channel.
+	irs := [][]float64{randomSignal(7*blockSize/2, 7), randomSignal(7*blockSize/2, 8)}
+	left, right := randomSignal(1000, 9), randomSignal(1000, 10)
+	wantLeft, wantRight := convolve(left, irs[0]), convolve(right, irs[1])
+
+	convolver := processors.NewConvolver[gsp.Stereo[float64], float64](irs, processors.ConvolverBlockSize(blockSize))
+
+	input := make([]gsp.Stereo[float64], len(left))
+	for i := range input {
+		input[i] = gsp.ToStereo(left[i], right[i])
+	}
+
+	output := make([]gsp.Stereo[float64], len(input))
+
+	// Buffers which are not a multiple of the block size.
+	for i := 0; i < len(input); i += 100 {
+		convolver.ProcessBuffer(output[i:min(i+100, len(input))], input[i:min(i+100, len(input))])
+	}
+
+	for i := blockSize; i < len(output); i++ {
+		if math.Abs(output[i].L()-wantLeft[i-blockSize]) > 1e-9 || math.Abs(output[i].R()-wantRight[i-blockSize]) > 1e-9 {
+			t.Fatalf("wrong frame at index '%d': got '%v', want '%v'", i, output[i], gsp.ToStereo(wantLeft[i-blockSize], wantRight[i-blockSize]))
+		}
+	}
+}
+
+func TestConvolverTrueStereo(t *testing.T) {
+	t.Parallel()
+
+	const blockSize = 16
+
+	ll, lr, rl, rr := randomSignal(40, 11), randomSignal(50, 12), randomSignal(10, 13), randomSignal(70, 14)
+	left, right := randomSignal(400, 15), randomSignal(400, 16)
+
+	// Each output is the sum of both inputs convolved with the responses to that output.
+	leftToLeft, rightToLeft := convolve(left, ll), convolve(right, rl)
+	leftToRight, rightToRight := convolve(left, lr), convolve(right, rr)
+
+	convolver := processors.NewTrueStereoConvolver[gsp.Stereo[float64], float64](ll, lr, rl, rr, processors.ConvolverBlockSize(blockSize))
+
+	for i := range left {
+		frame := convolver.Process(gsp.ToStereo(left[i], right[i]))
+		if i < blockSize {
+			continue
+		}
+
+		j := i - blockSize
+		want := gsp.ToStereo(leftToLeft[j]+rightToLeft[j], leftToRight[j]+rightToRight[j])
+
+		if math.Abs(frame.L()-want.L()) > 1e-9 || math.Abs(frame.R()-want.R()) > 1e-9 {
+			t.Fatalf("wrong frame at index '%d': got '%v', want '%v'", i, frame, want)
+		}
+	}
+}
+
+func TestDecodeIR(t *testing.T) {
+	t.Parallel()
+
+	left, right := randomSignal(300, 17), randomSignal(300, 18)
+
+	frames := make([]gsp.Stereo[float32], len(left))
+	for i := range frames {
+		frames[i] = gsp.ToStereo(float32(left[i]), float32(right[i]))
+	}
+
+	file := new(bytes.Buffer)
+
+	writer, err := wav.NewWriter[gsp.Stereo[float32], float32](file, 48000)
+	if err != nil {
+		t.Fatalf("creating wav writer: error: %s", err.Error())
+	}
+
+	_, err = writer.Write(frames)
+	if err != nil {
+		t.Fatalf("writing frames: error: %s", err.Error())
+	}
+
+	err = writer.Close()
+	if err != nil {
+		t.Fatalf("closing wav writer: error: %s", err.Error())
+	}
+
+	// Parsing the header leaves the file at the start of the data chunk.
+	reader, err := wav.NewReader[gsp.MultiChannel[float32], float32](file)
+	if err != nil {
+		t.Fatalf("creating wav reader: error: %s", err.Error())
+	}
+
+	irs, err := processors.DecodeIR[gsp.MultiChannel[float32], float32](file, gsp.EncodingChannels(reader.Header().Channels))
+	if err != nil {
+		t.Fatalf("decoding impulse response: error: %s", err.Error())
+	}
+
+	if len(irs) != 2 || len(irs[0]) != len(left) || len(irs[1]) != len(right) {
+		t.Fatalf("wrong impulse response size: got '%d' channels, want '%d' channels of '%d' samples", len(irs), 2, len(left))
+	}
+
+	for i := range left {
+		if irs[0][i] != float32(left[i]) || irs[1][i] != float32(right[i]) {
+			t.Fatalf("wrong impulse response at index '%d': got '%g' and '%g', want '%g' and '%g'", i, irs[0][i], irs[1][i], float32(left[i]), float32(right[i]))
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package processors
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"github.com/samborkent/gsp"
+)
+
+// DynamicsDetection selects how the signal level of a dynamics processor is detected.
+type DynamicsDetection string
+
+const (
+	DynamicsDetectionPeak DynamicsDetection = "peak"
+	DynamicsDetectionRMS  DynamicsDetection = "RMS"
+)
+
+// dynamicsRMSWindow is the default averaging time of RMS detection.
+const dynamicsRMSWindow = 10 * time.Millisecond
+
+// minLevel is the lowest detected level in dB, which prevents taking the logarithm of zero.
+const minLevel = -200
+
+// smoothingCoefficient returns the pole of a one-pole smoother with the given time constant.
+// A zero time constant returns zero, which disables smoothing.
+func smoothingCoefficient[T gsp.Float](timeConstant time.Duration, sampleRate int) T {
+	if timeConstant <= 0 || sampleRate <= 0 {
+		return 0
+	}
+
+	return T(math.Exp(-1 / (timeConstant.Seconds() * float64(sampleRate))))
+}
+
+// levelToDB converts a linear level to dB, clamped to minLevel.
+func levelToDB[T gsp.Float](level T) T {
+	if level <= 0 {
+		return minLevel
+	}
+
+	return max(minLevel, gsp.LinearToDB(level))
+}
+
+// frameSamples returns the samples of a frame as a slice aliasing the frame.
+func frameSamples[F gsp.Frame[T], T gsp.Float](mode Mode, frame *F) []T {
+	switch mode {
+	case ModeMono:
+		return unsafe.Slice((*T)(unsafe.Pointer(frame)), 1)
+	case ModeStereo:
+		return unsafe.Slice((*T)(unsafe.Pointer(frame)), 2)
+	default:
+		return *(*[]T)(unsafe.Pointer(frame))
+	}
+}
+
+// outputSamples returns the samples of an output frame with the given number of channels,
+// allocating multi-channel frames of the wrong length.
+func outputSamples[F gsp.Frame[T], T gsp.Float](mode Mode, frame *F, channels int) []T {
+	if mode == ModeMultiChannel {
+		samples := (*[]T)(unsafe.Pointer(frame))
+		if len(*samples) != channels {
+			*samples = make([]T, channels)
+		}
+
+		return *samples
+	}
+
+	return frameSamples[F, T](mode, frame)
+}
+
+// atomicFloat is a float64 which can be read while it is written by the processing goroutine, used for metering.
+type atomicFloat struct {
+	bits atomic.Uint64
+}
+
+func (f *atomicFloat) Load() float64 {
+	return math.Float64frombits(f.bits.Load())
+}
+
+func (f *atomicFloat) Store(value float64) {
+	f.bits.Store(math.Float64bits(value))
+}
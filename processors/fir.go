@@ -0,0 +1,252 @@
+package processors
+
+import (
+	"unsafe"
+
+	"github.com/samborkent/gsp"
+	"github.com/samborkent/gsp/fft"
+)
+
+var _ gsp.LatencyReporter = &FIR[float32, float32]{}
+
+// firDefaultBlockSize is the default block size of FFT-based block convolution.
+const firDefaultBlockSize = 256
+
+// FIR is a finite impulse response filter with a delay line per channel.
+// Kernels can be designed with the firdesign package.
+// Long kernels can optionally be convolved in blocks with FFTs, see [FIRFFTThreshold].
+type FIR[F gsp.Frame[T], T gsp.Float] struct {
+	taps int
+
+	// Direct convolution.
+	reversed []T   // Kernel in reverse order, such that it can be multiplied with the delay line from oldest to newest sample.
+	history  [][]T // Delay line of twice the number of taps per channel, written twice to avoid wrapping.
+	position int
+
+	// FFT-based block convolution.
+	blockSize int
+	plan      *fft.RealPlan[T]
+	filter    *partitionedFilter[T]
+	stream    *partitionedStream[T]
+
+	channels int
+	mode     Mode
+}
+
+func NewFIR[F gsp.Frame[T], T gsp.Float](kernel []T, options ...FIROption) *FIR[F, T] {
+	if len(kernel) == 0 {
+		panic("gsp: NewFIR: empty kernel")
+	}
+
+	cfg := &FIRConfig{}
+	for _, option := range options {
+		option(cfg)
+	}
+
+	fir := &FIR[F, T]{
+		taps:     len(kernel),
+		reversed: make([]T, len(kernel)),
+	}
+
+	for i := range kernel {
+		fir.reversed[len(kernel)-1-i] = kernel[i]
+	}
+
+	if cfg.FFTThreshold > 0 && len(kernel) >= cfg.FFTThreshold {
+		fir.blockSize = cfg.BlockSize
+		if fir.blockSize <= 0 {
+			fir.blockSize = firDefaultBlockSize
+		}
+
+		fir.plan = fft.NewRealPlan[T](2 * fir.blockSize)
+		fir.filter = newPartitionedFilter(fir.plan, fir.blockSize, kernel)
+	}
+
+	switch any(*new(F)).(type) {
+	case T:
+		fir.mode = ModeMono
+		fir.allocate(1)
+	case [2]T, gsp.Stereo[T]:
+		fir.mode = ModeStereo
+		fir.allocate(2)
+	case []T, gsp.MultiChannel[T]:
+		fir.mode = ModeMultiChannel
+	default:
+		panic("gsp: NewFIR: unknown audio frame type")
+	}
+
+	return fir
+}
+
+// Init allocates the delay lines of multi-channel streams, and clears the filter state.
+func (p *FIR[F, T]) Init(format gsp.Format) {
+	if p.mode == ModeMultiChannel && format.Channels > 0 {
+		p.allocate(format.Channels)
+	}
+
+	p.Reset()
+}
+
+// Latency returns the number of frames by which block convolution delays the output, in addition to the delay of the kernel itself.
+func (p *FIR[F, T]) Latency() int {
+	return p.blockSize
+}
+
+// Reset clears the filter state.
+func (p *FIR[F, T]) Reset() {
+	for c := range p.history {
+		clear(p.history[c])
+	}
+
+	if p.stream != nil {
+		p.stream.reset()
+	}
+
+	p.position = 0
+}
+
+func (p *FIR[F, T]) Process(sample F) F {
+	switch p.mode {
+	case ModeMono:
+		monoSample := *(*T)(unsafe.Pointer(&sample))
+		processedSample := p.filterSample(0, monoSample)
+		p.advance()
+
+		return *(*F)(unsafe.Pointer(&processedSample))
+	case ModeStereo:
+		stereoSample := *(*gsp.Stereo[T])(unsafe.Pointer(&sample))
+		processedSamples := [2]T{
+			p.filterSample(gsp.L, stereoSample.L()),
+			p.filterSample(gsp.R, stereoSample.R()),
+		}
+		p.advance()
+
+		return *(*F)(unsafe.Pointer(&processedSamples))
+	case ModeMultiChannel:
+		multiChannelSample := *(*gsp.MultiChannel[T])(unsafe.Pointer(&sample))
+		p.grow(len(multiChannelSample))
+
+		processedSamples := make([]T, len(multiChannelSample))
+		for i := range multiChannelSample {
+			processedSamples[i] = p.filterSample(i, multiChannelSample[i])
+		}
+		p.advance()
+
+		return *(*F)(unsafe.Pointer(&processedSamples))
+	default:
+		return *new(F)
+	}
+}
+
+func (p *FIR[F, T]) ProcessBuffer(output, input []F) {
+	size := min(len(output), len(input))
+	if size == 0 {
+		return
+	}
+
+	switch p.mode {
+	case ModeMono:
+		samplePtr := (*T)(unsafe.Pointer(&input[0]))
+		monoSamples := unsafe.Slice(samplePtr, len(input))
+
+		for i := range size {
+			processedSample := p.filterSample(0, monoSamples[i])
+			p.advance()
+			output[i] = *(*F)(unsafe.Pointer(&processedSample))
+		}
+	case ModeStereo:
+		samplePtr := (*gsp.Stereo[T])(unsafe.Pointer(&input[0]))
+		stereoSamples := unsafe.Slice(samplePtr, len(input))
+
+		for i := range size {
+			processedSamples := [2]T{
+				p.filterSample(gsp.L, stereoSamples[i].L()),
+				p.filterSample(gsp.R, stereoSamples[i].R()),
+			}
+			p.advance()
+			output[i] = *(*F)(unsafe.Pointer(&processedSamples))
+		}
+	case ModeMultiChannel:
+		samplePtr := (*gsp.MultiChannel[T])(unsafe.Pointer(&input[0]))
+		multiChannelSamples := unsafe.Slice(samplePtr, len(input))
+
+		outputPtr := (*gsp.MultiChannel[T])(unsafe.Pointer(&output[0]))
+		outputSamples := unsafe.Slice(outputPtr, len(output))
+
+		for i := range size {
+			p.grow(len(multiChannelSamples[i]))
+
+			if len(outputSamples[i]) != len(multiChannelSamples[i]) {
+				outputSamples[i] = make(gsp.MultiChannel[T], len(multiChannelSamples[i]))
+			}
+
+			for j := range multiChannelSamples[i] {
+				outputSamples[i][j] = p.filterSample(j, multiChannelSamples[i][j])
+			}
+			p.advance()
+		}
+	}
+}
+
+// filterSample feeds a sample into the delay line of a channel, and returns the filtered sample.
+func (p *FIR[F, T]) filterSample(channel int, sample T) T {
+	if p.stream != nil {
+		return p.stream.exchange(channel, sample)
+	}
+
+	history := p.history[channel]
+	history[p.position] = sample
+	history[p.position+p.taps] = sample
+
+	var sum T
+
+	delayLine := history[p.position+1 : p.position+1+p.taps]
+	for i := range delayLine {
+		sum += delayLine[i] * p.reversed[i]
+	}
+
+	return sum
+}
+
+// advance moves all delay lines one frame further, after all channels of a frame are filtered.
+func (p *FIR[F, T]) advance() {
+	if p.stream != nil {
+		p.stream.advance()
+		return
+	}
+
+	p.position++
+	if p.position == p.taps {
+		p.position = 0
+	}
+}
+
+// grow ensures there are delay lines for the number of channels.
+func (p *FIR[F, T]) grow(channels int) {
+	if p.channels < channels {
+		p.allocate(channels)
+	}
+}
+
+// allocate creates cleared delay lines for the number of channels.
+func (p *FIR[F, T]) allocate(channels int) {
+	p.channels = channels
+	p.position = 0
+
+	if p.filter == nil {
+		p.history = make([][]T, channels)
+		for c := range p.history {
+			p.history[c] = make([]T, 2*p.taps)
+		}
+
+		return
+	}
+
+	filters := make([][]*partitionedFilter[T], channels)
+	for c := range filters {
+		filters[c] = make([]*partitionedFilter[T], channels)
+		filters[c][c] = p.filter
+	}
+
+	p.stream = newPartitionedStream(p.plan, p.blockSize, filters)
+}
@@ -0,0 +1,25 @@
+package processors
+
+// FIROption is a functional option for [FIR].
+type FIROption func(cfg *FIRConfig)
+
+// FIRConfig contains all configuration options for [FIR].
+type FIRConfig struct {
+	FFTThreshold int // Minimum number of taps for which FFT-based block convolution is used, zero disables it.
+	BlockSize    int // Block size of FFT-based block convolution, which is also its latency in frames.
+}
+
+// FIRFFTThreshold enables FFT-based block convolution for kernels of at least the given number of taps.
+// Block convolution is much faster for long kernels, but adds a latency of one block.
+func FIRFFTThreshold(taps int) FIROption {
+	return func(cfg *FIRConfig) {
+		cfg.FFTThreshold = taps
+	}
+}
+
+// FIRBlockSize sets the block size of FFT-based block convolution, which defaults to 256 frames.
+func FIRBlockSize(frames int) FIROption {
+	return func(cfg *FIRConfig) {
+		cfg.BlockSize = frames
+	}
+}
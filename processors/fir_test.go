@@ -0,0 +1,119 @@
+package processors_test
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/samborkent/gsp"
+	"github.com/samborkent/gsp/processors"
+)
+
+// randomSignal returns n deterministic uniformly distributed samples in [-1, 1).
+func randomSignal(n int, seed uint64) []float64 {
+	random := rand.New(rand.NewPCG(seed, seed))
+
+	signal := make([]float64, n)
+	for i := range signal {
+		signal[i] = 2*random.Float64() - 1
+	}
+
+	return signal
+}
+
+// convolve returns the first len(input) samples of the direct convolution of input with kernel.
+func convolve(input, kernel []float64) []float64 {
+	output := make([]float64, len(input))
+
+	for i := range output {
+		for j := range min(len(kernel), i+1) {
+			output[i] += kernel[j] * input[i-j]
+		}
+	}
+
+	return output
+}
+
+func TestFIR(t *testing.T) {
+	t.Parallel()
+
+	const blockSize = 64
+
+	kernel := randomSignal(300, 1)
+	input := randomSignal(2000, 2)
+	want := convolve(input, kernel)
+
+	tests := []struct {
+		name    string
+		options []processors.FIROption
+		latency int
+	}{
+		{"direct", nil, 0},
+		{"below threshold", []processors.FIROption{processors.FIRFFTThreshold(len(kernel) + 1), processors.FIRBlockSize(blockSize)}, 0},
+		{"FFT", []processors.FIROption{processors.FIRFFTThreshold(len(kernel)), processors.FIRBlockSize(blockSize)}, blockSize},
+		{"FFT default block size", []processors.FIROption{processors.FIRFFTThreshold(1)}, 256},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			fir := processors.NewFIR[float64, float64](kernel, test.options...)
+
+			if fir.Latency() != test.latency {
+				t.Fatalf("wrong latency: got '%d', want '%d'", fir.Latency(), test.latency)
+			}
+
+			output := make([]float64, len(input))
+
+			// Buffers which are not a multiple of the block size.
+			for i := 0; i < len(input); i += 100 {
+				fir.ProcessBuffer(output[i:min(i+100, len(input))], input[i:min(i+100, len(input))])
+			}
+
+			for i := range output[:len(output)-test.latency] {
+				if math.Abs(output[i+test.latency]-want[i]) > 1e-9 {
+					t.Fatalf("wrong sample at index '%d': got '%g', want '%g'", i+test.latency, output[i+test.latency], want[i])
+				}
+			}
+
+			// The latency is the only delay, the output starts silent.
+			for i := range test.latency {
+				if math.Abs(output[i]) > 1e-9 {
+					t.Fatalf("wrong sample at index '%d': got '%g', want '%g'", i, output[i], 0.0)
+				}
+			}
+		})
+	}
+}
+
+func TestFIRStereo(t *testing.T) {
+	t.Parallel()
+
+	const blockSize = 32
+
+	kernel := randomSignal(100, 3)
+	left, right := randomSignal(500, 4), randomSignal(500, 5)
+	wantLeft, wantRight := convolve(left, kernel), convolve(right, kernel)
+
+	direct := processors.NewFIR[gsp.Stereo[float64], float64](kernel)
+	block := processors.NewFIR[gsp.Stereo[float64], float64](kernel, processors.FIRFFTThreshold(1), processors.FIRBlockSize(blockSize))
+
+	for i := range left {
+		frame := gsp.ToStereo(left[i], right[i])
+
+		directFrame := direct.Process(frame)
+		if math.Abs(directFrame.L()-wantLeft[i]) > 1e-9 || math.Abs(directFrame.R()-wantRight[i]) > 1e-9 {
+			t.Fatalf("wrong direct frame at index '%d': got '%v', want '%v'", i, directFrame, gsp.ToStereo(wantLeft[i], wantRight[i]))
+		}
+
+		blockFrame := block.Process(frame)
+		if i < blockSize {
+			continue
+		}
+
+		if math.Abs(blockFrame.L()-wantLeft[i-blockSize]) > 1e-9 || math.Abs(blockFrame.R()-wantRight[i-blockSize]) > 1e-9 {
+			t.Fatalf("wrong block frame at index '%d': got '%v', want '%v'", i, blockFrame, gsp.ToStereo(wantLeft[i-blockSize], wantRight[i-blockSize]))
+		}
+	}
+}
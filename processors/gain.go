@@ -6,6 +6,11 @@ import (
 	"github.com/samborkent/gsp"
 )
 
+var (
+	_ gsp.PlanarProcessor[float32] = &Gain[float32, float32]{}
+	_ gsp.InPlaceProcessor         = &Gain[float32, float32]{}
+)
+
 type Gain[F gsp.Frame[T], T gsp.Float] struct {
 	Gain T
 
@@ -48,6 +53,11 @@ func (p *Gain[F, T]) Process(sample F) F {
 	}
 }
 
+// InPlace reports that every frame is read before it is written, so output may alias input.
+func (p *Gain[F, T]) InPlace() bool {
+	return true
+}
+
 func (p *Gain[F, T]) ProcessBuffer(output, input []F) {
 	size := min(len(output), len(input))
 	if size == 0 {
@@ -81,3 +91,12 @@ func (p *Gain[F, T]) ProcessBuffer(output, input []F) {
 		}
 	}
 }
+
+// ProcessPlanar applies the gain to every channel of input.
+func (p *Gain[F, T]) ProcessPlanar(output, input gsp.Planar[T]) {
+	for c := range min(len(output), len(input)) {
+		for i := range min(len(output[c]), len(input[c])) {
+			output[c][i] = input[c][i] * p.Gain
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package processors
+
+import (
+	"math"
+	"time"
+
+	"github.com/samborkent/gsp"
+	"github.com/samborkent/gsp/internal/gmath"
+)
+
+// gatePeakDecay is the decay time constant of the peak detector, which prevents the gate from closing between waveform peaks.
+const gatePeakDecay = 10 * time.Millisecond
+
+// GateConfig contains the parameters of a [Gate].
+type GateConfig[T gsp.Float] struct {
+	Threshold         T                 // Level in dB above which the gate opens.
+	Hysteresis        T                 // Distance in dB below the threshold at which the gate closes again.
+	Hold              time.Duration     // Time the gate stays open after the level falls below the closing level.
+	Attack            time.Duration     // Time constant of opening.
+	Release           time.Duration     // Time constant of closing.
+	Range             T                 // Maximum attenuation in dB as a positive number, zero attenuates completely.
+	Ratio             T                 // Downward expansion ratio of a closed gate, values of at most one give a hard gate.
+	Detection         DynamicsDetection // Level detection, defaults to peak detection.
+	RMSWindow         time.Duration     // Averaging time of RMS detection, defaults to 10 ms.
+	SidechainHighPass T                 // Cutoff frequency in Hz of a high-pass filter in front of the detector, zero disables it.
+}
+
+// Gate is a noise gate and downward expander, which attenuates the signal while its level is below a threshold.
+// The level is detected on the loudest channel, and a single gain is applied to all channels.
+type Gate[F gsp.Frame[T], T gsp.Float] struct {
+	config     GateConfig[T]
+	sampleRate int
+
+	attack, release, rms, decay T // Smoothing coefficients.
+	floor                       T // Linear gain of a closed gate.
+	holdFrames                  int
+
+	highPass biquadCoefficients[T]
+	filters  []biquadState[T] // Sidechain high-pass filter per sidechain channel.
+	envelope []T              // Peak envelope or mean square level per sidechain channel.
+
+	open          bool
+	hold          int
+	gain          T
+	gainReduction atomicFloat
+
+	mode Mode
+}
+
+// NewGate returns a gate with the given configuration.
+// It panics if the hysteresis is negative, or if the sidechain high-pass frequency is not between zero and half the sample rate.
+func NewGate[F gsp.Frame[T], T gsp.Float](sampleRate int, config GateConfig[T]) *Gate[F, T] {
+	if config.Hysteresis < 0 {
+		panic("gsp: NewGate: hysteresis must not be negative")
+	}
+
+	if config.SidechainHighPass != 0 {
+		validateBiquad("NewGate", sampleRate, config.SidechainHighPass, T(math.Sqrt2/2))
+	}
+
+	gate := &Gate[F, T]{
+		config:     config,
+		sampleRate: sampleRate,
+	}
+
+	if gate.config.Detection == "" {
+		gate.config.Detection = DynamicsDetectionPeak
+	}
+
+	if gate.config.RMSWindow <= 0 {
+		gate.config.RMSWindow = dynamicsRMSWindow
+	}
+
+	switch any(*new(F)).(type) {
+	case T:
+		gate.mode = ModeMono
+		gate.grow(1)
+	case [2]T, gsp.Stereo[T]:
+		gate.mode = ModeStereo
+		gate.grow(2)
+	case []T, gsp.MultiChannel[T]:
+		gate.mode = ModeMultiChannel
+	default:
+		panic("gsp: NewGate: unknown audio frame type")
+	}
+
+	gate.Init(gsp.Format{SampleRate: sampleRate})
+
+	return gate
+}
+
+// Init sets the sample rate, redesigns the sidechain filter and clears the detector state.
+// It panics if the sidechain high-pass frequency is not below half the new sample rate.
+func (p *Gate[F, T]) Init(format gsp.Format) {
+	if format.SampleRate > 0 {
+		if p.config.SidechainHighPass != 0 {
+			validateBiquad("Gate.Init", format.SampleRate, p.config.SidechainHighPass, T(math.Sqrt2/2))
+		}
+
+		p.sampleRate = format.SampleRate
+	}
+
+	if format.Channels > 0 {
+		p.grow(format.Channels)
+	}
+
+	p.attack = smoothingCoefficient[T](p.config.Attack, p.sampleRate)
+	p.release = smoothingCoefficient[T](p.config.Release, p.sampleRate)
+	p.rms = smoothingCoefficient[T](p.config.RMSWindow, p.sampleRate)
+	p.decay = smoothingCoefficient[T](gatePeakDecay, p.sampleRate)
+	p.holdFrames = int(p.config.Hold.Seconds() * float64(p.sampleRate))
+
+	p.floor = 0
+	if p.config.Range > 0 {
+		p.floor = gsp.DBToLinear(-p.config.Range)
+	}
+
+	if p.config.SidechainHighPass > 0 {
+		p.highPass = designBiquad(BiquadHighPass, p.sampleRate, p.config.SidechainHighPass, T(math.Sqrt2/2), 0)
+	}
+
+	p.Reset()
+}
+
+// Reset closes the gate, and clears the detector state.
+func (p *Gate[F, T]) Reset() {
+	clear(p.filters)
+	clear(p.envelope)
+
+	p.open = false
+	p.hold = 0
+	p.gain = p.floor
+	p.gainReduction.Store(-float64(levelToDB(p.floor)))
+}
+
+// GainReduction returns the current attenuation in dB, as a positive number.
+// It is safe to call concurrently with processing, which makes it suitable for metering.
+func (p *Gate[F, T]) GainReduction() T {
+	return T(p.gainReduction.Load())
+}
+
+func (p *Gate[F, T]) Process(sample F) F {
+	return p.ProcessSidechain(sample, sample)
+}
+
+// ProcessSidechain gates sample, with the level detected on the sidechain frame.
+func (p *Gate[F, T]) ProcessSidechain(sample, sidechain F) F {
+	var processedSample F
+
+	input := frameSamples[F, T](p.mode, &sample)
+	output := outputSamples[F, T](p.mode, &processedSample, len(input))
+
+	gain := p.processFrame(output, input, frameSamples[F, T](p.mode, &sidechain))
+	p.gainReduction.Store(-float64(levelToDB(gain)))
+
+	return processedSample
+}
+
+func (p *Gate[F, T]) ProcessBuffer(output, input []F) {
+	p.ProcessBufferSidechain(output, input, input)
+}
+
+// ProcessBufferSidechain gates input into output, with the level detected on the sidechain frames.
+// The sidechain may have a different number of channels than the input.
+func (p *Gate[F, T]) ProcessBufferSidechain(output, input, sidechain []F) {
+	size := min(len(output), len(input), len(sidechain))
+	if size == 0 {
+		return
+	}
+
+	var gain T
+
+	for i := range size {
+		inputFrame := frameSamples[F, T](p.mode, &input[i])
+		outputFrame := outputSamples[F, T](p.mode, &output[i], len(inputFrame))
+
+		gain = p.processFrame(outputFrame, inputFrame, frameSamples[F, T](p.mode, &sidechain[i]))
+	}
+
+	p.gainReduction.Store(-float64(levelToDB(gain)))
+}
+
+// processFrame gates a single frame, and returns the applied linear gain.
+func (p *Gate[F, T]) processFrame(output, input, sidechain []T) T {
+	p.grow(len(sidechain))
+
+	level := T(minLevel)
+	for c := range sidechain {
+		level = max(level, p.detect(c, sidechain[c]))
+	}
+
+	switch {
+	case level >= p.config.Threshold:
+		p.open = true
+		p.hold = p.holdFrames
+	case level < p.config.Threshold-p.config.Hysteresis:
+		if p.hold > 0 {
+			p.hold--
+		} else {
+			p.open = false
+		}
+	}
+
+	target := T(1)
+	if !p.open {
+		target = p.floor
+
+		if p.config.Ratio > 1 {
+			// Expand downwards, proportional to the distance below the threshold.
+			target = max(p.floor, gsp.DBToLinear((level-p.config.Threshold)*(p.config.Ratio-1)))
+		}
+	}
+
+	coefficient := p.release
+	if target > p.gain {
+		coefficient = p.attack
+	}
+
+	p.gain = coefficient*p.gain + (1-coefficient)*target
+
+	for c := range input {
+		output[c] = input[c] * p.gain
+	}
+
+	return p.gain
+}
+
+// detect returns the level in dB of a sidechain channel, after the sidechain high-pass filter.
+func (p *Gate[F, T]) detect(channel int, sample T) T {
+	if p.config.SidechainHighPass > 0 {
+		sample = p.filters[channel].process(&p.highPass, sample)
+	}
+
+	if p.config.Detection == DynamicsDetectionRMS {
+		p.envelope[channel] = p.rms*p.envelope[channel] + (1-p.rms)*sample*sample
+		return levelToDB(gmath.Sqrt(p.envelope[channel]))
+	}
+
+	p.envelope[channel] = max(gmath.Abs(sample), p.decay*p.envelope[channel])
+
+	return levelToDB(p.envelope[channel])
+}
+
+// grow ensures there is detector state for the number of sidechain channels.
+func (p *Gate[F, T]) grow(channels int) {
+	if len(p.filters) < channels {
+		p.filters = append(p.filters, make([]biquadState[T], channels-len(p.filters))...)
+		p.envelope = append(p.envelope, make([]T, channels-len(p.envelope))...)
+	}
+}
@@ -0,0 +1,214 @@
+package processors_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/samborkent/gsp"
+	"github.com/samborkent/gsp/processors"
+)
+
+func TestGateHysteresis(t *testing.T) {
+	t.Parallel()
+
+	gate := processors.NewGate[float64, float64](dynamicsSampleRate, processors.GateConfig[float64]{
+		Threshold:  -30,
+		Hysteresis: 10,
+		Range:      60,
+	})
+
+	// The gate opens above the threshold, and only closes again below the threshold minus the hysteresis.
+	steps := []struct {
+		level float64
+		want  float64
+	}{
+		{-35, -60},
+		{-25, 0},
+		{-35, 0},
+		{-45, -60},
+		{-35, -60},
+		{-30, 0},
+	}
+
+	for _, step := range steps {
+		// Long enough for the peak detector to decay to the new level.
+		input := constant(step.level, dynamicsSampleRate/10)
+		output := make([]float64, len(input))
+		gate.ProcessBuffer(output, input)
+
+		if got := gainDB(output[len(output)-1], input[len(input)-1]); math.Abs(got-step.want) > 1e-9 {
+			t.Errorf("wrong gain at '%g' dB: got '%g' dB, want '%g' dB", step.level, got, step.want)
+		}
+	}
+}
+
+func TestGateHold(t *testing.T) {
+	t.Parallel()
+
+	const hold = 10 * time.Millisecond
+
+	// closingFrame returns the index of the first frame at which the gate is closed after a loud signal stops.
+	closingFrame := func(hold time.Duration) int {
+		gate := processors.NewGate[float64, float64](dynamicsSampleRate, processors.GateConfig[float64]{
+			Threshold: -30,
+			Hold:      hold,
+		})
+
+		input := append(constant(0, 100), constant(-60, dynamicsSampleRate/10)...)
+		output := make([]float64, len(input))
+		gate.ProcessBuffer(output, input)
+
+		for i := 100; i < len(output); i++ {
+			if output[i] == 0 {
+				return i
+			}
+		}
+
+		return -1
+	}
+
+	instant, held := closingFrame(0), closingFrame(hold)
+	if instant < 0 || held < 0 {
+		t.Fatalf("gate did not close: got closing frames '%d' and '%d'", instant, held)
+	}
+
+	if want := int(hold.Seconds() * dynamicsSampleRate); held-instant != want {
+		t.Errorf("wrong hold: got '%d' frames, want '%d' frames", held-instant, want)
+	}
+}
+
+func TestGateRange(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		rangeDB   float64
+		ratio     float64
+		level     float64
+		want      float64
+		wantMuted bool
+	}{
+		{"open", 20, 0, -20, 0, false},
+		{"range", 20, 0, -40, -20, false},
+		{"no range", 0, 0, -40, 0, true},
+		{"expander", 60, 2, -40, -10, false},
+		{"steep expander", 60, 3, -40, -20, false},
+		{"expander limited by range", 20, 2, -70, -20, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			gate := processors.NewGate[float64, float64](dynamicsSampleRate, processors.GateConfig[float64]{
+				Threshold: -30,
+				Range:     test.rangeDB,
+				Ratio:     test.ratio,
+			})
+
+			input := constant(test.level, 16)
+			output := make([]float64, len(input))
+			gate.ProcessBuffer(output, input)
+
+			if test.wantMuted {
+				if output[15] != 0 {
+					t.Errorf("wrong sample: got '%g', want '%g'", output[15], 0.0)
+				}
+
+				return
+			}
+
+			if got := gainDB(output[15], input[15]); math.Abs(got-test.want) > 1e-9 {
+				t.Errorf("wrong gain: got '%g' dB, want '%g' dB", got, test.want)
+			}
+
+			if got := gate.GainReduction(); math.Abs(got+test.want) > 1e-9 {
+				t.Errorf("wrong gain reduction: got '%g' dB, want '%g' dB", got, -test.want)
+			}
+		})
+	}
+}
+
+func TestGateSidechain(t *testing.T) {
+	t.Parallel()
+
+	// An offset at -20 dB, which only opens the gate without sidechain filter.
+	offset := constant(-20, dynamicsSampleRate/10)
+
+	tone := make([]float64, len(offset))
+	for i := range tone {
+		tone[i] = gsp.DBToLinear(-20.0) * math.Sin(2*math.Pi*1000*float64(i)/dynamicsSampleRate)
+	}
+
+	tests := []struct {
+		name      string
+		highPass  float64
+		sidechain []float64
+		wantOpen  bool
+	}{
+		{"offset", 0, offset, true},
+		{"offset high-passed", 100, offset, false},
+		{"tone high-passed", 100, tone, true},
+		{"quiet", 0, constant(-60, len(offset)), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			gate := processors.NewGate[float64, float64](dynamicsSampleRate, processors.GateConfig[float64]{
+				Threshold:         -30,
+				SidechainHighPass: test.highPass,
+			})
+
+			// A quiet input is gated by the level of the sidechain.
+			input := constant(-60, len(offset))
+			output := make([]float64, len(input))
+			gate.ProcessBufferSidechain(output, input, test.sidechain)
+
+			if open := output[len(output)-1] != 0; open != test.wantOpen {
+				t.Errorf("wrong gate state: got open '%t', want '%t'", open, test.wantOpen)
+			}
+		})
+	}
+}
+
+func TestGateInvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		sampleRate int
+		config     processors.GateConfig[float64]
+	}{
+		{"negative hysteresis", dynamicsSampleRate, processors.GateConfig[float64]{Hysteresis: -1}},
+		{"zero sample rate", 0, processors.GateConfig[float64]{SidechainHighPass: 100}},
+		{"negative high-pass", dynamicsSampleRate, processors.GateConfig[float64]{SidechainHighPass: -100}},
+		{"high-pass at nyquist", dynamicsSampleRate, processors.GateConfig[float64]{SidechainHighPass: dynamicsSampleRate / 2}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if recover() == nil {
+					t.Errorf("no panic for sample rate '%d' and config '%+v'", test.sampleRate, test.config)
+				}
+			}()
+
+			_ = processors.NewGate[float64, float64](test.sampleRate, test.config)
+		})
+	}
+
+	gate := processors.NewGate[float64, float64](dynamicsSampleRate, processors.GateConfig[float64]{SidechainHighPass: 10000})
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("no panic for sample rate '%d'", 16000)
+		}
+	}()
+
+	gate.Init(gsp.Format{SampleRate: 16000})
+}
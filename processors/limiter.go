@@ -0,0 +1,285 @@
+package processors
+
+import (
+	"time"
+
+	"github.com/samborkent/gsp"
+	"github.com/samborkent/gsp/internal/gmath"
+	"github.com/samborkent/gsp/internal/truepeak"
+)
+
+var _ gsp.LatencyReporter = &Limiter[float32, float32]{}
+
+const (
+	limiterDefaultLookahead = 5 * time.Millisecond
+	limiterDefaultRelease   = 50 * time.Millisecond
+)
+
+// LimiterConfig contains the parameters of a [Limiter].
+type LimiterConfig[T gsp.Float] struct {
+	Ceiling   T             // Maximum output level in dB.
+	Lookahead time.Duration // Time over which the gain is reduced before a peak, defaults to 5 ms.
+	Release   time.Duration // Time constant of the gain recovery after a peak, defaults to 50 ms.
+	TruePeak  bool          // Detect inter-sample peaks by oversampling, such that the ceiling is in dBTP.
+}
+
+// Limiter is a look-ahead brickwall limiter with linked gain across all channels.
+// The input is delayed, such that the gain can be lowered smoothly before a peak arrives.
+// Use [Limiter.Latency] to compensate for the delay.
+//
+// The gain required by each frame is tracked with a sliding minimum over the look-ahead window,
+// which is smoothed by a moving average of the same length.
+// Since every gain inside the moving average already satisfies the frame it is applied to, the output never exceeds the ceiling.
+type Limiter[F gsp.Frame[T], T gsp.Float] struct {
+	config     LimiterConfig[T]
+	sampleRate int
+
+	ceiling   T // Linear ceiling.
+	release   T // Release smoothing coefficient.
+	lookahead int
+	delay     int
+
+	// Delay line per channel.
+	audio         [][]T
+	audioPosition int
+
+	// Sliding minimum of the required gain, as a monotonic queue.
+	minimumGain   []T
+	minimumFrame  []int64
+	head, count   int
+	frame         int64
+	releasedGain  T
+	boxGain       []T
+	boxSum        float64
+	boxPosition   int
+	gainReduction atomicFloat
+
+	detectors []*truepeak.Detector[T]
+	mode      Mode
+}
+
+func NewLimiter[F gsp.Frame[T], T gsp.Float](sampleRate int, config LimiterConfig[T]) *Limiter[F, T] {
+	limiter := &Limiter[F, T]{
+		config:     config,
+		sampleRate: sampleRate,
+	}
+
+	if limiter.config.Lookahead <= 0 {
+		limiter.config.Lookahead = limiterDefaultLookahead
+	}
+
+	if limiter.config.Release <= 0 {
+		limiter.config.Release = limiterDefaultRelease
+	}
+
+	channels := 0
+
+	switch any(*new(F)).(type) {
+	case T:
+		limiter.mode = ModeMono
+		channels = 1
+	case [2]T, gsp.Stereo[T]:
+		limiter.mode = ModeStereo
+		channels = 2
+	case []T, gsp.MultiChannel[T]:
+		limiter.mode = ModeMultiChannel
+	default:
+		panic("gsp: NewLimiter: unknown audio frame type")
+	}
+
+	limiter.Init(gsp.Format{SampleRate: sampleRate, Channels: channels})
+
+	return limiter
+}
+
+// Init sets the sample rate and number of channels, and clears the limiter state.
+func (p *Limiter[F, T]) Init(format gsp.Format) {
+	if format.SampleRate > 0 {
+		p.sampleRate = format.SampleRate
+	}
+
+	p.ceiling = gsp.DBToLinear(p.config.Ceiling)
+	p.release = smoothingCoefficient[T](p.config.Release, p.sampleRate)
+	p.lookahead = max(1, int(p.config.Lookahead.Seconds()*float64(p.sampleRate)))
+
+	p.delay = p.lookahead - 1
+	if p.config.TruePeak {
+		p.delay += truepeak.Delay
+	}
+
+	p.minimumGain = make([]T, p.lookahead)
+	p.minimumFrame = make([]int64, p.lookahead)
+	p.boxGain = make([]T, p.lookahead)
+
+	channels := len(p.audio)
+	if format.Channels > 0 {
+		channels = format.Channels
+	}
+
+	p.allocate(channels)
+}
+
+// Latency returns the number of frames by which the output is delayed.
+func (p *Limiter[F, T]) Latency() int {
+	return p.delay
+}
+
+// Reset clears the delay lines and gain state.
+func (p *Limiter[F, T]) Reset() {
+	for c := range p.audio {
+		clear(p.audio[c])
+		p.detectors[c].Reset()
+	}
+
+	p.audioPosition = 0
+	p.head, p.count = 0, 0
+	p.frame = 0
+	p.releasedGain = 1
+
+	for i := range p.boxGain {
+		p.boxGain[i] = 1
+	}
+
+	p.boxSum = float64(len(p.boxGain))
+	p.boxPosition = 0
+
+	p.gainReduction.Store(0)
+}
+
+// GainReduction returns the current gain reduction in dB, as a positive number.
+// It is safe to call concurrently with processing, which makes it suitable for metering.
+func (p *Limiter[F, T]) GainReduction() T {
+	return T(p.gainReduction.Load())
+}
+
+func (p *Limiter[F, T]) Process(sample F) F {
+	var processedSample F
+
+	input := frameSamples[F, T](p.mode, &sample)
+	output := outputSamples[F, T](p.mode, &processedSample, len(input))
+
+	gain := p.processFrame(output, input)
+	p.gainReduction.Store(-float64(gsp.LinearToDB(gain)))
+
+	return processedSample
+}
+
+func (p *Limiter[F, T]) ProcessBuffer(output, input []F) {
+	size := min(len(output), len(input))
+	if size == 0 {
+		return
+	}
+
+	var gain T
+
+	for i := range size {
+		inputFrame := frameSamples[F, T](p.mode, &input[i])
+		outputFrame := outputSamples[F, T](p.mode, &output[i], len(inputFrame))
+
+		gain = p.processFrame(outputFrame, inputFrame)
+	}
+
+	p.gainReduction.Store(-float64(gsp.LinearToDB(gain)))
+}
+
+// processFrame limits a single frame, and returns the applied gain.
+func (p *Limiter[F, T]) processFrame(output, input []T) T {
+	if len(input) != len(p.audio) {
+		p.allocate(len(input))
+	}
+
+	var peak T
+
+	for c, sample := range input {
+		if p.config.TruePeak {
+			peak = max(peak, p.detectors[c].Process(sample))
+		} else {
+			peak = max(peak, gmath.Abs(sample))
+		}
+	}
+
+	required := T(1)
+	if peak > p.ceiling {
+		required = p.ceiling / peak
+	}
+
+	gain := p.smoothGain(p.slidingMinimum(required))
+
+	for c, sample := range input {
+		delayed := sample
+
+		if p.delay > 0 {
+			delayed = p.audio[c][p.audioPosition]
+			p.audio[c][p.audioPosition] = sample
+		}
+
+		// Guard against rounding errors of the moving average.
+		output[c] = min(max(delayed*gain, -p.ceiling), p.ceiling)
+	}
+
+	if p.delay > 0 {
+		p.audioPosition++
+		if p.audioPosition == p.delay {
+			p.audioPosition = 0
+		}
+	}
+
+	return gain
+}
+
+// slidingMinimum adds the required gain of a frame, and returns the minimum required gain within the look-ahead window.
+func (p *Limiter[F, T]) slidingMinimum(required T) T {
+	size := len(p.minimumGain)
+
+	// Remove the gain at the front once it leaves the window.
+	if p.count > 0 && p.minimumFrame[p.head] <= p.frame-int64(size) {
+		p.head = (p.head + 1) % size
+		p.count--
+	}
+
+	// Remove gains from the back which are not lower than the new gain, since they no longer determine the minimum.
+	for p.count > 0 && p.minimumGain[(p.head+p.count-1)%size] >= required {
+		p.count--
+	}
+
+	tail := (p.head + p.count) % size
+	p.minimumGain[tail] = required
+	p.minimumFrame[tail] = p.frame
+	p.count++
+
+	p.frame++
+
+	return p.minimumGain[p.head]
+}
+
+// smoothGain applies the release to the gain, and smooths it with a moving average over the look-ahead window.
+func (p *Limiter[F, T]) smoothGain(gain T) T {
+	if gain < p.releasedGain {
+		p.releasedGain = gain
+	} else {
+		p.releasedGain = p.release*p.releasedGain + (1-p.release)*gain
+	}
+
+	p.boxSum += float64(p.releasedGain) - float64(p.boxGain[p.boxPosition])
+	p.boxGain[p.boxPosition] = p.releasedGain
+
+	p.boxPosition++
+	if p.boxPosition == len(p.boxGain) {
+		p.boxPosition = 0
+	}
+
+	return T(p.boxSum / float64(len(p.boxGain)))
+}
+
+// allocate creates delay lines and detectors for the number of channels, and clears the limiter state.
+func (p *Limiter[F, T]) allocate(channels int) {
+	p.audio = make([][]T, channels)
+	p.detectors = make([]*truepeak.Detector[T], channels)
+
+	for c := range channels {
+		p.audio[c] = make([]T, p.delay)
+		p.detectors[c] = truepeak.NewDetector[T]()
+	}
+
+	p.Reset()
+}
@@ -0,0 +1,225 @@
+package processors_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/samborkent/gsp"
+	"github.com/samborkent/gsp/firdesign"
+	"github.com/samborkent/gsp/processors"
+	"github.com/samborkent/gsp/window"
+)
+
+// interpolatedPeak returns the absolute peak of signal, evaluated with windowed sinc interpolation at the oversampling factor.
+// It is independent of the true-peak detector of the limiter.
+// The edges of the signal are skipped, since the interpolation is truncated there.
+func interpolatedPeak(signal []float64, oversampling int) float64 {
+	const zeroCrossings = 32
+
+	var peak float64
+
+	for i := zeroCrossings; i < len(signal)-zeroCrossings; i++ {
+		for k := range oversampling {
+			t := float64(i) + float64(k)/float64(oversampling)
+
+			var sum float64
+
+			for m := i - zeroCrossings; m <= i+zeroCrossings; m++ {
+				x := t - float64(m)
+
+				sinc := 1.0
+				if x != 0 {
+					sinc = math.Sin(math.Pi*x) / (math.Pi * x)
+				}
+
+				// Hann window over the interpolation length.
+				w := 0.5 + 0.5*math.Cos(math.Pi*x/(zeroCrossings+1))
+
+				sum += signal[m] * sinc * w
+			}
+
+			peak = max(peak, math.Abs(sum))
+		}
+	}
+
+	return peak
+}
+
+// bandLimitedNoise returns n samples of noise below a quarter of the sample rate, scaled to the given sample peak.
+func bandLimitedNoise(n int, peak float64, seed uint64) []float64 {
+	kernel := firdesign.LowPass(101, 0.2, window.Kaiser[float64](101, firdesign.KaiserBeta(80)))
+	noise := convolve(randomSignal(n, seed), kernel)
+
+	var largest float64
+	for _, sample := range noise {
+		largest = max(largest, math.Abs(sample))
+	}
+
+	for i := range noise {
+		noise[i] *= peak / largest
+	}
+
+	return noise
+}
+
+func TestLimiterCeiling(t *testing.T) {
+	t.Parallel()
+
+	const ceiling = -1.0
+
+	// Noise with overs of up to 12 dB, followed by isolated transients in silence.
+	signal := bandLimitedNoise(dynamicsSampleRate/4, 4, 19)
+	for _, i := range []int{1000, 1003, 1500, 4000} {
+		transient := make([]float64, 1000)
+		transient[i%len(transient)] = 8
+		signal = append(signal, transient...)
+	}
+
+	tests := []struct {
+		name     string
+		truePeak bool
+	}{
+		{"sample peak", false},
+		{"true peak", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			limiter := processors.NewLimiter[gsp.Stereo[float64], float64](dynamicsSampleRate, processors.LimiterConfig[float64]{
+				Ceiling:  ceiling,
+				TruePeak: test.truePeak,
+			})
+
+			// The right channel is a quieter copy, which is reduced by the same gain.
+			input := make([]gsp.Stereo[float64], len(signal))
+			for i := range input {
+				input[i] = gsp.ToStereo(signal[i], signal[i]/2)
+			}
+
+			output := make([]gsp.Stereo[float64], len(input))
+			limiter.ProcessBuffer(output, input)
+
+			left := make([]float64, len(output))
+
+			for i := range output {
+				left[i] = output[i].L()
+
+				if math.Abs(left[i]) > gsp.DBToLinear(ceiling) {
+					t.Fatalf("wrong sample at index '%d': got '%g', want at most '%g'", i, left[i], gsp.DBToLinear(ceiling))
+				}
+
+				// The ceiling is held by the gain, not by clipping, so the channels keep their ratio.
+				if math.Abs(output[i].R()-left[i]/2) > 1e-12 {
+					t.Fatalf("wrong right sample at index '%d': got '%g', want '%g'", i, output[i].R(), left[i]/2)
+				}
+			}
+
+			if !test.truePeak {
+				return
+			}
+
+			// The true-peak detector oversamples by four, which may slightly underestimate the peaks.
+			if peak := gsp.LinearToDB(interpolatedPeak(left, 8)); peak > ceiling+0.1 {
+				t.Errorf("wrong true peak: got '%g' dBTP, want at most '%g' dBTP", peak, ceiling)
+			}
+		})
+	}
+}
+
+func TestLimiterInterSamplePeaks(t *testing.T) {
+	t.Parallel()
+
+	// A sine at a quarter of the sample rate, sampled 45 degrees off its peaks, has sample peaks 3 dB below its true peak.
+	sine := make([]float64, dynamicsSampleRate/10)
+	for i := range sine {
+		sine[i] = math.Sin(math.Pi*float64(i)/2 + math.Pi/4)
+	}
+
+	// Oversampling by four underestimates a peak halfway between samples by about 0.2 dB.
+	tests := []struct {
+		name      string
+		truePeak  bool
+		low, high float64
+	}{
+		{"sample peak", false, -0.05, 0.05},
+		{"true peak", true, -1.05, -0.75},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			limiter := processors.NewLimiter[float64, float64](dynamicsSampleRate, processors.LimiterConfig[float64]{
+				Ceiling:  -1,
+				TruePeak: test.truePeak,
+			})
+
+			output := make([]float64, len(sine))
+			limiter.ProcessBuffer(output, sine)
+
+			// Skip the attack at the start.
+			if peak := gsp.LinearToDB(interpolatedPeak(output[len(output)/2:], 8)); peak < test.low || peak > test.high {
+				t.Errorf("wrong true peak: got '%g' dBTP, want between '%g' and '%g' dBTP", peak, test.low, test.high)
+			}
+		})
+	}
+}
+
+func TestLimiterLatency(t *testing.T) {
+	t.Parallel()
+
+	const lookahead = 2 * time.Millisecond
+
+	lookaheadFrames := int(lookahead.Seconds() * dynamicsSampleRate)
+
+	tests := []struct {
+		name     string
+		truePeak bool
+	}{
+		{"sample peak", false},
+		{"true peak", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			limiter := processors.NewLimiter[float64, float64](dynamicsSampleRate, processors.LimiterConfig[float64]{
+				Ceiling:   -6,
+				Lookahead: lookahead,
+				TruePeak:  test.truePeak,
+			})
+
+			if limiter.Latency() < lookaheadFrames-1 {
+				t.Fatalf("wrong latency: got '%d', want at least '%d'", limiter.Latency(), lookaheadFrames-1)
+			}
+
+			// A transient far above the ceiling is reduced to the ceiling exactly when it leaves the delay line.
+			const at = 100
+
+			input := make([]float64, at+4*lookaheadFrames)
+			input[at] = 1
+
+			output := make([]float64, len(input))
+			limiter.ProcessBuffer(output, input)
+
+			peakIndex := 0
+			for i := range output {
+				if math.Abs(output[i]) > math.Abs(output[peakIndex]) {
+					peakIndex = i
+				}
+			}
+
+			if peakIndex-at != limiter.Latency() {
+				t.Errorf("wrong delay: got '%d', want '%d'", peakIndex-at, limiter.Latency())
+			}
+
+			if want := gsp.DBToLinear(-6.0); test.truePeak && math.Abs(output[peakIndex]) > want || !test.truePeak && math.Abs(output[peakIndex]-want) > 1e-9 {
+				t.Errorf("wrong transient: got '%g', want '%g'", output[peakIndex], want)
+			}
+		})
+	}
+}
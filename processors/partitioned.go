@@ -0,0 +1,179 @@
+package processors
+
+import (
+	"github.com/samborkent/gsp"
+	"github.com/samborkent/gsp/fft"
+)
+
+// partitionedFilter holds the spectra of the uniform partitions of an impulse response.
+type partitionedFilter[T gsp.Float] struct {
+	partitions [][]fft.Complex[T]
+}
+
+// newPartitionedFilter splits the impulse response into partitions of block samples,
+// and transforms each zero-padded partition with a plan of twice the block size.
+func newPartitionedFilter[T gsp.Float](plan *fft.RealPlan[T], block int, ir []T) *partitionedFilter[T] {
+	count := max(1, (len(ir)+block-1)/block)
+	filter := &partitionedFilter[T]{partitions: make([][]fft.Complex[T], count)}
+	padded := make([]T, 2*block)
+
+	for i := range filter.partitions {
+		clear(padded)
+		copy(padded, ir[min(i*block, len(ir)):min((i+1)*block, len(ir))])
+
+		filter.partitions[i] = make([]fft.Complex[T], plan.Bins())
+		plan.Forward(filter.partitions[i], padded)
+	}
+
+	return filter
+}
+
+// partitionedConvolution is a uniformly partitioned overlap-save convolution engine.
+// It convolves each input channel with a matrix of impulse responses, and sums the results per output channel.
+// The spectra of past input blocks are kept in a frequency-domain delay line per input, which is shared by all outputs.
+// Every block of input produces a block of output, so the latency is one block.
+type partitionedConvolution[T gsp.Float] struct {
+	block   int
+	plan    *fft.RealPlan[T]
+	filters [][]*partitionedFilter[T] // Indexed by input then output, nil if the input does not feed the output.
+
+	delayLine [][][]fft.Complex[T] // Indexed by input then partition.
+	head      int
+	history   [][]T // Previous and current input block per input.
+	acc       []fft.Complex[T]
+	signal    []T
+}
+
+func newPartitionedConvolution[T gsp.Float](plan *fft.RealPlan[T], block int, filters [][]*partitionedFilter[T]) *partitionedConvolution[T] {
+	c := &partitionedConvolution[T]{
+		block:     block,
+		plan:      plan,
+		filters:   filters,
+		delayLine: make([][][]fft.Complex[T], len(filters)),
+		history:   make([][]T, len(filters)),
+		acc:       make([]fft.Complex[T], plan.Bins()),
+		signal:    make([]T, 2*block),
+	}
+
+	partitions := 1
+	for _, row := range filters {
+		for _, filter := range row {
+			if filter != nil {
+				partitions = max(partitions, len(filter.partitions))
+			}
+		}
+	}
+
+	for i := range filters {
+		c.history[i] = make([]T, 2*block)
+		c.delayLine[i] = make([][]fft.Complex[T], partitions)
+
+		for p := range c.delayLine[i] {
+			c.delayLine[i][p] = make([]fft.Complex[T], plan.Bins())
+		}
+	}
+
+	return c
+}
+
+func (c *partitionedConvolution[T]) reset() {
+	for i := range c.history {
+		clear(c.history[i])
+
+		for p := range c.delayLine[i] {
+			clear(c.delayLine[i][p])
+		}
+	}
+
+	c.head = 0
+}
+
+// process convolves one block per input into one block per output.
+func (c *partitionedConvolution[T]) process(outputs, inputs [][]T) {
+	partitions := len(c.delayLine[0])
+
+	for i, input := range inputs {
+		history := c.history[i]
+		copy(history, history[c.block:])
+		copy(history[c.block:], input)
+
+		c.plan.Forward(c.delayLine[i][c.head], history)
+	}
+
+	for o, output := range outputs {
+		clear(c.acc)
+
+		for i := range inputs {
+			filter := c.filters[i][o]
+			if filter == nil {
+				continue
+			}
+
+			for p, spectrum := range filter.partitions {
+				x := c.delayLine[i][(c.head-p+partitions)%partitions]
+
+				for k := range c.acc {
+					c.acc[k].Re += x[k].Re*spectrum[k].Re - x[k].Im*spectrum[k].Im
+					c.acc[k].Im += x[k].Re*spectrum[k].Im + x[k].Im*spectrum[k].Re
+				}
+			}
+		}
+
+		// The first half of the inverse transform is corrupted by circular wrap-around, and is discarded.
+		c.plan.Inverse(c.signal, c.acc)
+		copy(output, c.signal[c.block:])
+	}
+
+	c.head = (c.head + 1) % partitions
+}
+
+// partitionedStream feeds a partitioned convolution engine one frame at a time, by buffering a block of input and output per channel.
+// Input channel c is exchanged for output channel c, so the filter matrix must be square.
+type partitionedStream[T gsp.Float] struct {
+	engine        *partitionedConvolution[T]
+	input, output [][]T
+	index         int
+}
+
+func newPartitionedStream[T gsp.Float](plan *fft.RealPlan[T], block int, filters [][]*partitionedFilter[T]) *partitionedStream[T] {
+	s := &partitionedStream[T]{
+		engine: newPartitionedConvolution(plan, block, filters),
+		input:  make([][]T, len(filters)),
+		output: make([][]T, len(filters)),
+	}
+
+	for c := range filters {
+		s.input[c] = make([]T, block)
+		s.output[c] = make([]T, block)
+	}
+
+	return s
+}
+
+func (s *partitionedStream[T]) reset() {
+	for c := range s.input {
+		clear(s.input[c])
+		clear(s.output[c])
+	}
+
+	s.engine.reset()
+	s.index = 0
+}
+
+// exchange buffers an input sample of a channel, and returns the output sample of the previous block.
+func (s *partitionedStream[T]) exchange(channel int, sample T) T {
+	processedSample := s.output[channel][s.index]
+	s.input[channel][s.index] = sample
+
+	return processedSample
+}
+
+// advance moves to the next frame after all channels are exchanged, and convolves the input block once it is full.
+func (s *partitionedStream[T]) advance() {
+	s.index++
+
+	if s.index == s.engine.block {
+		s.engine.process(s.output, s.input)
+		s.index = 0
+	}
+}
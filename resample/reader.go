@@ -0,0 +1,72 @@
+package resample
+
+import (
+	"io"
+
+	"github.com/samborkent/gsp"
+)
+
+// readSize is the number of input frames read from the source per call.
+const readSize = 512
+
+var _ gsp.Reader[float32, float32] = &Reader[float32, float32]{}
+
+// Reader resamples the frames read from a source [gsp.Reader].
+type Reader[F gsp.Frame[T], T gsp.Float] struct {
+	r          gsp.Reader[F, T]
+	resampler  *Resampler[F, T]
+	input      []F
+	start, end int
+	eof        bool
+}
+
+// NewReader returns a [Reader] which reads frames at inputRate from r, and returns them at outputRate.
+func NewReader[F gsp.Frame[T], T gsp.Float](r gsp.Reader[F, T], inputRate, outputRate int, quality Quality) *Reader[F, T] {
+	return &Reader[F, T]{
+		r:         r,
+		resampler: New[F, T](inputRate, outputRate, quality),
+		input:     make([]F, readSize),
+	}
+}
+
+// Read fills buffer with resampled frames.
+// Once the source returns [io.EOF], the filter is drained, after which Read returns [io.EOF].
+func (r *Reader[F, T]) Read(buffer []F) (int, error) {
+	framesWritten := 0
+
+	for framesWritten < len(buffer) {
+		if r.start < r.end {
+			n, m := r.resampler.Process(buffer[framesWritten:], r.input[r.start:r.end])
+			framesWritten += n
+			r.start += m
+
+			continue
+		}
+
+		if !r.eof {
+			n, err := r.r.Read(r.input)
+			r.start, r.end = 0, n
+
+			if err == io.EOF {
+				r.eof = true
+			} else if err != nil {
+				return framesWritten, err
+			}
+
+			continue
+		}
+
+		n, drained := r.resampler.Flush(buffer[framesWritten:])
+		framesWritten += n
+
+		if drained {
+			if framesWritten == 0 {
+				return 0, io.EOF
+			}
+
+			break
+		}
+	}
+
+	return framesWritten, nil
+}
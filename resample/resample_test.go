@@ -0,0 +1,143 @@
+package resample_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/samborkent/gsp"
+	"github.com/samborkent/gsp/resample"
+)
+
+func TestReaderSine(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                  string
+		inputRate, outputRate int
+	}{
+		{"44.1kHz->48kHz", 44100, 48000},
+		{"48kHz->44.1kHz", 48000, 44100},
+		{"48kHz->96kHz", 48000, 96000},
+		{"96kHz->48kHz", 96000, 48000},
+		{"irregular ratio", 44100, 96001},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			N := test.inputRate / 10
+			frequency := 1000.0
+
+			input := make([]float64, N)
+			for i := range N {
+				input[i] = math.Sin(2 * math.Pi * frequency * float64(i) / float64(test.inputRate))
+			}
+
+			reader := resample.NewReader[float64, float64](gsp.NewBuffer[float64, float64](input), test.inputRate, test.outputRate, resample.QualityHigh)
+			output := new(gsp.Buffer[float64, float64])
+
+			_, err := output.ReadFrom(reader)
+			if err != nil {
+				t.Fatalf("resampling: error: %s", err.Error())
+			}
+
+			want := int(math.Ceil(float64(N) * float64(test.outputRate) / float64(test.inputRate)))
+			if output.Len() != want {
+				t.Fatalf("wrong number of frames: got '%d', want '%d'", output.Len(), want)
+			}
+
+			// Skip the edges, where the filter sees the silence around the signal.
+			frames := output.Frames()
+			for i := want / 10; i < want-want/10; i++ {
+				expected := math.Sin(2 * math.Pi * frequency * float64(i) / float64(test.outputRate))
+				if math.Abs(frames[i]-expected) > 1e-3 {
+					t.Fatalf("sample mismatch at index '%d': got '%g', want '%g'", i, frames[i], expected)
+				}
+			}
+		})
+	}
+}
+
+func TestWriterChannels(t *testing.T) {
+	t.Parallel()
+
+	N := 4800
+
+	t.Run("stereo", func(t *testing.T) {
+		t.Parallel()
+
+		input := make([]gsp.Stereo[float32], N)
+		for i := range N {
+			input[i] = gsp.ToStereo(float32(0.5), float32(-0.25))
+		}
+
+		output := new(gsp.Buffer[gsp.Stereo[float32], float32])
+		writer := resample.NewWriter[gsp.Stereo[float32], float32](output, 48000, 32000, resample.QualityMedium)
+
+		_, err := writer.Write(input)
+		if err != nil {
+			t.Fatalf("writing frames: error: %s", err.Error())
+		}
+
+		err = writer.Flush()
+		if err != nil {
+			t.Fatalf("flushing: error: %s", err.Error())
+		}
+
+		if output.Len() != 3200 {
+			t.Fatalf("wrong number of frames: got '%d', want '%d'", output.Len(), 3200)
+		}
+
+		// A constant signal must pass unchanged away from the edges.
+		frame := output.Frames()[1600]
+		if math.Abs(float64(frame.L()-0.5)) > 1e-3 || math.Abs(float64(frame.R()+0.25)) > 1e-3 {
+			t.Errorf("wrong frame: got '%v', want '%v'", frame, gsp.ToStereo(float32(0.5), float32(-0.25)))
+		}
+	})
+
+	t.Run("multi-channel", func(t *testing.T) {
+		t.Parallel()
+
+		channels := 3
+
+		input := make([]gsp.MultiChannel[float64], N)
+		for i := range N {
+			input[i] = gsp.ToMultiChannel(0.1, 0.2, 0.3)
+		}
+
+		var output []gsp.MultiChannel[float64]
+
+		writer := resample.NewWriter[gsp.MultiChannel[float64], float64](writerFunc[gsp.MultiChannel[float64], float64](func(frames []gsp.MultiChannel[float64]) (int, error) {
+			for _, frame := range frames {
+				output = append(output, gsp.ToMultiChannel(frame...))
+			}
+
+			return len(frames), nil
+		}), 48000, 96000, resample.QualityLow)
+
+		_, _ = writer.Write(input)
+		_ = writer.Flush()
+
+		if len(output) != 2*N {
+			t.Fatalf("wrong number of frames: got '%d', want '%d'", len(output), 2*N)
+		}
+
+		frame := output[N]
+		if len(frame) != channels {
+			t.Fatalf("wrong number of channels: got '%d', want '%d'", len(frame), channels)
+		}
+
+		for c := range channels {
+			if math.Abs(frame[c]-input[0][c]) > 1e-3 {
+				t.Errorf("wrong sample in channel '%d': got '%g', want '%g'", c, frame[c], input[0][c])
+			}
+		}
+	})
+}
+
+type writerFunc[F gsp.Frame[T], T gsp.Type] func(frames []F) (int, error)
+
+func (w writerFunc[F, T]) Write(frames []F) (int, error) {
+	return w(frames)
+}
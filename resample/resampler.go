@@ -0,0 +1,312 @@
+// Package resample implements sample-rate conversion with a polyphase windowed-sinc filter.
+package resample
+
+import (
+	"math"
+	"unsafe"
+
+	"github.com/samborkent/gsp"
+)
+
+// Quality selects the filter length and stop-band attenuation of the interpolation filter.
+type Quality int
+
+const (
+	QualityLow    Quality = iota // 16 taps, suitable for previews.
+	QualityMedium                // 32 taps.
+	QualityHigh                  // 64 taps.
+	QualityBest                  // 128 taps, transparent for mastering.
+)
+
+// maxPhases is the maximum number of exactly computed filter phases.
+// Ratios with a larger interpolation factor interpolate between adjacent phases.
+const maxPhases = 1024
+
+type qualityParameters struct {
+	taps    int     // Filter taps per phase, must be even.
+	beta    float64 // Kaiser window shape.
+	rolloff float64 // Cutoff relative to the Nyquist frequency of the lowest sample rate.
+}
+
+func (q Quality) parameters() qualityParameters {
+	switch q {
+	case QualityLow:
+		return qualityParameters{taps: 16, beta: 5, rolloff: 0.85}
+	case QualityMedium:
+		return qualityParameters{taps: 32, beta: 7, rolloff: 0.9}
+	case QualityHigh:
+		return qualityParameters{taps: 64, beta: 9, rolloff: 0.94}
+	case QualityBest:
+		return qualityParameters{taps: 128, beta: 12, rolloff: 0.96}
+	default:
+		panic("gsp: resample: unknown quality")
+	}
+}
+
+// Resampler converts frames from one sample rate to another by a rational ratio.
+// The output is aligned with the input, so the filter delay is compensated.
+type Resampler[F gsp.Frame[T], T gsp.Float] struct {
+	up, down     int // Interpolation and decimation factors of the reduced ratio.
+	taps, phases int
+	coefficients []T // (phases+1) rows of taps coefficients, stored in reversed order.
+
+	channels     int
+	multiChannel bool
+	history      [][]T // Per channel delay line of 2*taps samples, written twice for contiguous access.
+	position     int
+
+	acc  int // Position of the next output relative to the newest input, in units of 1/up input frames.
+	skip int // Number of leading output frames to discard for delay compensation.
+
+	framesRead, framesWritten int64
+	zeros                     []F
+}
+
+// New returns a [Resampler] converting from inputRate to outputRate.
+func New[F gsp.Frame[T], T gsp.Float](inputRate, outputRate int, quality Quality) *Resampler[F, T] {
+	if inputRate <= 0 || outputRate <= 0 {
+		panic("gsp: resample.New: sample rates must be positive")
+	}
+
+	divisor := gcd(inputRate, outputRate)
+	params := quality.parameters()
+
+	r := &Resampler[F, T]{
+		up:     outputRate / divisor,
+		down:   inputRate / divisor,
+		taps:   params.taps,
+		phases: min(outputRate/divisor, maxPhases),
+	}
+
+	switch any(*new(F)).(type) {
+	case T:
+		r.channels = 1
+	case [2]T, gsp.Stereo[T]:
+		r.channels = 2
+	case []T, gsp.MultiChannel[T]:
+		// Channel count is taken from the first input frame.
+		r.multiChannel = true
+	default:
+		panic("gsp: resample.New: unknown audio frame type")
+	}
+
+	// Cut off below the Nyquist frequency of the lowest sample rate.
+	cutoff := params.rolloff * min(1, float64(r.up)/float64(r.down))
+	delay := float64(r.taps / 2)
+	norm := 1 / besselI0(params.beta)
+
+	r.coefficients = make([]T, (r.phases+1)*r.taps)
+	for p := range r.phases + 1 {
+		fraction := float64(p) / float64(r.phases)
+		row := r.coefficients[p*r.taps : (p+1)*r.taps]
+
+		for j := range r.taps {
+			// Distance between the output position and input sample n-j, relative to the filter center.
+			d := float64(j) + fraction - delay
+			x := d / delay
+			window := besselI0(params.beta*math.Sqrt(max(0, 1-x*x))) * norm
+			row[r.taps-1-j] = T(cutoff * sinc(cutoff*d) * window)
+		}
+	}
+
+	r.Reset()
+
+	return r
+}
+
+// Ratio returns the reduced output to input frame ratio.
+func (r *Resampler[F, T]) Ratio() (up, down int) {
+	return r.up, r.down
+}
+
+// Reset clears the filter state, after which the resampler behaves as newly created.
+func (r *Resampler[F, T]) Reset() {
+	for c := range r.history {
+		clear(r.history[c])
+	}
+
+	if r.history == nil && r.channels > 0 {
+		r.allocate()
+	}
+
+	r.position = 0
+	r.framesRead = 0
+	r.framesWritten = 0
+
+	// Place the output grid such that an output frame coincides with the first input frame after the filter delay.
+	delay := r.taps / 2 * r.up
+	offset := delay % r.down
+	r.acc = r.up + offset
+	r.skip = (delay - offset) / r.down
+}
+
+// Process resamples input into output, and returns the number of frames written and read.
+// Processing stops as soon as output is full or input is exhausted, unread input must be passed to the next call.
+func (r *Resampler[F, T]) Process(output, input []F) (framesWritten, framesRead int) {
+	framesWritten, framesRead = r.process(output, input)
+	r.framesRead += int64(framesRead)
+	r.framesWritten += int64(framesWritten)
+
+	return framesWritten, framesRead
+}
+
+// Flush drains the filter into output by feeding silence, until the number of output frames corresponds to all input read.
+// It returns the number of frames written, and whether the resampler is fully drained.
+func (r *Resampler[F, T]) Flush(output []F) (framesWritten int, drained bool) {
+	expected := (r.framesRead*int64(r.up) + int64(r.down) - 1) / int64(r.down)
+
+	if r.zeros == nil {
+		r.zeros = make([]F, r.taps)
+
+		if r.multiChannel {
+			for i := range r.zeros {
+				frame := gsp.ZeroMultiChannel[T](r.channels)
+				r.zeros[i] = *(*F)(unsafe.Pointer(&frame))
+			}
+		}
+	}
+
+	for r.framesWritten < expected && framesWritten < len(output) {
+		limit := min(int64(len(output)-framesWritten), expected-r.framesWritten)
+
+		n, _ := r.process(output[framesWritten:framesWritten+int(limit)], r.zeros)
+		framesWritten += n
+		r.framesWritten += int64(n)
+	}
+
+	return framesWritten, r.framesWritten >= expected
+}
+
+func (r *Resampler[F, T]) process(output, input []F) (framesWritten, framesRead int) {
+	for framesWritten < len(output) {
+		if r.acc >= r.up {
+			if framesRead == len(input) {
+				break
+			}
+
+			r.acc -= r.up
+			r.push(input[framesRead])
+			framesRead++
+
+			continue
+		}
+
+		if r.skip > 0 {
+			r.skip--
+		} else {
+			r.interpolate(&output[framesWritten])
+			framesWritten++
+		}
+
+		r.acc += r.down
+	}
+
+	return framesWritten, framesRead
+}
+
+func (r *Resampler[F, T]) push(frame F) {
+	samples := r.samples(&frame)
+
+	if r.history == nil {
+		r.channels = len(samples)
+		r.allocate()
+	}
+
+	for c := range min(len(samples), r.channels) {
+		r.history[c][r.position] = samples[c]
+		r.history[c][r.position+r.taps] = samples[c]
+	}
+
+	r.position++
+	if r.position == r.taps {
+		r.position = 0
+	}
+}
+
+func (r *Resampler[F, T]) interpolate(frame *F) {
+	if r.multiChannel {
+		samples := (*[]T)(unsafe.Pointer(frame))
+		if len(*samples) != r.channels {
+			*samples = make([]T, r.channels)
+		}
+	}
+
+	samples := r.samples(frame)
+
+	// Fractional phase of the output position.
+	p := r.acc * r.phases / r.up
+	row := r.coefficients[p*r.taps : (p+1)*r.taps]
+
+	if r.phases == r.up {
+		for c := range samples {
+			samples[c] = dot(r.history[c][r.position:r.position+r.taps], row)
+		}
+
+		return
+	}
+
+	// Interpolate linearly between adjacent phases.
+	fraction := T(r.acc*r.phases-p*r.up) / T(r.up)
+	next := r.coefficients[(p+1)*r.taps : (p+2)*r.taps]
+
+	for c := range samples {
+		view := r.history[c][r.position : r.position+r.taps]
+		a, b := dot(view, row), dot(view, next)
+		samples[c] = a + fraction*(b-a)
+	}
+}
+
+// samples returns the samples of a frame as a slice aliasing the frame.
+func (r *Resampler[F, T]) samples(frame *F) []T {
+	if r.multiChannel {
+		return *(*[]T)(unsafe.Pointer(frame))
+	}
+
+	return unsafe.Slice((*T)(unsafe.Pointer(frame)), r.channels)
+}
+
+func (r *Resampler[F, T]) allocate() {
+	r.history = make([][]T, r.channels)
+	for c := range r.history {
+		r.history[c] = make([]T, 2*r.taps)
+	}
+}
+
+func dot[T gsp.Float](x, y []T) T {
+	var sum T
+
+	for i := range x {
+		sum += x[i] * y[i]
+	}
+
+	return sum
+}
+
+func sinc(x float64) float64 {
+	if x == 0 {
+		return 1
+	}
+
+	return math.Sin(math.Pi*x) / (math.Pi * x)
+}
+
+// besselI0 returns the modified Bessel function of the first kind of order zero.
+func besselI0(x float64) float64 {
+	sum, term := 1.0, 1.0
+	halfX := 0.5 * x
+
+	for k := 1; term > 1e-12*sum; k++ {
+		term *= (halfX / float64(k)) * (halfX / float64(k))
+		sum += term
+	}
+
+	return sum
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+
+	return a
+}
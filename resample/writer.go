@@ -0,0 +1,67 @@
+package resample
+
+import (
+	"github.com/samborkent/gsp"
+)
+
+// writeSize is the number of output frames written to the destination per call.
+const writeSize = 512
+
+var _ gsp.Writer[float32, float32] = &Writer[float32, float32]{}
+
+// Writer resamples the frames written to it into a destination [gsp.Writer].
+type Writer[F gsp.Frame[T], T gsp.Float] struct {
+	w         gsp.Writer[F, T]
+	resampler *Resampler[F, T]
+	output    []F
+}
+
+// NewWriter returns a [Writer] which accepts frames at inputRate, and writes them to w at outputRate.
+func NewWriter[F gsp.Frame[T], T gsp.Float](w gsp.Writer[F, T], inputRate, outputRate int, quality Quality) *Writer[F, T] {
+	return &Writer[F, T]{
+		w:         w,
+		resampler: New[F, T](inputRate, outputRate, quality),
+		output:    make([]F, writeSize),
+	}
+}
+
+// Write resamples buffer and writes the result to the destination.
+// Due to the filter delay, the last frames are only written on [Writer.Flush].
+func (w *Writer[F, T]) Write(buffer []F) (int, error) {
+	framesRead := 0
+
+	for framesRead < len(buffer) {
+		n, m := w.resampler.Process(w.output, buffer[framesRead:])
+		framesRead += m
+
+		if n == 0 {
+			continue
+		}
+
+		_, err := w.w.Write(w.output[:n])
+		if err != nil {
+			return framesRead, err
+		}
+	}
+
+	return framesRead, nil
+}
+
+// Flush drains the filter into the destination, after which the writer can be reused for a new stream.
+func (w *Writer[F, T]) Flush() error {
+	for {
+		n, drained := w.resampler.Flush(w.output)
+
+		if n > 0 {
+			_, err := w.w.Write(w.output[:n])
+			if err != nil {
+				return err
+			}
+		}
+
+		if drained {
+			w.resampler.Reset()
+			return nil
+		}
+	}
+}
@@ -0,0 +1,400 @@
+package gsp
+
+import (
+	"errors"
+	"io"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	_ Reader[uint8, uint8] = &RingBuffer[uint8, uint8]{}
+	_ Writer[uint8, uint8] = &RingBuffer[uint8, uint8]{}
+)
+
+var (
+	ErrRingBufferFull   = errors.New("gsp: RingBuffer: full")
+	ErrRingBufferClosed = errors.New("gsp: RingBuffer: closed")
+
+	errRingAborted = errors.New("gsp: ring: wait aborted")
+)
+
+// RingBuffer is a lock-free single-producer, single-consumer queue of frames with a fixed capacity.
+// One goroutine may write while another goroutine reads, without locks or allocations on the audio path.
+//
+// By default, reads return the available frames and writes return [ErrRingBufferFull] if not all frames fit.
+// See [RingBufferBlocking] and [RingBufferOverwrite] for the other policies.
+type RingBuffer[F Frame[T], T Type] struct {
+	ring *ring[F]
+	cfg  RingBufferConfig
+}
+
+// NewRingBuffer returns an empty [RingBuffer] which holds up to capacity frames.
+func NewRingBuffer[F Frame[T], T Type](capacity int, opts ...RingBufferOption) *RingBuffer[F, T] {
+	if capacity <= 0 {
+		panic("gsp: NewRingBuffer: capacity must be positive")
+	}
+
+	var cfg RingBufferConfig
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return &RingBuffer[F, T]{
+		ring: newRing(capacity, copyFrames[F, T]),
+		cfg:  cfg,
+	}
+}
+
+// Read copies the oldest frames into frames, and returns the number of frames read.
+// A blocking ring buffer waits until at least one frame is available.
+// After [RingBuffer.Close], the remaining frames are read before [io.EOF] is returned.
+func (b *RingBuffer[F, T]) Read(frames []F) (int, error) {
+	return b.ring.read(frames, b.cfg.Blocking, nil)
+}
+
+// Write copies frames into the ring buffer, and returns the number of frames written.
+// If the buffer is full, a blocking ring buffer waits for the reader, an overwriting ring buffer discards the oldest frames,
+// and otherwise the write stops with [ErrRingBufferFull].
+// It returns [ErrRingBufferClosed] after [RingBuffer.Close].
+func (b *RingBuffer[F, T]) Write(frames []F) (int, error) {
+	if b.cfg.Overwrite {
+		return b.ring.overwrite(frames)
+	}
+
+	return b.ring.write(frames, b.cfg.Blocking, nil)
+}
+
+// Close stops accepting writes and wakes a blocked reader or writer.
+// It is safe to call from either side, and multiple times.
+func (b *RingBuffer[F, T]) Close() error {
+	b.ring.close()
+	return nil
+}
+
+// Len returns the number of frames available for reading.
+func (b *RingBuffer[F, T]) Len() int {
+	return b.ring.len()
+}
+
+// Cap returns the capacity in frames.
+func (b *RingBuffer[F, T]) Cap() int {
+	return len(b.ring.slots)
+}
+
+// Discarded returns the number of frames which were discarded by overwriting writes.
+func (b *RingBuffer[F, T]) Discarded() int64 {
+	return b.ring.discarded.Load()
+}
+
+// ring is the lock-free single-producer, single-consumer queue underlying [RingBuffer] and the pipelines.
+type ring[E any] struct {
+	slots []E
+	copy  func(dst, src []E) int
+
+	// Monotonic positions of the next element to read and write, the slot of a position is the position modulo the capacity.
+	// The write position is only stored by the producer, the read position by the consumer, and by the producer when it overwrites.
+	readPosition, writePosition atomic.Uint64
+	reading                     atomic.Bool // Set while the consumer copies elements, so an overwriting producer waits before reusing their slots.
+	discarded                   atomic.Int64
+
+	readable, writable chan struct{} // Signalled after every write and read, to wake the other side.
+
+	closed    atomic.Bool
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newRing[E any](capacity int, copy func(dst, src []E) int) *ring[E] {
+	return &ring[E]{
+		slots:    make([]E, capacity),
+		copy:     copy,
+		readable: make(chan struct{}, 1),
+		writable: make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+}
+
+// copySlice is the copy function of rings of elements which do not reference memory of their own.
+func copySlice[E any](dst, src []E) int {
+	return copy(dst, src)
+}
+
+func (r *ring[E]) len() int {
+	return int(r.writePosition.Load() - r.readPosition.Load())
+}
+
+func (r *ring[E]) free() int {
+	return len(r.slots) - r.len()
+}
+
+func (r *ring[E]) close() {
+	r.closeOnce.Do(func() {
+		r.closed.Store(true)
+		close(r.done)
+	})
+}
+
+// drained reports whether the ring is closed and all elements are read.
+func (r *ring[E]) drained() bool {
+	// Elements written before closing are visible once closed is.
+	return r.closed.Load() && r.len() == 0
+}
+
+// put copies as many elements as fit into the ring, and returns the number of elements written.
+func (r *ring[E]) put(elements []E) int {
+	position := r.writePosition.Load()
+
+	n := min(len(elements), len(r.slots)-int(position-r.readPosition.Load()))
+	if n == 0 {
+		return 0
+	}
+
+	r.copyIn(position, elements[:n])
+	r.writePosition.Store(position + uint64(n))
+	signal(r.readable)
+
+	return n
+}
+
+// get copies as many elements as available into elements, and returns the number of elements read.
+func (r *ring[E]) get(elements []E) int {
+	for {
+		r.reading.Store(true)
+
+		position := r.readPosition.Load()
+		n := min(len(elements), int(r.writePosition.Load()-position))
+		r.copyOut(elements[:n], position)
+
+		// Fails if an overwriting producer discarded the elements while they were copied.
+		ok := r.readPosition.CompareAndSwap(position, position+uint64(n))
+		r.reading.Store(false)
+
+		if ok {
+			if n > 0 {
+				signal(r.writable)
+			}
+
+			return n
+		}
+	}
+}
+
+// read copies at least one element into elements if block is set, waiting until elements are available.
+// It returns [io.EOF] once the ring is closed and drained, and errRingAborted if abort is closed while waiting.
+func (r *ring[E]) read(elements []E, block bool, abort <-chan struct{}) (int, error) {
+	for {
+		closed := r.closed.Load()
+
+		n := r.get(elements)
+		if n > 0 || len(elements) == 0 {
+			return n, nil
+		}
+
+		if closed {
+			return 0, io.EOF
+		}
+
+		if !block {
+			return 0, nil
+		}
+
+		err := r.wait(r.readable, abort)
+		if err != nil {
+			return 0, err
+		}
+	}
+}
+
+// write copies elements into the ring, waiting for space if block is set.
+// It returns [ErrRingBufferFull] if not all elements fit without blocking, [ErrRingBufferClosed] once the ring is closed,
+// and errRingAborted if abort is closed while waiting.
+func (r *ring[E]) write(elements []E, block bool, abort <-chan struct{}) (int, error) {
+	written := 0
+
+	for {
+		if r.closed.Load() {
+			return written, ErrRingBufferClosed
+		}
+
+		written += r.put(elements[written:])
+		if written == len(elements) {
+			return written, nil
+		}
+
+		if !block {
+			return written, ErrRingBufferFull
+		}
+
+		err := r.wait(r.writable, abort)
+		if err != nil {
+			return written, err
+		}
+	}
+}
+
+// overwrite copies elements into the ring, discarding the oldest elements to make space.
+// If more elements are written than fit, only the newest are kept.
+func (r *ring[E]) overwrite(elements []E) (int, error) {
+	if r.closed.Load() {
+		return 0, ErrRingBufferClosed
+	}
+
+	written := len(elements)
+
+	if excess := len(elements) - len(r.slots); excess > 0 {
+		r.discarded.Add(int64(excess))
+		elements = elements[excess:]
+	}
+
+	position := r.writePosition.Load()
+
+	for {
+		read := r.readPosition.Load()
+
+		excess := int(position-read) + len(elements) - len(r.slots)
+		if excess <= 0 {
+			break
+		}
+
+		if r.readPosition.CompareAndSwap(read, read+uint64(excess)) {
+			r.discarded.Add(int64(excess))
+
+			// A read which started before the discard may still copy from the slots about to be reused.
+			// Reads which start after it see the new read position, so this waits for at most one copy.
+			for r.reading.Load() {
+				runtime.Gosched()
+			}
+
+			break
+		}
+	}
+
+	r.copyIn(position, elements)
+	r.writePosition.Store(position + uint64(len(elements)))
+	signal(r.readable)
+
+	return written, nil
+}
+
+// wait blocks until the signal is received or the ring is closed.
+func (r *ring[E]) wait(signal <-chan struct{}, abort <-chan struct{}) error {
+	select {
+	case <-signal:
+		return nil
+	case <-r.done:
+		return nil
+	case <-abort:
+		return errRingAborted
+	}
+}
+
+func (r *ring[E]) copyIn(position uint64, elements []E) {
+	slot := int(position % uint64(len(r.slots)))
+	n := r.copy(r.slots[slot:], elements)
+	r.copy(r.slots, elements[n:])
+}
+
+func (r *ring[E]) copyOut(elements []E, position uint64) {
+	slot := int(position % uint64(len(r.slots)))
+	n := r.copy(elements, r.slots[slot:])
+	r.copy(elements[n:], r.slots)
+}
+
+// signal wakes a waiting goroutine without blocking, a pending signal is not repeated.
+func signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// blockQueue transports buffers over rings, keeping the boundaries of the written buffers.
+// The frames of a buffer are written before its size, so a buffer is complete once its size is read.
+type blockQueue[F Frame[T], T Type] struct {
+	frames *ring[F]
+	sizes  *ring[int]
+
+	writeSize, readSize [1]int // Scratch of the producer and the consumer.
+}
+
+func newBlockQueue[F Frame[T], T Type](frames, blocks int) *blockQueue[F, T] {
+	return &blockQueue[F, T]{
+		frames: newRing(frames, copyFrames[F, T]),
+		sizes:  newRing(blocks, copySlice[int]),
+	}
+}
+
+// capacity returns the largest buffer which can be written.
+func (q *blockQueue[F, T]) capacity() int {
+	return len(q.frames.slots)
+}
+
+// put writes buffer as a single block, waiting for space if block is set.
+// Nothing is written until the whole buffer fits, so an aborted put never leaves a partial block.
+// Without blocking, [ErrRingBufferFull] is returned if the buffer does not fit.
+func (q *blockQueue[F, T]) put(buffer []F, block bool, abort <-chan struct{}) error {
+	for q.frames.free() < len(buffer) || q.sizes.free() == 0 {
+		if !block {
+			return ErrRingBufferFull
+		}
+
+		if q.frames.closed.Load() {
+			return ErrRingBufferClosed
+		}
+
+		var err error
+
+		if q.frames.free() < len(buffer) {
+			err = q.frames.wait(q.frames.writable, abort)
+		} else {
+			err = q.sizes.wait(q.sizes.writable, abort)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := q.frames.write(buffer, false, nil)
+	if err != nil {
+		return err
+	}
+
+	q.writeSize[0] = len(buffer)
+
+	_, err = q.sizes.write(q.writeSize[:], false, nil)
+
+	return err
+}
+
+// ready reports whether a complete block can be read without waiting.
+func (q *blockQueue[F, T]) ready() bool {
+	return q.sizes.len() > 0
+}
+
+// next waits for the next block and returns its size, after which its frames must be consumed with take.
+func (q *blockQueue[F, T]) next(abort <-chan struct{}) (int, error) {
+	_, err := q.sizes.read(q.readSize[:], true, abort)
+	if err != nil {
+		return 0, err
+	}
+
+	return q.readSize[0], nil
+}
+
+// take copies the next frames of the current block into buffer.
+func (q *blockQueue[F, T]) take(buffer []F) {
+	q.frames.get(buffer)
+}
+
+func (q *blockQueue[F, T]) close() {
+	q.frames.close()
+	q.sizes.close()
+}
+
+func (q *blockQueue[F, T]) drained() bool {
+	return q.sizes.drained()
+}
@@ -0,0 +1,20 @@
+package gsp
+
+// RingBufferOption is a functional option which configures a [RingBuffer].
+type RingBufferOption func(cfg *RingBufferConfig)
+
+// RingBufferConfig contains all configuration options for [RingBuffer].
+type RingBufferConfig struct {
+	Blocking  bool // Reads wait for at least one frame and writes wait for space, instead of returning early.
+	Overwrite bool // Writes to a full buffer discard the oldest frames, and never wait.
+}
+
+// RingBufferBlocking makes reads wait until frames are available, and writes wait until all frames are written.
+func RingBufferBlocking(cfg *RingBufferConfig) {
+	cfg.Blocking = true
+}
+
+// RingBufferOverwrite makes writes to a full buffer discard the oldest frames, so the newest frames are always kept.
+func RingBufferOverwrite(cfg *RingBufferConfig) {
+	cfg.Overwrite = true
+}
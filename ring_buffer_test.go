@@ -0,0 +1,235 @@
+package gsp_test
+
+import (
+	"errors"
+	"io"
+	"runtime"
+	"testing"
+
+	"github.com/samborkent/gsp"
+)
+
+func TestRingBufferNonBlocking(t *testing.T) {
+	t.Parallel()
+
+	ringBuffer := gsp.NewRingBuffer[int16, int16](4)
+
+	n, err := ringBuffer.Write([]int16{1, 2, 3, 4, 5})
+	if !errors.Is(err, gsp.ErrRingBufferFull) || n != 4 {
+		t.Fatalf("wrong write to full buffer: got '%d' frames with error '%v', want '%d' with error '%v'", n, err, 4, gsp.ErrRingBufferFull)
+	}
+
+	output := make([]int16, 3)
+
+	n, err = ringBuffer.Read(output)
+	if err != nil || n != 3 {
+		t.Fatalf("wrong read: got '%d' frames with error '%v', want '%d'", n, err, 3)
+	}
+
+	// Wrap around the end of the buffer.
+	_, err = ringBuffer.Write([]int16{5, 6, 7})
+	if err != nil {
+		t.Fatalf("writing frames: error: %s", err.Error())
+	}
+
+	if ringBuffer.Len() != 4 {
+		t.Errorf("wrong length: got '%d', want '%d'", ringBuffer.Len(), 4)
+	}
+
+	output = make([]int16, 8)
+
+	n, _ = ringBuffer.Read(output)
+	for i, want := range []int16{4, 5, 6, 7} {
+		if output[i] != want {
+			t.Errorf("wrong frame at index '%d': got '%d', want '%d'", i, output[i], want)
+		}
+	}
+
+	if n != 4 {
+		t.Errorf("wrong number of frames: got '%d', want '%d'", n, 4)
+	}
+
+	// An empty buffer does not block.
+	n, err = ringBuffer.Read(output)
+	if err != nil || n != 0 {
+		t.Errorf("wrong read from empty buffer: got '%d' frames with error '%v', want '%d'", n, err, 0)
+	}
+}
+
+func TestRingBufferOverwrite(t *testing.T) {
+	t.Parallel()
+
+	ringBuffer := gsp.NewRingBuffer[float32, float32](4, gsp.RingBufferOverwrite)
+
+	_, _ = ringBuffer.Write([]float32{1, 2, 3})
+	_, _ = ringBuffer.Write([]float32{4, 5, 6})
+
+	if ringBuffer.Discarded() != 2 {
+		t.Errorf("wrong number of discarded frames: got '%d', want '%d'", ringBuffer.Discarded(), 2)
+	}
+
+	output := make([]float32, 4)
+	_, _ = ringBuffer.Read(output)
+
+	for i, want := range []float32{3, 4, 5, 6} {
+		if output[i] != want {
+			t.Errorf("wrong frame at index '%d': got '%g', want '%g'", i, output[i], want)
+		}
+	}
+
+	// Only the newest frames of a write larger than the capacity are kept.
+	n, err := ringBuffer.Write([]float32{7, 8, 9, 10, 11, 12})
+	if err != nil || n != 6 {
+		t.Fatalf("wrong write: got '%d' frames with error '%v', want '%d'", n, err, 6)
+	}
+
+	_, _ = ringBuffer.Read(output)
+
+	for i, want := range []float32{9, 10, 11, 12} {
+		if output[i] != want {
+			t.Errorf("wrong frame at index '%d': got '%g', want '%g'", i, output[i], want)
+		}
+	}
+
+	if ringBuffer.Discarded() != 4 {
+		t.Errorf("wrong number of discarded frames: got '%d', want '%d'", ringBuffer.Discarded(), 4)
+	}
+}
+
+func TestRingBufferConcurrent(t *testing.T) {
+	t.Parallel()
+
+	N := 100000
+
+	tests := []struct {
+		name string
+		opts []gsp.RingBufferOption
+	}{
+		{"blocking", []gsp.RingBufferOption{gsp.RingBufferBlocking}},
+		{"overwrite", []gsp.RingBufferOption{gsp.RingBufferBlocking, gsp.RingBufferOverwrite}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ringBuffer := gsp.NewRingBuffer[gsp.Stereo[int32], int32](64, test.opts...)
+
+			go func() {
+				input := make([]gsp.Stereo[int32], 0, 37)
+
+				for i := 0; i < N; {
+					input = input[:0]
+					for j := 0; j < cap(input) && i < N; j++ {
+						input = append(input, gsp.ToStereo(int32(i), -int32(i)))
+						i++
+					}
+
+					_, err := ringBuffer.Write(input)
+					if err != nil {
+						t.Errorf("writing frames: error: %s", err.Error())
+						return
+					}
+
+					// Interleave with the reader, which would otherwise only see the last frames when overwriting.
+					runtime.Gosched()
+				}
+
+				_ = ringBuffer.Close()
+			}()
+
+			output := make([]gsp.Stereo[int32], 29)
+			frames, previous := 0, int32(-1)
+
+			for {
+				n, err := ringBuffer.Read(output)
+				if err == io.EOF {
+					break
+				} else if err != nil {
+					t.Fatalf("reading frames: error: %s", err.Error())
+				}
+
+				for _, frame := range output[:n] {
+					// Frames are in order and never torn, only discarded when overwriting.
+					if frame.L() <= previous || frame.R() != -frame.L() {
+						t.Fatalf("wrong frame after '%d': got '%v'", previous, frame)
+					}
+
+					if ringBuffer.Discarded() == 0 && frame.L() != previous+1 {
+						t.Fatalf("missing frame after '%d': got '%v'", previous, frame)
+					}
+
+					previous = frame.L()
+				}
+
+				frames += n
+			}
+
+			if int64(frames)+ringBuffer.Discarded() != int64(N) {
+				t.Errorf("wrong number of frames: got '%d' read and '%d' discarded, want '%d'", frames, ringBuffer.Discarded(), N)
+			}
+		})
+	}
+}
+
+func TestRingBufferClose(t *testing.T) {
+	t.Parallel()
+
+	ringBuffer := gsp.NewRingBuffer[uint8, uint8](8, gsp.RingBufferBlocking)
+
+	_, _ = ringBuffer.Write([]uint8{1, 2})
+	_ = ringBuffer.Close()
+
+	_, err := ringBuffer.Write([]uint8{3})
+	if !errors.Is(err, gsp.ErrRingBufferClosed) {
+		t.Errorf("wrong write error: got '%v', want '%v'", err, gsp.ErrRingBufferClosed)
+	}
+
+	// Frames written before closing are still read.
+	output := make([]uint8, 8)
+
+	n, err := ringBuffer.Read(output)
+	if err != nil || n != 2 {
+		t.Errorf("wrong read: got '%d' frames with error '%v', want '%d'", n, err, 2)
+	}
+
+	_, err = ringBuffer.Read(output)
+	if err != io.EOF {
+		t.Errorf("wrong read error: got '%v', want '%v'", err, io.EOF)
+	}
+}
+
+func TestRingBufferMultiChannel(t *testing.T) {
+	t.Parallel()
+
+	ringBuffer := gsp.NewRingBuffer[gsp.MultiChannel[float64], float64](2)
+
+	frame := gsp.ToMultiChannel(0.1, 0.2, 0.3)
+	_, _ = ringBuffer.Write([]gsp.MultiChannel[float64]{frame})
+
+	// The ring buffer must not retain the written frame.
+	frame[0] = 1
+
+	output := make([]gsp.MultiChannel[float64], 1)
+	_, _ = ringBuffer.Read(output)
+
+	if len(output[0]) != 3 || output[0][0] != 0.1 || output[0][2] != 0.3 {
+		t.Errorf("wrong frame: got '%v', want '%v'", output[0], gsp.ToMultiChannel(0.1, 0.2, 0.3))
+	}
+}
+
+func TestRingBufferAllocations(t *testing.T) {
+	ringBuffer := gsp.NewRingBuffer[gsp.Stereo[float32], float32](1024)
+
+	input := make([]gsp.Stereo[float32], 256)
+	output := make([]gsp.Stereo[float32], 256)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = ringBuffer.Write(input)
+		_, _ = ringBuffer.Read(output)
+	})
+
+	if allocs != 0 {
+		t.Errorf("wrong number of allocations: got '%g', want '%d'", allocs, 0)
+	}
+}
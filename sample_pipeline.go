@@ -3,68 +3,122 @@ package gsp
 import (
 	"context"
 	"runtime"
+	"sync"
 )
 
+// samplePipelineFrames is the capacity in frames of the rings of a [SamplePipeline].
+const samplePipelineFrames = 256
+
+// SamplePipeline processes frames one by one with a chain of sample processors on a separate goroutine.
+// Frames are transported over lock-free rings.
 type SamplePipeline[F Frame[T], T Type] struct {
-	processors []SampleProcessor[F, T]
+	format Format
+
+	input, output *ring[F]
 
-	input, output chan F
+	inputFrame, outputFrame [1]F // Scratch of the writer and the reader.
 
-	initialized bool
+	loop     chan F // Processed frames forwarded by [SamplePipeline.Loop].
+	loopOnce sync.Once
 }
 
-func NewSamplePipeline[F Frame[T], T Type](processors ...SampleProcessor[F, T]) *SamplePipeline[F, T] {
-	if len(processors) == 0 {
-		return &SamplePipeline[F, T]{}
-	}
+// NewSamplePipeline starts a [SamplePipeline] which processes frames with the processors in order.
+// Processors implementing [Initializer] are initialized with the stream format before processing starts, unless the format is the zero value.
+// Without processors, frames are passed through unchanged.
+func NewSamplePipeline[F Frame[T], T Type](format Format, processors ...SampleProcessor[F, T]) *SamplePipeline[F, T] {
+	input := newRing(samplePipelineFrames, copyFrames[F, T])
+	output := newRing(samplePipelineFrames, copyFrames[F, T])
 
 	pipeline := &SamplePipeline[F, T]{
-		processors:  processors,
-		input:       make(chan F, 1),
-		output:      make(chan F, 1),
-		initialized: true,
+		format: format,
+		input:  input,
+		output: output,
+	}
+
+	for _, processor := range processors {
+		initialize(processor, format)
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 
 	runtime.AddCleanup(pipeline, func(_ int) {
 		cancel()
-		close(pipeline.input)
-		close(pipeline.output)
+		input.close()
+		output.close()
 	}, 0)
 
-	go pipeline.run(ctx)
+	go runSamplePipeline(ctx, input, output, processors)
 
 	return pipeline
 }
 
+// Format returns the format the pipeline was created with.
+func (p *SamplePipeline[F, T]) Format() Format {
+	return p.format
+}
+
+// Loop returns a channel of processed frames.
+// The first call starts forwarding processed frames to the channel, after which [SamplePipeline.ReadSample] must not be used.
 func (p *SamplePipeline[F, T]) Loop() <-chan F {
-	return p.output
+	p.loopOnce.Do(func() {
+		p.loop = make(chan F)
+
+		// The goroutine does not reference the pipeline, so the pipeline can be garbage collected while looping.
+		go func(loop chan<- F, output *ring[F]) {
+			defer close(loop)
+
+			for {
+				// A new frame for every receive, as multi-channel samples are copied into it.
+				var frame [1]F
+
+				_, err := output.read(frame[:], true, nil)
+				if err != nil {
+					return
+				}
+
+				loop <- frame[0]
+			}
+		}(p.loop, p.output)
+	})
+
+	return p.loop
 }
 
+// ReadSample reads a single processed frame, blocking until it is available.
 func (p *SamplePipeline[F, T]) ReadSample() F {
-	return <-p.output
+	// Multi-channel samples are copied into a new frame, so returned frames are never reused.
+	p.outputFrame[0] = *new(F)
+	_, _ = p.output.read(p.outputFrame[:], true, nil)
+	return p.outputFrame[0]
 }
 
+// WriteSample writes a single frame, blocking until the pipeline accepts it.
 func (p *SamplePipeline[F, T]) WriteSample(s F) {
-	p.input <- s
+	p.inputFrame[0] = s
+	_, _ = p.input.write(p.inputFrame[:], true, nil)
 }
 
-func (p *SamplePipeline[F, T]) run(ctx context.Context) {
+// runSamplePipeline processes all available input frames at once, until the input is closed or the context is cancelled.
+// It does not reference the pipeline, so the pipeline can be garbage collected.
+func runSamplePipeline[F Frame[T], T Type](ctx context.Context, input, output *ring[F], processors []SampleProcessor[F, T]) {
+	// Process all available frames at once, to reduce the number of wake-ups.
+	buffer := make([]F, samplePipelineFrames)
+
 	for {
-		select {
-		case <-ctx.Done():
+		n, err := input.read(buffer, true, ctx.Done())
+		if err != nil {
 			return
-		case input := <-p.input:
-			p.output <- p.process(input)
 		}
-	}
-}
 
-func (p *SamplePipeline[F, T]) process(sample F) F {
-	for _, processor := range p.processors {
-		sample = processor.Process(sample)
-	}
+		for i := range n {
+			for _, processor := range processors {
+				buffer[i] = processor.Process(buffer[i])
+			}
+		}
 
-	return sample
+		_, err = output.write(buffer[:n], true, ctx.Done())
+		if err != nil {
+			return
+		}
+	}
 }
@@ -0,0 +1,46 @@
+package gsp_test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/samborkent/gsp"
+)
+
+func TestSamplePipeline(t *testing.T) {
+	t.Parallel()
+
+	pipeline := gsp.NewSamplePipeline[float64, float64](gsp.Format{}, sampleFunc(func(x float64) float64 { return 2 * x }), sampleFunc(func(x float64) float64 { return 3 * x }))
+
+	for i := range 10 {
+		pipeline.WriteSample(float64(i))
+
+		if got, want := pipeline.ReadSample(), 6*float64(i); got != want {
+			t.Errorf("wrong sample at index '%d': got '%g', want '%g'", i, got, want)
+		}
+	}
+}
+
+func TestSamplePipelineCleanup(t *testing.T) {
+	t.Parallel()
+
+	// Only the loop is kept, so the pipeline becomes unreachable and its rings are closed by the cleanup.
+	loop := gsp.NewSamplePipeline[float64, float64](gsp.Format{}).Loop()
+
+	timeout := time.After(10 * time.Second)
+
+	for {
+		runtime.GC()
+
+		select {
+		case _, ok := <-loop:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("pipeline was not cleaned up")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
@@ -32,6 +32,11 @@ const (
 	invMaxInt32_32 float32 = 1.0 / maxInt32_32
 )
 
+const (
+	maxInt24_32    float32 = 1<<23 - 1
+	invMaxInt24_32 float32 = 1.0 / maxInt24_32
+)
+
 const (
 	maxInt8_64  float64 = math.MaxInt8
 	maxInt16_64 float64 = math.MaxInt16
@@ -64,3 +69,7 @@ func isSigned[T Type]() bool {
 		return false
 	}
 }
+
+func isFloat[T Type]() bool {
+	return !isSigned[T]() && !isUnsigned[T]()
+}
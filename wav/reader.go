@@ -0,0 +1,201 @@
+package wav
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/samborkent/gsp"
+)
+
+var _ gsp.Reader[int16, int16] = &Reader[int16, int16]{}
+
+// Reader reads the samples of a RIFF/WAVE stream.
+// The sample type T must match the sample format of the stream, no conversion is performed,
+// except for 24-bit PCM streams which are read into int32 or float32 samples.
+type Reader[F gsp.Frame[T], T gsp.Type] struct {
+	header    Header
+	data      io.LimitedReader // Remainder of the data chunk.
+	window    io.LimitedReader // Limits the decoder to the frames requested by a single Read.
+	decoder   *gsp.Decoder[F, T]
+	frameSize int
+}
+
+// NewReader parses the RIFF header and fmt chunk of r, and skips to the start of the data chunk.
+func NewReader[F gsp.Frame[T], T gsp.Type](r io.Reader) (*Reader[F, T], error) {
+	header, dataSize, err := readHeader(r)
+	if err != nil {
+		return nil, fmt.Errorf("wav: NewReader: %w", err)
+	}
+
+	formatTag, bitsPerSample, err := sampleFormat[T]()
+	if err != nil {
+		return nil, fmt.Errorf("wav: NewReader: %w", err)
+	}
+
+	opts := []gsp.EncodingOption{gsp.EncodingChannels(header.Channels), gsp.EncodingSampleRate(header.SampleRate)}
+
+	if header.FormatTag == FormatPCM && header.BitsPerSample == 24 && packed24[T]() {
+		opts = append(opts, gsp.EncodingPacking(gsp.Packing24))
+	} else if header.FormatTag != formatTag || header.BitsPerSample != bitsPerSample {
+		return nil, fmt.Errorf("wav: NewReader: %w: stream has format tag '%d' with '%d' bits per sample", ErrFormatMismatch, header.FormatTag, header.BitsPerSample)
+	}
+
+	if channels := frameChannels[F, T](); channels != 0 && channels != header.Channels {
+		return nil, fmt.Errorf("wav: NewReader: %w: stream has '%d' channels, frame has '%d'", gsp.ErrChannelMismatch, header.Channels, channels)
+	}
+
+	reader := &Reader[F, T]{
+		header:    header,
+		frameSize: header.BlockAlign(),
+	}
+
+	reader.data = io.LimitedReader{R: r, N: dataSize}
+	reader.window = io.LimitedReader{R: &reader.data}
+	reader.decoder = gsp.NewDecoder[F, T](&reader.window, opts...)
+
+	return reader, nil
+}
+
+// Header returns the stream information.
+func (r *Reader[F, T]) Header() Header {
+	return r.header
+}
+
+// Format returns the format of the decoded frames.
+func (r *Reader[F, T]) Format() gsp.Format {
+	return r.decoder.Format()
+}
+
+// Read decodes up to len(buffer) frames from the data chunk.
+// It returns [io.EOF] once the data chunk is exhausted.
+func (r *Reader[F, T]) Read(buffer []F) (int, error) {
+	if len(buffer) == 0 {
+		return 0, nil
+	}
+
+	if r.data.N < int64(r.frameSize) {
+		return 0, io.EOF
+	}
+
+	r.window.N = int64(len(buffer) * r.frameSize)
+	remaining := r.data.N
+
+	err := r.decoder.Decode(buffer)
+	if err != nil {
+		return 0, fmt.Errorf("wav: Reader.Read: %w", err)
+	}
+
+	bytesRead := remaining - r.data.N
+	if bytesRead == 0 {
+		// The underlying reader ended before the end of the data chunk.
+		r.data.N = 0
+		return 0, io.EOF
+	}
+
+	return int(bytesRead / int64(r.frameSize)), nil
+}
+
+// readHeader reads all chunks up to the start of the data chunk and returns the header and data chunk size.
+func readHeader(r io.Reader) (Header, int64, error) {
+	var riff [riffHeaderSize]byte
+
+	_, err := io.ReadFull(r, riff[:])
+	if err != nil {
+		return Header{}, 0, fmt.Errorf("reading RIFF header: %w", err)
+	}
+
+	if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+		return Header{}, 0, ErrNotRIFF
+	}
+
+	var header Header
+	var haveFormat bool
+
+	for {
+		var chunk [chunkHeaderSize]byte
+
+		_, err := io.ReadFull(r, chunk[:])
+		if err != nil {
+			return Header{}, 0, fmt.Errorf("reading chunk header: %w", err)
+		}
+
+		id := string(chunk[0:4])
+		size := binary.LittleEndian.Uint32(chunk[4:8])
+
+		switch id {
+		case "fmt ":
+			header, err = readFormatChunk(r, size)
+			if err != nil {
+				return Header{}, 0, err
+			}
+
+			haveFormat = true
+		case "data":
+			if !haveFormat {
+				return Header{}, 0, ErrMissingFormatChunk
+			}
+
+			if size == unknownSize {
+				// Streamed file without patched sizes, read until the end of the stream.
+				header.DataSize = -1
+				return header, math.MaxInt64, nil
+			}
+
+			header.DataSize = int64(size)
+
+			return header, int64(size), nil
+		default:
+			// Skip unknown chunks including their pad byte.
+			_, err = io.CopyN(io.Discard, r, int64(size)+int64(size&1))
+			if err != nil {
+				return Header{}, 0, fmt.Errorf("skipping '%s' chunk: %w", id, err)
+			}
+		}
+	}
+}
+
+func readFormatChunk(r io.Reader, size uint32) (Header, error) {
+	if size < fmtChunkSize {
+		return Header{}, fmt.Errorf("%w: fmt chunk too small", ErrUnsupportedFormat)
+	}
+
+	data := make([]byte, size+size&1)
+
+	_, err := io.ReadFull(r, data)
+	if err != nil {
+		return Header{}, fmt.Errorf("reading fmt chunk: %w", err)
+	}
+
+	header := Header{
+		FormatTag:     binary.LittleEndian.Uint16(data[0:2]),
+		Channels:      int(binary.LittleEndian.Uint16(data[2:4])),
+		SampleRate:    int(binary.LittleEndian.Uint32(data[4:8])),
+		BitsPerSample: int(binary.LittleEndian.Uint16(data[14:16])),
+	}
+
+	if header.FormatTag == FormatExtensible {
+		if size < fmtExtensibleSize {
+			return Header{}, fmt.Errorf("%w: extensible fmt chunk too small", ErrUnsupportedFormat)
+		}
+
+		header.Extensible = true
+		header.ChannelMask = binary.LittleEndian.Uint32(data[20:24])
+		header.FormatTag = binary.LittleEndian.Uint16(data[24:26])
+
+		if [14]byte(data[26:40]) != subFormatSuffix {
+			return Header{}, fmt.Errorf("%w: unknown sub format", ErrUnsupportedFormat)
+		}
+	}
+
+	if header.FormatTag != FormatPCM && header.FormatTag != FormatIEEEFloat {
+		return Header{}, fmt.Errorf("%w: format tag '%d'", ErrUnsupportedFormat, header.FormatTag)
+	}
+
+	if header.Channels == 0 || header.BitsPerSample == 0 || header.BitsPerSample%8 != 0 {
+		return Header{}, fmt.Errorf("%w: '%d' channels with '%d' bits per sample", ErrUnsupportedFormat, header.Channels, header.BitsPerSample)
+	}
+
+	return header, nil
+}
@@ -0,0 +1,153 @@
+// Package wav implements reading and writing of RIFF/WAVE files on top of [gsp.Decoder] and [gsp.Encoder].
+package wav
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/samborkent/gsp"
+)
+
+// Format tags of the fmt chunk.
+const (
+	FormatPCM        uint16 = 0x0001
+	FormatIEEEFloat  uint16 = 0x0003
+	FormatExtensible uint16 = 0xFFFE
+)
+
+const (
+	riffHeaderSize    = 12 // "RIFF", size, "WAVE"
+	chunkHeaderSize   = 8  // ID, size
+	fmtChunkSize      = 16
+	fmtExtensibleSize = 40
+	extensibleCbSize  = 22
+
+	// unknownSize is written as chunk size by streaming writers which cannot seek back to patch the header.
+	unknownSize = 0xFFFFFFFF
+)
+
+// subFormatSuffix is the GUID suffix shared by all KSDATAFORMAT_SUBTYPE format tags.
+var subFormatSuffix = [14]byte{0x00, 0x00, 0x00, 0x00, 0x10, 0x00, 0x80, 0x00, 0x00, 0xAA, 0x00, 0x38, 0x9B, 0x71}
+
+var (
+	ErrNotRIFF               = errors.New("wav: not a RIFF/WAVE stream")
+	ErrMissingFormatChunk    = errors.New("wav: missing fmt chunk before data chunk")
+	ErrUnsupportedFormat     = errors.New("wav: unsupported sample format")
+	ErrUnsupportedSampleType = errors.New("wav: sample type cannot be represented in a WAVE file")
+	ErrFormatMismatch        = errors.New("wav: sample format does not match sample type")
+	ErrClosed                = errors.New("wav: Writer: closed")
+)
+
+// Header contains the stream information of the fmt and data chunks.
+type Header struct {
+	FormatTag     uint16 // Resolved format tag, [FormatPCM] or [FormatIEEEFloat], also for extensible files.
+	Extensible    bool   // The fmt chunk uses WAVE_FORMAT_EXTENSIBLE.
+	Channels      int
+	SampleRate    int
+	BitsPerSample int
+	ChannelMask   uint32 // Speaker position mask, only set for extensible files.
+	DataSize      int64  // Size of the data chunk in bytes, -1 if unknown.
+}
+
+// BlockAlign returns the size of a single frame in bytes.
+func (h Header) BlockAlign() int {
+	return h.Channels * h.BitsPerSample / 8
+}
+
+// Frames returns the number of frames in the data chunk, -1 if unknown.
+func (h Header) Frames() int64 {
+	if h.DataSize < 0 || h.BlockAlign() == 0 {
+		return -1
+	}
+
+	return h.DataSize / int64(h.BlockAlign())
+}
+
+// sampleFormat returns the format tag and bit size a sample type is stored with.
+func sampleFormat[T gsp.Type]() (formatTag uint16, bitsPerSample int, err error) {
+	switch any(T(0)).(type) {
+	case uint8:
+		return FormatPCM, 8, nil
+	case int16:
+		return FormatPCM, 16, nil
+	case int32:
+		return FormatPCM, 32, nil
+	case float32:
+		return FormatIEEEFloat, 32, nil
+	case float64:
+		return FormatIEEEFloat, 64, nil
+	default:
+		return 0, 0, ErrUnsupportedSampleType
+	}
+}
+
+// packed24 reports whether 24-bit PCM samples can be decoded into and encoded from sample type T.
+func packed24[T gsp.Type]() bool {
+	switch any(T(0)).(type) {
+	case int32, float32:
+		return true
+	default:
+		return false
+	}
+}
+
+// frameChannels returns the number of channels of frame type F, or zero for multi-channel frames.
+func frameChannels[F gsp.Frame[T], T gsp.Type]() int {
+	switch any(*new(F)).(type) {
+	case T:
+		return 1
+	case [2]T, gsp.Stereo[T]:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// defaultChannelMask returns the conventional speaker mask for a channel count.
+func defaultChannelMask(channels int) uint32 {
+	switch channels {
+	case 1:
+		return 0x4 // FC
+	case 2:
+		return 0x3 // FL, FR
+	case 4:
+		return 0x33 // FL, FR, BL, BR
+	case 6:
+		return 0x3F // FL, FR, FC, LFE, BL, BR
+	case 8:
+		return 0x63F // FL, FR, FC, LFE, BL, BR, SL, SR
+	default:
+		return 1<<channels - 1
+	}
+}
+
+// appendFormatChunk appends a complete fmt chunk to b.
+func appendFormatChunk(b []byte, h Header) []byte {
+	blockAlign := h.BlockAlign()
+
+	b = append(b, "fmt "...)
+
+	if h.Extensible {
+		b = binary.LittleEndian.AppendUint32(b, fmtExtensibleSize)
+		b = binary.LittleEndian.AppendUint16(b, FormatExtensible)
+	} else {
+		b = binary.LittleEndian.AppendUint32(b, fmtChunkSize)
+		b = binary.LittleEndian.AppendUint16(b, h.FormatTag)
+	}
+
+	b = binary.LittleEndian.AppendUint16(b, uint16(h.Channels))
+	b = binary.LittleEndian.AppendUint32(b, uint32(h.SampleRate))
+	b = binary.LittleEndian.AppendUint32(b, uint32(h.SampleRate*blockAlign))
+	b = binary.LittleEndian.AppendUint16(b, uint16(blockAlign))
+	b = binary.LittleEndian.AppendUint16(b, uint16(h.BitsPerSample))
+
+	if h.Extensible {
+		b = binary.LittleEndian.AppendUint16(b, extensibleCbSize)
+		b = binary.LittleEndian.AppendUint16(b, uint16(h.BitsPerSample))
+		b = binary.LittleEndian.AppendUint32(b, h.ChannelMask)
+		b = binary.LittleEndian.AppendUint16(b, h.FormatTag)
+		b = append(b, subFormatSuffix[:]...)
+	}
+
+	return b
+}
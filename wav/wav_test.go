@@ -0,0 +1,364 @@
+package wav_test
+
+import (
+	"bytes"
+	"io"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/samborkent/gsp"
+	"github.com/samborkent/gsp/wav"
+)
+
+func TestWriterReaderStereo(t *testing.T) {
+	t.Parallel()
+
+	N := 1001
+
+	input := make([]gsp.Stereo[int16], N)
+	for i := range N {
+		input[i] = gsp.ToStereo(int16(rand.Int32()), int16(rand.Int32()))
+	}
+
+	file, err := os.Create(filepath.Join(t.TempDir(), "stereo.wav"))
+	if err != nil {
+		t.Fatalf("creating file: error: %s", err.Error())
+	}
+	defer file.Close()
+
+	writer, err := wav.NewWriter[gsp.Stereo[int16], int16](file, 44100)
+	if err != nil {
+		t.Fatalf("creating writer: error: %s", err.Error())
+	}
+
+	_, err = writer.Write(input)
+	if err != nil {
+		t.Fatalf("writing frames: error: %s", err.Error())
+	}
+
+	err = writer.Close()
+	if err != nil {
+		t.Fatalf("closing writer: error: %s", err.Error())
+	}
+
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		t.Fatalf("seeking file: error: %s", err.Error())
+	}
+
+	reader, err := wav.NewReader[gsp.Stereo[int16], int16](file)
+	if err != nil {
+		t.Fatalf("creating reader: error: %s", err.Error())
+	}
+
+	header := reader.Header()
+	if header.SampleRate != 44100 || header.Channels != 2 || header.BitsPerSample != 16 || header.Extensible {
+		t.Errorf("wrong header: got '%+v'", header)
+	}
+
+	format := reader.Format()
+	if format.SampleRate != 44100 || format.Channels != 2 || format.SampleType != gsp.SampleTypeInt16 || format.FrameSize() != 4 {
+		t.Errorf("wrong format: got '%+v'", format)
+	}
+
+	if header.Frames() != int64(N) {
+		t.Errorf("wrong number of frames: got '%d', want '%d'", header.Frames(), N)
+	}
+
+	output := readAll(t, reader, 100)
+
+	if len(output) != N {
+		t.Fatalf("missing frames: got '%d', want '%d'", len(output), N)
+	}
+
+	for i := range output {
+		if output[i] != input[i] {
+			t.Errorf("frame mismatch at index '%d': got '%v', want '%v'", i, output[i], input[i])
+		}
+	}
+}
+
+func TestWriterReaderMultiChannelStream(t *testing.T) {
+	t.Parallel()
+
+	N := 257
+	channels := 6
+
+	input := make([]gsp.MultiChannel[float32], N)
+	for i := range N {
+		input[i] = make(gsp.MultiChannel[float32], channels)
+		for j := range channels {
+			input[i][j] = 2*rand.Float32() - 1
+		}
+	}
+
+	// A bytes.Buffer cannot seek, so the sizes remain unknown.
+	buf := new(bytes.Buffer)
+
+	writer, err := wav.NewWriter[gsp.MultiChannel[float32], float32](buf, 48000, wav.WriterChannels(channels))
+	if err != nil {
+		t.Fatalf("creating writer: error: %s", err.Error())
+	}
+
+	_, err = writer.Write(input)
+	if err != nil {
+		t.Fatalf("writing frames: error: %s", err.Error())
+	}
+
+	err = writer.Close()
+	if err != nil {
+		t.Fatalf("closing writer: error: %s", err.Error())
+	}
+
+	reader, err := wav.NewReader[gsp.MultiChannel[float32], float32](buf)
+	if err != nil {
+		t.Fatalf("creating reader: error: %s", err.Error())
+	}
+
+	header := reader.Header()
+	if header.FormatTag != wav.FormatIEEEFloat || !header.Extensible || header.Channels != channels || header.ChannelMask != 0x3F {
+		t.Errorf("wrong header: got '%+v'", header)
+	}
+
+	if header.Frames() != -1 {
+		t.Errorf("wrong number of frames: got '%d', want '%d'", header.Frames(), -1)
+	}
+
+	output := readAll(t, reader, 64)
+
+	if len(output) != N {
+		t.Fatalf("missing frames: got '%d', want '%d'", len(output), N)
+	}
+
+	for i := range output {
+		for j := range channels {
+			if output[i][j] != input[i][j] {
+				t.Errorf("sample mismatch at index '%d', channel '%d': got '%v', want '%v'", i, j, output[i][j], input[i][j])
+			}
+		}
+	}
+}
+
+func TestWriterReader24Bit(t *testing.T) {
+	t.Parallel()
+
+	// An odd number of mono frames makes the data chunk odd sized.
+	N := 101
+
+	input := make([]int32, N)
+	for i := range N {
+		input[i] = rand.Int32() &^ 0xFF
+	}
+
+	file, err := os.Create(filepath.Join(t.TempDir(), "24bit.wav"))
+	if err != nil {
+		t.Fatalf("creating file: error: %s", err.Error())
+	}
+	defer file.Close()
+
+	writer, err := wav.NewWriter[int32, int32](file, 96000, wav.WriterBitsPerSample(24))
+	if err != nil {
+		t.Fatalf("creating writer: error: %s", err.Error())
+	}
+
+	_, err = writer.Write(input)
+	if err != nil {
+		t.Fatalf("writing frames: error: %s", err.Error())
+	}
+
+	err = writer.Close()
+	if err != nil {
+		t.Fatalf("closing writer: error: %s", err.Error())
+	}
+
+	t.Run("int32", func(t *testing.T) {
+		_, err = file.Seek(0, io.SeekStart)
+		if err != nil {
+			t.Fatalf("seeking file: error: %s", err.Error())
+		}
+
+		reader, err := wav.NewReader[int32, int32](file)
+		if err != nil {
+			t.Fatalf("creating reader: error: %s", err.Error())
+		}
+
+		header := reader.Header()
+		if header.FormatTag != wav.FormatPCM || header.BitsPerSample != 24 || header.BlockAlign() != 3 {
+			t.Errorf("wrong header: got '%+v'", header)
+		}
+
+		if size := reader.Format().FrameSize(); size != header.BlockAlign() {
+			t.Errorf("wrong frame size: got '%d', want '%d'", size, header.BlockAlign())
+		}
+
+		if header.Frames() != int64(N) {
+			t.Errorf("wrong number of frames: got '%d', want '%d'", header.Frames(), N)
+		}
+
+		output := readAll(t, reader, 10)
+
+		if len(output) != N {
+			t.Fatalf("missing frames: got '%d', want '%d'", len(output), N)
+		}
+
+		for i := range output {
+			if output[i] != input[i] {
+				t.Errorf("sample mismatch at index '%d': got '%v', want '%v'", i, output[i], input[i])
+			}
+		}
+	})
+
+	t.Run("float32", func(t *testing.T) {
+		_, err = file.Seek(0, io.SeekStart)
+		if err != nil {
+			t.Fatalf("seeking file: error: %s", err.Error())
+		}
+
+		reader, err := wav.NewReader[float32, float32](file)
+		if err != nil {
+			t.Fatalf("creating reader: error: %s", err.Error())
+		}
+
+		output := readAll(t, reader, 10)
+
+		if len(output) != N {
+			t.Fatalf("missing frames: got '%d', want '%d'", len(output), N)
+		}
+
+		for i := range output {
+			want := max(float32(input[i]>>8)/(1<<23-1), -1)
+			if output[i] != want {
+				t.Errorf("sample mismatch at index '%d': got '%v', want '%v'", i, output[i], want)
+			}
+		}
+	})
+
+	_, err = wav.NewWriter[int16, int16](new(bytes.Buffer), 96000, wav.WriterBitsPerSample(24))
+	if err == nil {
+		t.Errorf("expected unsupported sample type error")
+	}
+}
+
+func TestWriterAfterPrefix(t *testing.T) {
+	t.Parallel()
+
+	N := 101
+
+	input := make([]int16, N)
+	for i := range N {
+		input[i] = int16(rand.Int32())
+	}
+
+	file, err := os.Create(filepath.Join(t.TempDir(), "prefix.wav"))
+	if err != nil {
+		t.Fatalf("creating file: error: %s", err.Error())
+	}
+	defer file.Close()
+
+	// The stream is embedded after other data, so the chunk sizes must be patched relative to the header.
+	prefix := []byte("prefix")
+
+	_, err = file.Write(prefix)
+	if err != nil {
+		t.Fatalf("writing prefix: error: %s", err.Error())
+	}
+
+	writer, err := wav.NewWriter[int16, int16](file, 48000)
+	if err != nil {
+		t.Fatalf("creating writer: error: %s", err.Error())
+	}
+
+	_, err = writer.Write(input)
+	if err != nil {
+		t.Fatalf("writing frames: error: %s", err.Error())
+	}
+
+	err = writer.Close()
+	if err != nil {
+		t.Fatalf("closing writer: error: %s", err.Error())
+	}
+
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		t.Fatalf("seeking file: error: %s", err.Error())
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		t.Fatalf("reading file: error: %s", err.Error())
+	}
+
+	if !bytes.HasPrefix(data, prefix) {
+		t.Errorf("wrong prefix: got '%q', want '%q'", data[:len(prefix)], prefix)
+	}
+
+	reader, err := wav.NewReader[int16, int16](bytes.NewReader(data[len(prefix):]))
+	if err != nil {
+		t.Fatalf("creating reader: error: %s", err.Error())
+	}
+
+	if header := reader.Header(); header.Frames() != int64(N) {
+		t.Errorf("wrong number of frames: got '%d', want '%d'", header.Frames(), N)
+	}
+
+	output := readAll(t, reader, 16)
+
+	if len(output) != N {
+		t.Fatalf("missing frames: got '%d', want '%d'", len(output), N)
+	}
+
+	for i := range output {
+		if output[i] != input[i] {
+			t.Errorf("frame mismatch at index '%d': got '%v', want '%v'", i, output[i], input[i])
+		}
+	}
+}
+
+func TestReaderFormatMismatch(t *testing.T) {
+	t.Parallel()
+
+	buf := new(bytes.Buffer)
+
+	writer, err := wav.NewWriter[int16, int16](buf, 8000)
+	if err != nil {
+		t.Fatalf("creating writer: error: %s", err.Error())
+	}
+
+	_ = writer.Close()
+
+	_, err = wav.NewReader[float32, float32](bytes.NewReader(buf.Bytes()))
+	if err == nil {
+		t.Errorf("expected format mismatch error")
+	}
+
+	_, err = wav.NewReader[gsp.Stereo[int16], int16](bytes.NewReader(buf.Bytes()))
+	if err == nil {
+		t.Errorf("expected channel mismatch error")
+	}
+}
+
+func readAll[F gsp.Frame[T], T gsp.Type](t *testing.T, reader *wav.Reader[F, T], size int) []F {
+	t.Helper()
+
+	var output []F
+
+	frames := make([]F, size)
+
+	for {
+		n, err := reader.Read(frames)
+		output = append(output, frames[:n]...)
+
+		if err == io.EOF {
+			return output
+		}
+
+		if err != nil {
+			t.Fatalf("reading frames: error: %s", err.Error())
+		}
+
+		// Multi-channel frames are reused by the decoder.
+		frames = make([]F, size)
+	}
+}
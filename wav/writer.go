@@ -0,0 +1,239 @@
+package wav
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/samborkent/gsp"
+)
+
+var _ gsp.Writer[int16, int16] = &Writer[int16, int16]{}
+
+// WriterOption is a functional option for [NewWriter].
+type WriterOption func(cfg *WriterConfig)
+
+// WriterConfig contains all configuration options for [Writer].
+type WriterConfig struct {
+	Channels      int    // Number of channels, required for multi-channel frames.
+	Extensible    bool   // Force WAVE_FORMAT_EXTENSIBLE, which is always used for more than two channels.
+	ChannelMask   uint32 // Speaker position mask for extensible files, a default mask is used if zero.
+	BitsPerSample int    // Bits per encoded sample, the size of the sample type is used if zero.
+}
+
+// WriterChannels sets the number of channels of multi-channel frames.
+func WriterChannels(channels int) WriterOption {
+	return func(cfg *WriterConfig) {
+		cfg.Channels = channels
+	}
+}
+
+// WriterExtensible forces the use of WAVE_FORMAT_EXTENSIBLE.
+func WriterExtensible(cfg *WriterConfig) {
+	cfg.Extensible = true
+}
+
+// WriterChannelMask sets the speaker position mask, which implies WAVE_FORMAT_EXTENSIBLE.
+func WriterChannelMask(mask uint32) WriterOption {
+	return func(cfg *WriterConfig) {
+		cfg.Extensible = true
+		cfg.ChannelMask = mask
+	}
+}
+
+// WriterBitsPerSample sets the number of bits per encoded sample.
+// Besides the size of the sample type, only 24-bit PCM from int32 or float32 samples is supported.
+func WriterBitsPerSample(bits int) WriterOption {
+	return func(cfg *WriterConfig) {
+		cfg.BitsPerSample = bits
+	}
+}
+
+// Writer writes samples as a RIFF/WAVE stream.
+// If the underlying writer is an [io.WriteSeeker], the chunk sizes are patched on [Writer.Close].
+// Otherwise, the chunk sizes are left as 0xFFFFFFFF, which readers interpret as unknown length.
+// The stream may start anywhere in the underlying writer, offsets are relative to the position of the header.
+type Writer[F gsp.Frame[T], T gsp.Type] struct {
+	w              io.Writer
+	seeker         io.WriteSeeker // Nil if the underlying writer cannot seek.
+	encoder        *gsp.Encoder[F, T]
+	header         Header
+	start          int64 // Position of the RIFF header in the underlying writer.
+	dataSizeOffset int64
+	counter        countingWriter
+	closed         bool
+}
+
+// NewWriter writes the RIFF header and fmt chunk to w and returns a [Writer] for the data chunk.
+func NewWriter[F gsp.Frame[T], T gsp.Type](w io.Writer, sampleRate int, opts ...WriterOption) (*Writer[F, T], error) {
+	// Apply writer options.
+	var cfg WriterConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	formatTag, bitsPerSample, err := sampleFormat[T]()
+	if err != nil {
+		return nil, fmt.Errorf("wav: NewWriter: %w", err)
+	}
+
+	var packing gsp.Packing
+
+	switch {
+	case cfg.BitsPerSample == 0 || cfg.BitsPerSample == bitsPerSample:
+	case cfg.BitsPerSample == 24 && packed24[T]():
+		formatTag, bitsPerSample, packing = FormatPCM, 24, gsp.Packing24
+	default:
+		return nil, fmt.Errorf("wav: NewWriter: %w: '%d' bits per sample", ErrUnsupportedSampleType, cfg.BitsPerSample)
+	}
+
+	channels := frameChannels[F, T]()
+	if channels == 0 {
+		channels = cfg.Channels
+	}
+
+	if channels == 0 {
+		return nil, fmt.Errorf("wav: NewWriter: %w", gsp.ErrUnknownChannelCount)
+	}
+
+	header := Header{
+		FormatTag:     formatTag,
+		Extensible:    cfg.Extensible || channels > 2,
+		Channels:      channels,
+		SampleRate:    sampleRate,
+		BitsPerSample: bitsPerSample,
+		DataSize:      -1,
+	}
+
+	if header.Extensible {
+		header.ChannelMask = cfg.ChannelMask
+		if header.ChannelMask == 0 {
+			header.ChannelMask = defaultChannelMask(channels)
+		}
+	}
+
+	// Sizes are unknown until the writer is closed.
+	b := make([]byte, 0, riffHeaderSize+chunkHeaderSize+fmtExtensibleSize+chunkHeaderSize)
+	b = append(b, "RIFF"...)
+	b = binary.LittleEndian.AppendUint32(b, unknownSize)
+	b = append(b, "WAVE"...)
+	b = appendFormatChunk(b, header)
+	b = append(b, "data"...)
+	dataSizeOffset := len(b)
+	b = binary.LittleEndian.AppendUint32(b, unknownSize)
+
+	writer := &Writer[F, T]{
+		w:              w,
+		header:         header,
+		dataSizeOffset: int64(dataSizeOffset),
+	}
+
+	// Writers which report their position, such as files, can be patched on close.
+	if seeker, ok := w.(io.WriteSeeker); ok {
+		start, err := seeker.Seek(0, io.SeekCurrent)
+		if err == nil {
+			writer.seeker, writer.start = seeker, start
+		}
+	}
+
+	_, err = w.Write(b)
+	if err != nil {
+		return nil, fmt.Errorf("wav: NewWriter: writing header: %w", err)
+	}
+
+	writer.counter.w = w
+	writer.encoder = gsp.NewEncoder[F, T](&writer.counter, gsp.EncodingChannels(channels), gsp.EncodingSampleRate(sampleRate), gsp.EncodingPacking(packing))
+
+	return writer, nil
+}
+
+// Header returns the stream information.
+// The data size is only known after [Writer.Close].
+func (w *Writer[F, T]) Header() Header {
+	return w.header
+}
+
+// Format returns the format of the encoded frames.
+func (w *Writer[F, T]) Format() gsp.Format {
+	return w.encoder.Format()
+}
+
+// Write encodes the frames into the data chunk.
+func (w *Writer[F, T]) Write(buffer []F) (int, error) {
+	if w.closed {
+		return 0, ErrClosed
+	}
+
+	err := w.encoder.Encode(buffer)
+	if err != nil {
+		return 0, fmt.Errorf("wav: Writer.Write: %w", err)
+	}
+
+	return len(buffer), nil
+}
+
+// Close pads the data chunk and patches the chunk sizes if the underlying writer is an [io.WriteSeeker].
+// It does not close the underlying writer.
+func (w *Writer[F, T]) Close() error {
+	if w.closed {
+		return nil
+	}
+
+	w.closed = true
+	w.header.DataSize = w.counter.n
+
+	if w.counter.n%2 != 0 {
+		// Chunks are word aligned.
+		_, err := w.w.Write([]byte{0})
+		if err != nil {
+			return fmt.Errorf("wav: Writer.Close: writing pad byte: %w", err)
+		}
+	}
+
+	if w.seeker == nil {
+		return nil
+	}
+
+	end := w.dataSizeOffset + 4 + w.counter.n + w.counter.n%2
+
+	err := patchUint32(w.seeker, w.start+4, uint32(end-8))
+	if err != nil {
+		return fmt.Errorf("wav: Writer.Close: patching RIFF size: %w", err)
+	}
+
+	err = patchUint32(w.seeker, w.start+w.dataSizeOffset, uint32(w.counter.n))
+	if err != nil {
+		return fmt.Errorf("wav: Writer.Close: patching data size: %w", err)
+	}
+
+	// Continue after the stream, which need not be the end of the underlying writer.
+	_, err = w.seeker.Seek(w.start+end, io.SeekStart)
+	if err != nil {
+		return fmt.Errorf("wav: Writer.Close: seeking to end: %w", err)
+	}
+
+	return nil
+}
+
+func patchUint32(w io.WriteSeeker, offset int64, value uint32) error {
+	_, err := w.Seek(offset, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(binary.LittleEndian.AppendUint32(nil, value))
+
+	return err
+}
+
+// countingWriter counts the number of bytes written to the data chunk.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
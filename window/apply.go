@@ -0,0 +1,72 @@
+package window
+
+import (
+	"github.com/samborkent/gsp"
+)
+
+// Apply multiplies samples by window in place.
+// It panics if the lengths differ.
+func Apply[T gsp.Float](samples, window []T) {
+	if len(samples) != len(window) {
+		panic("gsp: window.Apply: window length does not match sample count")
+	}
+
+	for i := range samples {
+		samples[i] *= window[i]
+	}
+}
+
+// ApplyStereo multiplies both channels of frames by window in place.
+// It panics if the lengths differ.
+func ApplyStereo[T gsp.Float](frames []gsp.Stereo[T], window []T) {
+	if len(frames) != len(window) {
+		panic("gsp: window.ApplyStereo: window length does not match frame count")
+	}
+
+	for i := range frames {
+		frames[i] = frames[i].Multiply(window[i])
+	}
+}
+
+// ApplyMultiChannel multiplies all channels of frames by window in place.
+// It panics if the lengths differ.
+func ApplyMultiChannel[T gsp.Float](frames []gsp.MultiChannel[T], window []T) {
+	if len(frames) != len(window) {
+		panic("gsp: window.ApplyMultiChannel: window length does not match frame count")
+	}
+
+	for i := range frames {
+		frames[i].Multiply(window[i])
+	}
+}
+
+// CoherentGain returns the mean of the window, which is the amplitude gain for a sinusoid centered on a bin.
+// Divide measured amplitudes by the coherent gain to correct for the window.
+func CoherentGain[T gsp.Float](window []T) float64 {
+	if len(window) == 0 {
+		return 0
+	}
+
+	sum := 0.0
+	for _, w := range window {
+		sum += float64(w)
+	}
+
+	return sum / float64(len(window))
+}
+
+// ENBW returns the equivalent noise bandwidth of the window in bins.
+// Divide measured noise power by the ENBW to obtain the noise power per bin.
+func ENBW[T gsp.Float](window []T) float64 {
+	sum, sumSquares := 0.0, 0.0
+	for _, w := range window {
+		sum += float64(w)
+		sumSquares += float64(w) * float64(w)
+	}
+
+	if sum == 0 {
+		return 0
+	}
+
+	return float64(len(window)) * sumSquares / (sum * sum)
+}
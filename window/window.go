@@ -0,0 +1,167 @@
+// Package window generates window functions for spectral analysis and filter design.
+//
+// All windows are symmetric, which is the appropriate form for filter design.
+// Use [Periodic] for the periodic form used in spectral analysis.
+package window
+
+import (
+	"math"
+
+	"github.com/samborkent/gsp"
+)
+
+// Rectangular returns a window of length n with all values set to one.
+func Rectangular[T gsp.Float](n int) []T {
+	w := make([]T, n)
+	for i := range w {
+		w[i] = 1
+	}
+
+	return w
+}
+
+// Hann returns a Hann window of length n.
+func Hann[T gsp.Float](n int) []T {
+	return cosineSum[T](n, 0.5, 0.5)
+}
+
+// Hamming returns a Hamming window of length n.
+func Hamming[T gsp.Float](n int) []T {
+	return cosineSum[T](n, 0.54, 0.46)
+}
+
+// Blackman returns a Blackman window of length n.
+func Blackman[T gsp.Float](n int) []T {
+	return cosineSum[T](n, 0.42, 0.5, 0.08)
+}
+
+// BlackmanHarris returns a 4-term Blackman-Harris window of length n.
+func BlackmanHarris[T gsp.Float](n int) []T {
+	return cosineSum[T](n, 0.35875, 0.48829, 0.14128, 0.01168)
+}
+
+// Nuttall returns a 4-term Nuttall window with continuous first derivative of length n.
+func Nuttall[T gsp.Float](n int) []T {
+	return cosineSum[T](n, 0.355768, 0.487396, 0.144232, 0.012604)
+}
+
+// FlatTop returns a flat-top window of length n.
+// Its main lobe is wide and flat, which minimizes the amplitude error of sinusoids between bins.
+func FlatTop[T gsp.Float](n int) []T {
+	return cosineSum[T](n, 0.21557895, 0.41663158, 0.277263158, 0.083578947, 0.006947368)
+}
+
+// Kaiser returns a Kaiser window of length n.
+// Beta trades main lobe width for side lobe level, a beta of zero gives a rectangular window.
+func Kaiser[T gsp.Float](n int, beta float64) []T {
+	w := make([]T, n)
+	if n == 1 {
+		w[0] = 1
+		return w
+	}
+
+	norm := 1 / besselI0(beta)
+	for i := range w {
+		x := 2*float64(i)/float64(n-1) - 1
+		w[i] = T(besselI0(beta*math.Sqrt(max(0, 1-x*x))) * norm)
+	}
+
+	return w
+}
+
+// Tukey returns a Tukey (tapered cosine) window of length n.
+// Alpha is the fraction of the window inside the cosine tapers,
+// an alpha of zero gives a rectangular window, and an alpha of one gives a Hann window.
+func Tukey[T gsp.Float](n int, alpha float64) []T {
+	if alpha <= 0 {
+		return Rectangular[T](n)
+	} else if alpha >= 1 {
+		return Hann[T](n)
+	}
+
+	w := make([]T, n)
+	if n == 1 {
+		w[0] = 1
+		return w
+	}
+
+	taper := alpha * float64(n-1) / 2
+	for i := range w {
+		// Distance to the nearest window edge.
+		x := float64(min(i, n-1-i))
+		if x < taper {
+			w[i] = T(0.5 - 0.5*math.Cos(math.Pi*x/taper))
+		} else {
+			w[i] = 1
+		}
+	}
+
+	return w
+}
+
+// Gaussian returns a Gaussian window of length n.
+// Sigma is the standard deviation relative to half the window length, and is typically at most 0.5.
+// It panics if sigma is not positive.
+func Gaussian[T gsp.Float](n int, sigma float64) []T {
+	if !(sigma > 0) {
+		panic("gsp: window.Gaussian: sigma must be positive")
+	}
+
+	w := make([]T, n)
+	if n == 1 {
+		w[0] = 1
+		return w
+	}
+
+	half := float64(n-1) / 2
+	for i := range w {
+		x := (float64(i) - half) / (sigma * half)
+		w[i] = T(math.Exp(-0.5 * x * x))
+	}
+
+	return w
+}
+
+// Periodic returns the periodic form of a window of length n, by generating a symmetric window of length n+1 and dropping the last value.
+// Periodic windows satisfy the constant overlap-add constraint exactly, which is why they are used for spectral analysis.
+//
+//	w := window.Periodic(window.Hann[float32], 1024)
+func Periodic[T gsp.Float](window func(n int) []T, n int) []T {
+	return window(n + 1)[:n]
+}
+
+// cosineSum returns a generalized cosine window with the given coefficients of alternating sign.
+func cosineSum[T gsp.Float](n int, coefficients ...float64) []T {
+	w := make([]T, n)
+	if n == 1 {
+		w[0] = 1
+		return w
+	}
+
+	for i := range w {
+		phase := 2 * math.Pi * float64(i) / float64(n-1)
+		sum, sign := 0.0, 1.0
+
+		for k, a := range coefficients {
+			sum += sign * a * math.Cos(float64(k)*phase)
+			sign = -sign
+		}
+
+		w[i] = T(sum)
+	}
+
+	return w
+}
+
+// besselI0 returns the modified Bessel function of the first kind of order zero.
+func besselI0(x float64) float64 {
+	sum, term := 1.0, 1.0
+	halfX := 0.5 * x
+
+	for k := 1; term > 1e-12*sum; k++ {
+		term *= (halfX / float64(k)) * (halfX / float64(k))
+		sum += term
+	}
+
+	return sum
+}
@@ -0,0 +1,134 @@
+package window_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/samborkent/gsp"
+	"github.com/samborkent/gsp/window"
+)
+
+func TestWindowGain(t *testing.T) {
+	t.Parallel()
+
+	const n = 4096
+
+	tests := []struct {
+		name         string
+		window       []float64
+		coherentGain float64
+		enbw         float64
+	}{
+		{"rectangular", window.Rectangular[float64](n), 1, 1},
+		{"hann", window.Periodic(window.Hann[float64], n), 0.5, 1.5},
+		{"hamming", window.Periodic(window.Hamming[float64], n), 0.54, 1.3628},
+		{"blackman", window.Periodic(window.Blackman[float64], n), 0.42, 1.7268},
+		{"blackman-harris", window.Periodic(window.BlackmanHarris[float64], n), 0.35875, 2.0044},
+		{"nuttall", window.Periodic(window.Nuttall[float64], n), 0.355768, 2.0212},
+		{"flat-top", window.Periodic(window.FlatTop[float64], n), 0.21557895, 3.7702},
+		{"kaiser beta 0", window.Kaiser[float64](n, 0), 1, 1},
+		{"tukey alpha 0", window.Tukey[float64](n, 0), 1, 1},
+		{"tukey alpha 0.5", window.Tukey[float64](n, 0.5), 0.75, 1.2222},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := window.CoherentGain(test.window); math.Abs(got-test.coherentGain) > 1e-3 {
+				t.Errorf("wrong coherent gain: got '%g', want '%g'", got, test.coherentGain)
+			}
+
+			if got := window.ENBW(test.window); math.Abs(got-test.enbw) > 1e-3 {
+				t.Errorf("wrong ENBW: got '%g', want '%g'", got, test.enbw)
+			}
+		})
+	}
+}
+
+func TestWindowShape(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		window func(n int) []float32
+	}{
+		{"hann", window.Hann[float32]},
+		{"hamming", window.Hamming[float32]},
+		{"blackman", window.Blackman[float32]},
+		{"blackman-harris", window.BlackmanHarris[float32]},
+		{"nuttall", window.Nuttall[float32]},
+		{"flat-top", window.FlatTop[float32]},
+		{"kaiser", func(n int) []float32 { return window.Kaiser[float32](n, 8) }},
+		{"tukey", func(n int) []float32 { return window.Tukey[float32](n, 0.5) }},
+		{"gaussian", func(n int) []float32 { return window.Gaussian[float32](n, 0.4) }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			for _, n := range []int{1, 2, 63, 64} {
+				w := test.window(n)
+				if len(w) != n {
+					t.Fatalf("wrong length: got '%d', want '%d'", len(w), n)
+				}
+
+				for i := range w {
+					if math.Abs(float64(w[i]-w[n-1-i])) > 1e-6 {
+						t.Fatalf("window is not symmetric at index '%d': got '%g', want '%g'", i, w[i], w[n-1-i])
+					}
+				}
+
+				if n%2 == 1 && math.Abs(float64(w[n/2])-1) > 1e-6 {
+					t.Fatalf("wrong center value: got '%g', want '%g'", w[n/2], 1.0)
+				}
+			}
+		})
+	}
+}
+
+func TestGaussianInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, sigma := range []float64{0, -0.4, math.NaN()} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("no panic for sigma '%g'", sigma)
+				}
+			}()
+
+			_ = window.Gaussian[float64](16, sigma)
+		}()
+	}
+}
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	w := []float32{0, 0.5, 1}
+
+	mono := []float32{2, 2, 2}
+	window.Apply(mono, w)
+
+	stereo := []gsp.Stereo[float32]{{2, -2}, {2, -2}, {2, -2}}
+	window.ApplyStereo(stereo, w)
+
+	multiChannel := []gsp.MultiChannel[float32]{{2, -2, 4}, {2, -2, 4}, {2, -2, 4}}
+	window.ApplyMultiChannel(multiChannel, w)
+
+	for i := range w {
+		if mono[i] != 2*w[i] {
+			t.Errorf("wrong mono sample at index '%d': got '%g', want '%g'", i, mono[i], 2*w[i])
+		}
+
+		if stereo[i] != (gsp.Stereo[float32]{2 * w[i], -2 * w[i]}) {
+			t.Errorf("wrong stereo frame at index '%d': got '%v', want '%v'", i, stereo[i], gsp.Stereo[float32]{2 * w[i], -2 * w[i]})
+		}
+
+		if multiChannel[i][2] != 4*w[i] {
+			t.Errorf("wrong multi-channel sample at index '%d': got '%g', want '%g'", i, multiChannel[i][2], 4*w[i])
+		}
+	}
+}